@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/ctlog"
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/orchestrator"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ctlogDomains       []string
+	ctlogDomainList    string
+	ctlogLogs          []string
+	ctlogOnce          bool
+	ctlogFromBeginning bool
+)
+
+var ctlogCmd = &cobra.Command{
+	Use:   "ctlog",
+	Short: "Follow Certificate Transparency logs for new subdomains",
+	Long: `Follow RFC 6962 Certificate Transparency logs and track the certificate names that fall
+under the watched domains as soon as they are logged. New hosts go through tracking,
+Elasticsearch and notifications like scan results, recorded as scans of the "ctlog"
+tool; they never mark other hosts DEAD.
+
+The position of every log is stored in the tracking database, so a restarted follower
+resumes where it stopped. A log that was never read starts at its current end, or at
+entry 0 with --from-beginning (ctlog.start: beginning).`,
+	Example: `  samoscout ctlog -d example.com --log https://ct.googleapis.com/logs/us1/argon2026h1/
+  samoscout ctlog --dL domains.txt --once
+  samoscout ctlog status`,
+	Args: cobra.NoArgs,
+	Run:  runCTLog,
+}
+
+var ctlogStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show how far each configured log has been read",
+	Args:  cobra.NoArgs,
+	Run:   runCTLogStatus,
+}
+
+func init() {
+	ctlogCmd.PersistentFlags().StringSliceVar(&ctlogLogs, "log", nil, "CT log URL (repeatable, adds to ctlog.logs)")
+	ctlogCmd.Flags().StringSliceVarP(&ctlogDomains, "domain", "d", nil, "watched domain (repeatable, adds to ctlog.domains)")
+	ctlogCmd.Flags().StringVar(&ctlogDomainList, "dL", "", "file containing watched domains")
+	ctlogCmd.Flags().BoolVar(&ctlogOnce, "once", false, "read every log up to its current size and exit")
+	ctlogCmd.Flags().BoolVar(&ctlogFromBeginning, "from-beginning", false, "start logs that were never read at entry 0")
+	ctlogCmd.Flags().StringVar(&metricsListen, "metrics-listen", "", "serve Prometheus metrics on this address (default: metrics.listen)")
+	ctlogCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose/debug output")
+
+	ctlogCmd.AddCommand(ctlogStatusCmd)
+	rootCmd.AddCommand(ctlogCmd)
+}
+
+func ctlogSources(cfg config.CTLog) []ctlog.Log {
+	var logs []ctlog.Log
+	for _, l := range cfg.Logs {
+		if l.URL != "" {
+			logs = append(logs, ctlog.Log{Name: l.Name, URL: l.URL})
+		}
+	}
+	for _, url := range ctlogLogs {
+		logs = append(logs, ctlog.Log{URL: url})
+	}
+	return logs
+}
+
+func openCTLogDB() (*orchestrator.Orchestrator, *database.DB) {
+	orch, err := orchestrator.NewOrchestrator(configFile)
+	if err != nil {
+		color.Red("Failed to initialize orchestrator: %v", err)
+		os.Exit(1)
+	}
+	configureLogging(orch.GetConfig().Logging)
+
+	db := orch.GetDB()
+	if db == nil || !db.IsEnabled() {
+		color.Red("Error: Database is not enabled. Please enable it in config.yaml")
+		os.Exit(1)
+	}
+	return orch, db
+}
+
+func runCTLog(cmd *cobra.Command, args []string) {
+	Verbose = verbose
+
+	orch, db := openCTLogDB()
+	cfg := orch.GetConfig().CTLog
+
+	domains := append(append([]string{}, cfg.Domains...), ctlogDomains...)
+	if ctlogDomainList != "" {
+		fromFile, err := readDomainsFromFile(ctlogDomainList)
+		if err != nil {
+			color.Red("Error reading domain list: %v", err)
+			os.Exit(1)
+		}
+		domains = append(domains, fromFile...)
+	}
+	if len(domains) == 0 {
+		color.Red("Error: no watched domains; use -d, --dL or ctlog.domains")
+		os.Exit(1)
+	}
+
+	logs := ctlogSources(cfg)
+	if len(logs) == 0 {
+		color.Red("Error: no CT logs; use --log or ctlog.logs")
+		os.Exit(1)
+	}
+
+	follower := ctlog.New(db, ctlog.Options{
+		Logs:          logs,
+		Domains:       domains,
+		PollInterval:  time.Duration(cfg.PollInterval) * time.Second,
+		BatchSize:     cfg.BatchSize,
+		FromBeginning: ctlogFromBeginning || strings.EqualFold(cfg.Start, "beginning"),
+	})
+
+	follower.OnDiscovery = func(ctx context.Context, d ctlog.Discovery) error {
+		sources := make(map[string][]string, len(d.Hosts))
+		for _, host := range d.Hosts {
+			sources[host] = []string{"ctlog:" + d.Log}
+		}
+
+		summary := orch.TrackDiscoveries(d.Domain, "ctlog", sources)
+		if summary == nil {
+			return fmt.Errorf("hosts were not tracked")
+		}
+
+		for _, c := range summary.Changes {
+			if c.Kind == database.ChangeNew || c.Kind == database.ChangeRevived {
+				fmt.Println(c.Subdomain)
+			}
+		}
+		log.Infof("%s: %d host(s) for %s, %d new, %d revived (scan #%d)", d.Log, len(d.Hosts), d.Domain, summary.New, summary.Reactivated, summary.ScanID)
+		return nil
+	}
+	follower.OnError = func(l ctlog.Log, err error) {
+		log.Errorf("%s: %v", l.Name, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		log.Infof("Stopping")
+		cancel()
+	}()
+	serveMetrics(ctx, orch.GetConfig().Metrics)
+
+	failed := false
+	if ctlogOnce {
+		for _, l := range logs {
+			if err := follower.Poll(ctx, l); err != nil {
+				log.Errorf("%s: %v", l.URL, err)
+				failed = true
+			}
+		}
+	} else {
+		log.Infof("Following %d log(s) for %d domain(s)", len(logs), len(domains))
+		follower.Run(ctx)
+	}
+
+	orch.Close()
+	db.Close()
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func runCTLogStatus(cmd *cobra.Command, args []string) {
+	orch, db := openCTLogDB()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, color.CyanString("LOG\tPOSITION\tTREE_SIZE\tBEHIND\tUPDATED"))
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	for _, l := range ctlogSources(orch.GetConfig().CTLog) {
+		state, err := db.GetCTLogState(l.URL)
+		if err != nil {
+			color.Red("Failed to query database: %v", err)
+			os.Exit(1)
+		}
+		if state == nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\tnever\n", l.URL)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n",
+			l.URL,
+			state.Position,
+			state.TreeSize,
+			state.TreeSize-state.Position,
+			state.UpdatedAt.Format("2006-01-02 15:04:05"),
+		)
+	}
+	w.Flush()
+}
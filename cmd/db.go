@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/database"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the tracking database schema",
+	Long:  `Manage the tracking database schema`,
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending schema migrations",
+	Long: `Apply every pending schema migration to the tracking database. Each migration runs in
+its own transaction and existing tracking data is preserved.`,
+	Run: runDBMigrate,
+}
+
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show applied and pending schema migrations",
+	Long:  `Show applied and pending schema migrations for the tracking database`,
+	Run:   runDBStatus,
+}
+
+func init() {
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbStatusCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+func openSchemaDB() *database.DB {
+	configManager := config.NewManager(configFile)
+	if err := configManager.LoadConfig(); err != nil {
+		color.Red("Failed to load configuration: %v", err)
+		os.Exit(1)
+	}
+	cfg := configManager.GetConfig()
+	configureLogging(cfg.Logging)
+
+	if !cfg.Database.Enabled {
+		color.Red("Error: Database is not enabled. Please enable it in config.yaml")
+		os.Exit(1)
+	}
+
+	db, err := database.Open(&cfg.Database)
+	if err != nil {
+		color.Red("Failed to open database: %v", err)
+		os.Exit(1)
+	}
+
+	return db
+}
+
+func runDBMigrate(cmd *cobra.Command, args []string) {
+	db := openSchemaDB()
+	defer db.Close()
+
+	applied, err := db.Migrate()
+	for _, m := range applied {
+		log.Infof("Applied %04d_%s", m.Version, m.Name)
+	}
+	if err != nil {
+		color.Red("Migration failed: %v", err)
+		os.Exit(1)
+	}
+
+	if len(applied) == 0 {
+		log.Infof("Database schema is up to date")
+		return
+	}
+
+	log.Infof("Applied %d migration(s)", len(applied))
+}
+
+func runDBStatus(cmd *cobra.Command, args []string) {
+	db := openSchemaDB()
+	defer db.Close()
+
+	migrations, err := db.Migrations()
+	if err != nil {
+		color.Red("Failed to read migrations: %v", err)
+		os.Exit(1)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	fmt.Fprintln(w, "-------\t----\t------\t----------")
+
+	pending := 0
+	for _, m := range migrations {
+		status := color.GreenString("applied")
+		appliedAt := m.AppliedAt.Format("2006-01-02 15:04:05")
+		if !m.Applied {
+			status = color.YellowString("pending")
+			appliedAt = "-"
+			pending++
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", m.Version, m.Name, status, appliedAt)
+	}
+	w.Flush()
+
+	fmt.Println()
+	if pending > 0 {
+		color.Yellow("%d pending migration(s), run 'samoscout db migrate'", pending)
+	} else {
+		color.Cyan("Database schema is up to date")
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samogod/samoscout/pkg/importer"
+	"github.com/samogod/samoscout/pkg/orchestrator"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importDomain string
+	importFormat string
+	importTool   string
+	importScope  string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>...",
+	Short: "Import subdomains found by other tools into the tracking database",
+	Long: `Import subdomains found by other tools into the tracking database.
+
+Supported formats:
+  subfinder   subfinder -oJ output (JSON lines with host, input and source)
+  amass       amass -json output (JSON lines with name, domain and sources)
+  samoscout   samoscout -json output
+  list        one host per line (bbot, httpx -silent, ...)
+
+The format is detected from the first record unless --format is given; host-keyed JSON
+lines are read as subfinder output. Hosts are grouped by the target domain recorded in
+the file or given with -d, deduplicated, and hosts outside the target domain, or outside
+the --scope file, are dropped.
+Each import is recorded as a scan of the given tool and goes through the same tracking
+logic as a samoscout scan, but never marks hosts missing from the file as DEAD.`,
+	Example: `  samoscout import subfinder.json
+  samoscout import amass.json --format amass
+  samoscout import bbot_subdomains.txt -d example.com --tool bbot
+  samoscout import previous.json --format samoscout`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVarP(&importDomain, "domain", "d", "", "target domain (required for host lists)")
+	importCmd.Flags().StringVar(&importFormat, "format", importer.FormatAuto, "input format: "+strings.Join(importer.Formats, ", "))
+	importCmd.Flags().StringVar(&importTool, "tool", "", "tool that produced the file (default: the input format, or \"import\" for host lists)")
+	importCmd.Flags().StringVar(&importScope, "scope", "", "scope file limiting the imported hosts (default: default_settings.scope)")
+
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) {
+	orch, err := orchestrator.NewOrchestrator(configFile)
+	if err != nil {
+		color.Red("Failed to initialize orchestrator: %v", err)
+		os.Exit(1)
+	}
+	configureLogging(orch.GetConfig().Logging)
+
+	db := orch.GetDB()
+	if db == nil || !db.IsEnabled() {
+		color.Red("Error: Database is not enabled. Please enable it in config.yaml")
+		os.Exit(1)
+	}
+
+	domain := importer.NormalizeHost(importDomain)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, color.CyanString("FILE\tFORMAT\tDOMAIN\tHOSTS\tNEW\tREVIVED\tOUT_OF_SCOPE\tSCAN"))
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	failed := false
+	for _, file := range args {
+		parsed, err := importer.ParseFile(file, importFormat)
+		if err != nil {
+			color.Red("Failed to read %s: %v", file, err)
+			os.Exit(1)
+		}
+
+		if parsed.Skipped > 0 {
+			log.Infof("%s: skipped %d unparseable line(s)", file, parsed.Skipped)
+		}
+
+		tool := importTool
+		if tool == "" {
+			tool = parsed.Format
+			if tool == importer.FormatList {
+				tool = "import"
+			}
+		}
+
+		results, unassigned, err := orch.ImportRecords(parsed.Records, orchestrator.ImportOptions{
+			File:   file,
+			Format: parsed.Format,
+			Tool:   strings.ToLower(tool),
+			Domain: domain,
+			Scope:  importScope,
+		})
+
+		for _, r := range results {
+			newHosts, revived, scan := 0, 0, "-"
+			if r.Track != nil {
+				newHosts, revived, scan = r.Track.New, r.Track.Reactivated, fmt.Sprintf("%d", r.Track.ScanID)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\n",
+				file, parsed.Format, r.Domain, r.Hosts, newHosts, revived, r.OutOfScope, scan)
+		}
+
+		if unassigned > 0 {
+			log.Infof("%s: %d host(s) have no target domain, use -d to import them", file, unassigned)
+		}
+
+		if err != nil {
+			color.Red("Import of %s failed: %v", file, err)
+			failed = true
+			break
+		}
+	}
+	w.Flush()
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/llm"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var llmPullForce bool
+
+var llmCmd = &cobra.Command{
+	Use:   "llm",
+	Short: "Manage the AI prediction model",
+	Long:  `Manage the AI prediction model used by -llm`,
+}
+
+var llmPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Download and verify the AI model into the local cache",
+	Long: `Download the AI model files into the samoscout cache directory and verify them
+against the llm_enumeration digests. Copy the cache directory to an offline machine
+(or point llm_enumeration.model_path at it) to run -llm without network access.`,
+	Example: `  samoscout llm pull
+  samoscout llm pull --force`,
+	Run: runLLMPull,
+}
+
+func init() {
+	llmPullCmd.Flags().BoolVar(&llmPullForce, "force", false, "re-download model files even if they are cached")
+	llmCmd.AddCommand(llmPullCmd)
+	rootCmd.AddCommand(llmCmd)
+}
+
+func runLLMPull(cmd *cobra.Command, args []string) {
+	configManager := config.NewManager(configFile)
+	if err := configManager.LoadConfig(); err != nil {
+		color.Red("Failed to load configuration: %v", err)
+		os.Exit(1)
+	}
+	cfg := configManager.GetConfig()
+
+	// pull always targets the cache, model_path is only read at scan time
+	settings := cfg.LLMEnumeration
+	pins := []struct {
+		file, key, sum string
+	}{
+		{llm.ModelFile, "model_sha256", settings.ModelSHA256},
+		{llm.TokenizerFile, "tokenizer_sha256", settings.TokenizerSHA256},
+		{llm.ConfigFile, "config_sha256", settings.ConfigSHA256},
+	}
+
+	checksums := make(llm.Checksums)
+	for _, pin := range pins {
+		checksums[pin.file] = pin.sum
+	}
+
+	downloader := llm.NewDownloader("", checksums)
+	modelPath, _, err := downloader.DownloadModel(llmPullForce)
+	if err != nil {
+		color.Red("Model pull failed: %v", err)
+		os.Exit(1)
+	}
+
+	dir := filepath.Dir(modelPath)
+	color.Green("[INF] Model ready in %s", dir)
+
+	var unpinned []string
+	for _, pin := range pins {
+		sum, err := llm.FileSHA256(filepath.Join(dir, pin.file))
+		if err != nil {
+			color.Red("Failed to hash %s: %v", pin.file, err)
+			os.Exit(1)
+		}
+		color.Cyan("[INF] %s sha256: %s", pin.file, sum)
+		if pin.sum == "" {
+			unpinned = append(unpinned, "llm_enumeration."+pin.key)
+		}
+	}
+	if len(unpinned) > 0 {
+		color.Yellow("[INF] Set %s to pin this model version", strings.Join(unpinned, ", "))
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/logging"
+
+	"github.com/fatih/color"
+)
+
+var (
+	logLevel  string
+	logFormat string
+	logFile   string
+)
+
+var log = logging.Component("cmd")
+
+// configureLogging applies the logging section of the config with the
+// --log-* flags and -v on top. Commands call it once with an empty section
+// before anything is logged and again once the config file is loaded.
+func configureLogging(cfg config.Logging) {
+	options := logging.Options{Level: cfg.Level, Format: cfg.Format, File: cfg.File}
+	if verbose {
+		options.Level = "debug"
+	}
+	if logLevel != "" {
+		options.Level = logLevel
+	}
+	if logFormat != "" {
+		options.Format = logFormat
+	}
+	if logFile != "" {
+		options.File = logFile
+	}
+
+	if err := logging.Configure(options); err != nil {
+		color.Red("Error: %v", err)
+		os.Exit(1)
+	}
+}
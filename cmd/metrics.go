@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/metrics"
+
+	"github.com/fatih/color"
+)
+
+var metricsListen string
+
+// serveMetrics starts the /metrics endpoint of a long-running command on
+// --metrics-listen, or metrics.listen, until ctx is cancelled.
+func serveMetrics(ctx context.Context, cfg config.Metrics) {
+	listen := metricsListen
+	if listen == "" {
+		listen = cfg.Listen
+	}
+	if listen == "" {
+		return
+	}
+
+	if err := metrics.Serve(ctx, listen); err != nil {
+		color.Red("Failed to start metrics endpoint: %v", err)
+		os.Exit(1)
+	}
+	log.Infof("Serving metrics on http://%s/metrics", listen)
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/monitor"
+	"github.com/samogod/samoscout/pkg/orchestrator"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	monitorWatchlist   string
+	monitorConcurrency int
+	monitorOnce        bool
+)
+
+var monitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: "Continuously scan a watchlist of domains on per-domain schedules",
+	Long: `Continuously scan the domains of a watchlist, each on its own schedules. Results are
+tracked in the database and changes go to the configured notifications.
+
+A schedule is due one interval after its last run as recorded in the database, so a
+restarted monitor picks up where it left off; schedules missed while it was down run
+once at startup. Scans of the same domain never overlap.
+
+Watchlist format:
+  domains:
+    - domain: example.com
+      schedules:
+        - name: passive
+          every: 6h
+        - name: active
+          every: 7d          # s, m, h, d and w units
+          active: true       # also: sources, exclude_sources, deep_enum, llm, httpx, wordlist
+          httpx: true`,
+	Example: `  samoscout monitor --watchlist watchlist.yaml
+  samoscout monitor --watchlist watchlist.yaml --once
+  samoscout monitor status --watchlist watchlist.yaml`,
+	Args: cobra.NoArgs,
+	Run:  runMonitor,
+}
+
+var monitorStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the schedules of a watchlist with their last and next run",
+	Args:  cobra.NoArgs,
+	Run:   runMonitorStatus,
+}
+
+func init() {
+	monitorCmd.PersistentFlags().StringVarP(&monitorWatchlist, "watchlist", "w", "", "watchlist file (required)")
+	monitorCmd.Flags().IntVar(&monitorConcurrency, "concurrency", 1, "scans run at the same time")
+	monitorCmd.Flags().BoolVar(&monitorOnce, "once", false, "run the schedules that are due and exit")
+	monitorCmd.Flags().StringVar(&metricsListen, "metrics-listen", "", "serve Prometheus metrics on this address (default: metrics.listen)")
+	monitorCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose/debug output")
+	monitorCmd.MarkPersistentFlagRequired("watchlist")
+
+	monitorCmd.AddCommand(monitorStatusCmd)
+	rootCmd.AddCommand(monitorCmd)
+}
+
+func openMonitor() (*orchestrator.Orchestrator, *monitor.Monitor) {
+	watchlist, err := monitor.LoadWatchlist(monitorWatchlist)
+	if err != nil {
+		color.Red("Error: %v", err)
+		os.Exit(1)
+	}
+
+	orch, err := orchestrator.NewOrchestrator(configFile)
+	if err != nil {
+		color.Red("Failed to initialize orchestrator: %v", err)
+		os.Exit(1)
+	}
+	configureLogging(orch.GetConfig().Logging)
+
+	db := orch.GetDB()
+	if db == nil || !db.IsEnabled() {
+		color.Red("Error: Database is not enabled. Please enable it in config.yaml")
+		os.Exit(1)
+	}
+
+	return orch, monitor.New(orch, watchlist, monitorConcurrency)
+}
+
+func runMonitor(cmd *cobra.Command, args []string) {
+	Verbose = verbose
+
+	orch, mon := openMonitor()
+
+	if !orch.GetConfig().Notifications.Enabled {
+		log.Infof("Notifications are disabled; changes are only recorded in the database")
+	}
+
+	mon.OnStart = func(e monitor.Entry) {
+		log.Infof("Running %s schedule of %s", e.Schedule.Name, e.Domain)
+	}
+	mon.OnRun = func(r monitor.Report) {
+		if r.Err != nil {
+			log.Errorf("%s/%s failed after %s: %v", r.Domain, r.Schedule, r.Duration.Round(time.Second), r.Err)
+			return
+		}
+		line := fmt.Sprintf("%s/%s finished in %s: %d subdomains", r.Domain, r.Schedule, r.Duration.Round(time.Second), r.Result.TotalSubdomains)
+		if t := r.Result.Track; t != nil {
+			line += fmt.Sprintf(", %d new, %d revived, %d dead (scan #%d)", t.New, t.Reactivated, t.Died, t.ScanID)
+		}
+		log.Infof("%s", line)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		log.Infof("Stopping, waiting for running scans (interrupt again to exit now)")
+		cancel()
+		<-signals
+		os.Exit(1)
+	}()
+	serveMetrics(ctx, orch.GetConfig().Metrics)
+
+	var err error
+	if monitorOnce {
+		err = mon.RunOnce(ctx)
+	} else {
+		log.Infof("Monitoring %s", monitorWatchlist)
+		err = mon.Run(ctx)
+	}
+
+	orch.Close()
+	orch.GetDB().Close()
+
+	if err != nil {
+		color.Red("Monitor failed: %v", err)
+		os.Exit(1)
+	}
+}
+
+func runMonitorStatus(cmd *cobra.Command, args []string) {
+	_, mon := openMonitor()
+
+	entries, err := mon.Entries()
+	if err != nil {
+		color.Red("Failed to query database: %v", err)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, color.CyanString("DOMAIN\tSCHEDULE\tEVERY\tLAST_RUN\tNEXT_RUN\tRUNS\tSCAN\tERROR"))
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	for _, e := range entries {
+		lastRun, next, scan := "-", color.YellowString("due"), "-"
+		if !e.Last.LastRunAt.IsZero() {
+			lastRun = e.Last.LastRunAt.Format("2006-01-02 15:04:05")
+		}
+		if e.Next.After(now) {
+			next = e.Next.Format("2006-01-02 15:04:05")
+		}
+		if e.Last.ScanID != 0 {
+			scan = fmt.Sprintf("%d", e.Last.ScanID)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			e.Domain,
+			e.Schedule.Name,
+			e.Schedule.Every,
+			lastRun,
+			next,
+			e.Last.Runs,
+			scan,
+			e.Last.Error,
+		)
+	}
+	w.Flush()
+}
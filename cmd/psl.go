@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/domainutil"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var pslCmd = &cobra.Command{
+	Use:   "psl",
+	Short: "Manage the Public Suffix List",
+	Long: `Manage the Public Suffix List used to find the registrable domain and level
+of hosts (example.co.uk, example.com.br). A copy is embedded in the binary.`,
+}
+
+var pslUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Download the current Public Suffix List",
+	Long: `Download the current Public Suffix List from publicsuffix.org into
+default_settings.psl, or into the samoscout cache directory when it is not set.
+Later runs use the downloaded list instead of the embedded one.`,
+	Example: `  samoscout psl update`,
+	Run:     runPSLUpdate,
+}
+
+func init() {
+	pslCmd.AddCommand(pslUpdateCmd)
+	rootCmd.AddCommand(pslCmd)
+}
+
+func runPSLUpdate(cmd *cobra.Command, args []string) {
+	configManager := config.NewManager(configFile)
+	if err := configManager.LoadConfig(); err != nil {
+		color.Red("Failed to load configuration: %v", err)
+		os.Exit(1)
+	}
+	cfg := configManager.GetConfig()
+
+	path := cfg.DefaultSettings.PSL
+	if path == "" {
+		path = config.GetPSLCachePath()
+	}
+
+	rules, err := domainutil.Download(path)
+	if err != nil {
+		color.Red("Public Suffix List update failed: %v", err)
+		os.Exit(1)
+	}
+
+	color.Green("[INF] Saved %d Public Suffix List rules to %s", rules, path)
+}
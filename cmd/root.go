@@ -2,13 +2,11 @@ package cmd
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"github.com/samogod/samoscout/pkg/config"
-	"github.com/samogod/samoscout/pkg/database"
 	"github.com/samogod/samoscout/pkg/orchestrator"
-	"github.com/samogod/samoscout/pkg/session"
+	"github.com/samogod/samoscout/pkg/sink"
 	"sort"
 	"strings"
 
@@ -32,6 +30,7 @@ var (
 	llmEnum        bool
 	httpxProbe     bool
 	wordlistPath   string
+	scopeFile      string
 )
 
 var Verbose bool
@@ -41,6 +40,9 @@ var rootCmd = &cobra.Command{
 	Short: "all in one subdomain enumeration tool",
 	Long:  `all in one llm powered, passive & active subdomain enumeration tool`,
 	Run:   runScan,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		configureLogging(config.Logging{})
+	},
 }
 
 func Execute() {
@@ -62,6 +64,10 @@ func Execute() {
 		if arg == "-es" {
 			os.Args[i] = "--es"
 		}
+		// keep machine-readable track output parseable
+		if arg == "--csv" || arg == "--json" {
+			hasSilentFlag = true
+		}
 	}
 
 	if !hasSilentFlag {
@@ -74,19 +80,6 @@ func Execute() {
 	}
 }
 
-func DebugLog(format string, args ...interface{}) {
-	if Verbose {
-		fmt.Printf("[DBG] "+format+"\n", args...)
-	}
-}
-
-func setDebugLogFunctions() {
-	config.DebugLog = DebugLog
-	orchestrator.DebugLog = DebugLog
-	session.DebugLog = DebugLog
-	database.DebugLog = DebugLog
-}
-
 func init() {
 	rootCmd.SetHelpTemplate(`Usage:
   {{.UseLine}}{{if .HasAvailableSubCommands}}
@@ -100,6 +93,9 @@ INPUT:
    -d, -domain string      target domain to enumerate
    -dL, -list string       file containing list of domains to enumerate
 
+SCOPE:
+   -scope string           scope file with include/exclude domains, regexes and CIDRs
+
 SOURCE:
    -s, -sources string     comma-separated list of sources to use (e.g., 'subdomaincenter,shrewdeye')
    -es string              comma-separated list of sources to exclude (e.g., 'alienvault,zoomeyeapi')
@@ -129,14 +125,23 @@ CONFIGURATION:
 
 OPTIMIZATION:
    -v, -verbose            enable verbose/debug output
+
+LOGGING:
+   -log-level string       log level: debug, info, warn or error (default: info)
+   -log-format string      log format: text or json (default: text)
+   -log-file string        append logs to this file instead of stderr
 {{if .HasAvailableSubCommands}}
 Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
 `)
 
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "config file path (default: config/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "log level: debug, info, warn or error (default: info, debug with -v)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "log format: text or json (default: text)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "append logs to this file instead of stderr")
 
 	rootCmd.Flags().StringVarP(&domain, "domain", "d", "", "target domain to enumerate")
 	rootCmd.Flags().StringVar(&domainList, "dL", "", "file containing list of domains to enumerate")
+	rootCmd.Flags().StringVar(&scopeFile, "scope", "", "scope file with include/exclude domains, regexes and CIDRs")
 	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "", "file to write output to")
 	rootCmd.Flags().BoolVarP(&jsonFormat, "json", "j", false, "write output in JSONL(ines) format")
 	rootCmd.Flags().BoolVar(&silent, "silent", false, "silent mode - no banner or extra output")
@@ -169,15 +174,20 @@ func runScan(cmd *cobra.Command, args []string) {
 
 	Verbose = verbose
 
-	if verbose {
-		setDebugLogFunctions()
-	}
-
 	orch, err := orchestrator.NewOrchestrator(configFile)
 	if err != nil {
 		color.Red("Failed to initialize orchestrator: %v", err)
 		os.Exit(1)
 	}
+	configureLogging(orch.GetConfig().Logging)
+
+	orch.AddSink(sink.NewStdout(jsonFormat), sink.Options{})
+
+	var fileSink *sink.File
+	if outputFile != "" {
+		fileSink = sink.NewFile(outputFile, jsonFormat)
+		orch.AddSink(fileSink, sink.Options{})
+	}
 
 	var domains []string
 
@@ -196,7 +206,7 @@ func runScan(cmd *cobra.Command, args []string) {
 
 	allSuccess := true
 	for _, targetDomain := range domains {
-		DebugLog("enumerating subdomains for %s", targetDomain)
+		log.Debugf("enumerating subdomains for %s", targetDomain)
 
 		scanOptions := orchestrator.ScanOptions{
 			Domain:         targetDomain,
@@ -209,6 +219,7 @@ func runScan(cmd *cobra.Command, args []string) {
 			LLMEnum:        llmEnum,
 			HttpxProbe:     httpxProbe,
 			WordlistPath:   wordlistPath,
+			ScopeFile:      scopeFile,
 		}
 
 		result, err := orch.RunScan(scanOptions)
@@ -218,7 +229,7 @@ func runScan(cmd *cobra.Command, args []string) {
 			continue
 		}
 
-		if err := handleOutput(result); err != nil {
+		if err := handleOutput(orch, fileSink, result); err != nil {
 			color.Red("Output error for %s: %v", targetDomain, err)
 			allSuccess = false
 			continue
@@ -233,6 +244,11 @@ func runScan(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if err := orch.Close(); err != nil {
+		color.Red("Output error: %v", err)
+		allSuccess = false
+	}
+
 	if allSuccess {
 		os.Exit(0)
 	} else {
@@ -281,21 +297,22 @@ func printBanner() {
 	fmt.Println()
 }
 
-func handleOutput(result *orchestrator.ScanResult) error {
-	if outputFile == "" {
-		if jsonFormat {
-			displayJSONResults(result)
-		} else {
-			displayTXTResults(result)
+// handleOutput prints the scan summary. Results themselves are streamed by
+// the stdout and file sinks while the scan runs.
+func handleOutput(orch *orchestrator.Orchestrator, fileSink *sink.File, result *orchestrator.ScanResult) error {
+	if fileSink != nil {
+		if failures := orch.SinkFailures()[fileSink.Name()]; failures > 0 {
+			return fmt.Errorf("failed to write %d result(s) to %s", failures, outputFile)
 		}
 		return nil
 	}
 
 	if jsonFormat {
-		return writeJSONFile(result, outputFile)
+		displayJSONResults(result)
 	} else {
-		return writeTXTFile(result, outputFile)
+		displayTXTResults(result)
 	}
+	return nil
 }
 
 func displayTXTResults(result *orchestrator.ScanResult) {
@@ -306,15 +323,16 @@ func displayTXTResults(result *orchestrator.ScanResult) {
 			color.Cyan("Active web services: %d hosts responding to HTTP/HTTPS",
 				len(result.ActiveWebServices))
 		}
+		if len(result.WildcardZones) > 0 {
+			color.Cyan("Wildcard zones: %d zones with wildcard names", len(result.WildcardZones))
+		}
+		displayArtifactSummary(result)
+		displayScopeSummary(result)
+		displaySinkErrors(result)
+		displayTrackSummary(result)
 	}
 }
 
-type SubdomainResult struct {
-	Host   string `json:"host"`
-	Input  string `json:"input"`
-	Source string `json:"source"`
-}
-
 func displayJSONResults(result *orchestrator.ScanResult) {
 	if !silent {
 		color.Green("\nScan completed: Found %d subdomains for %s in %v",
@@ -323,71 +341,79 @@ func displayJSONResults(result *orchestrator.ScanResult) {
 			color.Cyan("Active web services: %d hosts responding to HTTP/HTTPS",
 				len(result.ActiveWebServices))
 		}
+		if len(result.WildcardZones) > 0 {
+			color.Cyan("Wildcard zones: %d zones with wildcard names", len(result.WildcardZones))
+		}
+		displayArtifactSummary(result)
+		displayScopeSummary(result)
+		displaySinkErrors(result)
+		displayTrackSummary(result)
 	}
 }
 
-func writeTXTFile(result *orchestrator.ScanResult, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
-
-	for _, subdomain := range result.Subdomains {
-		if _, err := fmt.Fprintln(file, subdomain); err != nil {
-			return fmt.Errorf("failed to write to file: %w", err)
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
 		}
 	}
-
-	return nil
+	return false
 }
 
-func writeJSONFile(result *orchestrator.ScanResult, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+func displayTrackSummary(result *orchestrator.ScanResult) {
+	if result.Track == nil {
+		return
 	}
-	defer file.Close()
-
-	for _, subdomain := range result.Subdomains {
-		source := "unknown"
-		if result.SubdomainSources != nil {
-			if s, ok := result.SubdomainSources[subdomain]; ok {
-				source = s
-			}
-		}
+	color.Cyan("Tracking (scan #%d): %d new, %d reactivated, %d died",
+		result.Track.ScanID, result.Track.New, result.Track.Reactivated, result.Track.Died)
+}
 
-		jsonResult := SubdomainResult{
-			Host:   subdomain,
-			Input:  result.Domain,
-			Source: source,
-		}
+// displayArtifactSummary counts the IPs, URLs and related domains sources
+// reported; JSON output lists them.
+func displayArtifactSummary(result *orchestrator.ScanResult) {
+	if len(result.Artifacts) == 0 {
+		return
+	}
 
-		jsonBytes, err := json.Marshal(jsonResult)
-		if err != nil {
-			return fmt.Errorf("failed to marshal JSON: %w", err)
-		}
+	counts := make(map[string]int)
+	for _, artifact := range result.Artifacts {
+		counts[artifact.Type]++
+	}
+	color.Cyan("Artifacts: %d IPs, %d URLs, %d related domains",
+		counts["ip"], counts["url"], counts["related_domain"])
+}
 
-		if _, err := fmt.Fprintln(file, string(jsonBytes)); err != nil {
-			return fmt.Errorf("failed to write to file: %w", err)
-		}
+// displaySinkErrors lists what sinks failed to store, such as rejected
+// Elasticsearch documents.
+func displaySinkErrors(result *orchestrator.ScanResult) {
+	for _, err := range result.SinkErrors {
+		color.Red("Sink error: %v", err)
 	}
+}
 
-	if _, err := fmt.Fprintf(file, "\n# Found %d subdomains for %s in %v\n",
-		len(result.Subdomains), result.Domain, result.Duration); err != nil {
-		return fmt.Errorf("failed to write summary to file: %w", err)
+// displayScopeSummary counts the out-of-scope hosts by reason; -stats lists
+// them.
+func displayScopeSummary(result *orchestrator.ScanResult) {
+	if len(result.OutOfScope) == 0 {
+		return
 	}
 
-	return nil
-}
+	reasons := make(map[string]int)
+	for _, drop := range result.OutOfScope {
+		reasons[drop.Reason]++
+	}
+	keys := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		keys = append(keys, reason)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return reasons[keys[i]] > reasons[keys[j]] || reasons[keys[i]] == reasons[keys[j]] && keys[i] < keys[j]
+	})
 
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
+	color.Yellow("Out of scope: %d hosts dropped", len(result.OutOfScope))
+	for _, reason := range keys {
+		color.Yellow("  %d %s", reasons[reason], reason)
 	}
-	return false
 }
 
 func displayStatistics(result *orchestrator.ScanResult) {
@@ -423,4 +449,31 @@ func displayStatistics(result *orchestrator.ScanResult) {
 	}
 
 	fmt.Println()
+
+	if len(result.WildcardZones) > 0 {
+		color.Cyan("[INF] Printing wildcard zones for %s", result.Domain)
+		fmt.Println()
+
+		for _, zone := range result.WildcardZones {
+			fmt.Printf(" *.%s\n", zone)
+		}
+
+		fmt.Println()
+	}
+
+	if len(result.OutOfScope) == 0 {
+		return
+	}
+
+	color.Cyan("[INF] Printing out-of-scope hosts for %s", result.Domain)
+	fmt.Println()
+
+	fmt.Printf(" %-40s %-10s %s\n", "Host", "Phase", "Reason")
+	color.Cyan(strings.Repeat("─", 80))
+
+	for _, drop := range result.OutOfScope {
+		fmt.Printf(" %-40s %-10s %s\n", drop.Host, drop.Phase, drop.Reason)
+	}
+
+	fmt.Println()
 }
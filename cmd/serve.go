@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/orchestrator"
+	"github.com/samogod/samoscout/pkg/server"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveListen      string
+	serveTokens      []string
+	serveConcurrency int
+	serveNoAuth      bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the REST API with a scan job queue",
+	Long: `Run samoscout as a service. Scans are submitted as jobs, queued in the tracking
+database and run with bounded concurrency; results and track queries are served from
+the database. Queued jobs survive a restart, and jobs interrupted by one are run again.
+
+Endpoints:
+  POST   /scans                         queue a scan ({"domain": "example.com", "active": true, ...})
+  GET    /scans                         list jobs (?domain=, ?status=, ?limit=)
+  GET    /scans/{id}                    job status
+  DELETE /scans/{id}                    cancel a queued job
+  GET    /scans/{id}/events             progress as server-sent events
+  GET    /scans/{id}/results            subdomains observed by the job's scan
+  GET    /subdomains                    tracked subdomains (track filters as query parameters)
+  GET    /subdomains/{host}/timeline    observations and status changes of one host
+  GET    /domains/{domain}/scans        scan history
+  GET    /domains/{domain}/diff         hosts added and removed between scans (?from=, ?to=)
+  GET    /metrics                       Prometheus metrics
+  GET    /health                        liveness, no authentication
+
+Requests authenticate with "Authorization: Bearer <token>", an X-API-Token header or a
+token query parameter, using a token from --token or server.tokens.`,
+	Example: `  samoscout serve --token "$SAMOSCOUT_TOKEN"
+  samoscout serve --listen 0.0.0.0:8080 --concurrency 4
+  curl -H "Authorization: Bearer $SAMOSCOUT_TOKEN" -d '{"domain":"example.com"}' http://127.0.0.1:8080/scans`,
+	Args: cobra.NoArgs,
+	Run:  runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "", "address to listen on (default: server.listen, or 127.0.0.1:8080)")
+	serveCmd.Flags().StringSliceVar(&serveTokens, "token", nil, "API token accepted by the server (repeatable, adds to server.tokens)")
+	serveCmd.Flags().IntVar(&serveConcurrency, "concurrency", 0, "scans run at the same time (default: server.concurrency, or 2)")
+	serveCmd.Flags().BoolVar(&serveNoAuth, "no-auth", false, "accept requests without a token")
+	serveCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose/debug output")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) {
+	Verbose = verbose
+
+	orch, err := orchestrator.NewOrchestrator(configFile)
+	if err != nil {
+		color.Red("Failed to initialize orchestrator: %v", err)
+		os.Exit(1)
+	}
+	configureLogging(orch.GetConfig().Logging)
+
+	db := orch.GetDB()
+	if db == nil || !db.IsEnabled() {
+		color.Red("Error: Database is not enabled. Please enable it in config.yaml")
+		os.Exit(1)
+	}
+
+	cfg := orch.GetConfig().Server
+	listen := serveListen
+	if listen == "" {
+		listen = cfg.Listen
+	}
+	if listen == "" {
+		listen = "127.0.0.1:8080"
+	}
+	concurrency := serveConcurrency
+	if concurrency <= 0 {
+		concurrency = cfg.Concurrency
+	}
+	if concurrency <= 0 {
+		concurrency = 2
+	}
+
+	var tokens []string
+	for _, t := range append(cfg.Tokens, serveTokens...) {
+		if t != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	if len(tokens) == 0 && !serveNoAuth {
+		color.Red("Error: no API token configured; set server.tokens, pass --token, or use --no-auth")
+		os.Exit(1)
+	}
+
+	queue := server.NewQueue(orch, concurrency)
+	requeued, err := queue.Start()
+	if err != nil {
+		color.Red("Failed to start job queue: %v", err)
+		os.Exit(1)
+	}
+	if requeued > 0 {
+		log.Infof("Resuming %d queued job(s)", requeued)
+	}
+
+	srv := &http.Server{
+		Addr:              listen,
+		Handler:           server.New(orch, queue, tokens).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	log.Infof("Listening on http://%s (%d concurrent scan(s))", listen, concurrency)
+	if len(tokens) == 0 {
+		log.Warnf("API authentication is disabled")
+	}
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			color.Red("Server failed: %v", err)
+			os.Exit(1)
+		}
+	case <-signals:
+	}
+
+	// a second signal skips waiting; interrupted jobs are requeued on the next start
+	go func() {
+		<-signals
+		log.Infof("Exiting without waiting for running scans")
+		os.Exit(1)
+	}()
+
+	if running := queue.Running(); running > 0 {
+		log.Infof("Shutting down, waiting for %d running scan(s) (interrupt again to exit now)", running)
+	} else {
+		log.Infof("Shutting down")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(ctx)
+
+	queue.Stop()
+	orch.Close()
+	db.Close()
+}
@@ -1,34 +1,76 @@
 package cmd
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
+	"github.com/samogod/samoscout/pkg/database"
 	"github.com/samogod/samoscout/pkg/orchestrator"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
 var (
-	trackStatus string
-	trackAll    bool
+	trackStatus           string
+	trackAll              bool
+	trackSince            string
+	trackUntil            string
+	trackTimeField        string
+	trackNewSinceLastScan bool
+	trackMatch            string
+	trackRegex            string
+	trackJSON             bool
+	trackCSV              bool
+	trackTags             []string
+	trackState            string
 )
 
 var trackCmd = &cobra.Command{
 	Use:   "track [domain]",
 	Short: "Query subdomain tracking database",
 	Long:  `Query subdomain tracking database for a specific domain or all domains`,
-	Run:   runTrack,
+	Example: `  samoscout track example.com --since 7d
+  samoscout track example.com --since 2024-05-01 --until 2024-06-01 --time-field last_seen
+  samoscout track example.com --new-since-last-scan --json
+  samoscout track --all --status dead --regex '^(dev|stg)-' --csv`,
+	Run: runTrack,
 }
 
 func init() {
 	trackCmd.Flags().StringVar(&trackStatus, "status", "", "filter by status (active, dead, new)")
 	trackCmd.Flags().BoolVar(&trackAll, "all", false, "query all domains")
+	trackCmd.Flags().StringVar(&trackSince, "since", "", "only hosts seen at or after this time (2006-01-02, RFC3339, or a duration like 24h, 7d)")
+	trackCmd.Flags().StringVar(&trackUntil, "until", "", "only hosts seen at or before this time (same formats as --since)")
+	trackCmd.Flags().StringVar(&trackTimeField, "time-field", "first_seen", "field --since/--until apply to (first_seen, last_seen)")
+	trackCmd.Flags().BoolVar(&trackNewSinceLastScan, "new-since-last-scan", false, "only hosts first discovered by the latest scan (imports and ctlog batches excluded)")
+	trackCmd.Flags().StringVar(&trackMatch, "match", "", "only subdomains containing this substring")
+	trackCmd.Flags().StringVar(&trackRegex, "regex", "", "only subdomains matching this regular expression")
+	trackCmd.Flags().StringSliceVar(&trackTags, "tag", nil, "only subdomains carrying this tag (repeatable, all must match)")
+	trackCmd.Flags().StringVar(&trackState, "state", "", "filter by triage state ("+strings.Join(database.TriageStates, ", ")+")")
+	trackCmd.Flags().BoolVar(&trackJSON, "json", false, "write results as JSON")
+	trackCmd.Flags().BoolVar(&trackCSV, "csv", false, "write results as CSV")
 	rootCmd.AddCommand(trackCmd)
 }
 
+type trackRecordJSON struct {
+	Domain      string    `json:"domain"`
+	Subdomain   string    `json:"subdomain"`
+	Status      string    `json:"status"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	TriageState string    `json:"triage_state"`
+	Tags        []string  `json:"tags"`
+	HTTPStatus  int       `json:"http_status,omitempty"`
+	HTTPTitle   string    `json:"http_title,omitempty"`
+}
+
 func runTrack(cmd *cobra.Command, args []string) {
 	if !trackAll && len(args) == 0 {
 		color.Red("Error: either provide a domain or use --all flag")
@@ -42,83 +84,66 @@ func runTrack(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	orch, err := orchestrator.NewOrchestrator(configFile)
-	if err != nil {
-		color.Red("Failed to initialize orchestrator: %v", err)
+	if trackJSON && trackCSV {
+		color.Red("Error: --json and --csv cannot be used together")
 		os.Exit(1)
 	}
 
-	db := orch.GetDB()
-	if db == nil || !db.IsEnabled() {
-		color.Red("Error: Database is not enabled. Please enable it in config.yaml")
+	filter, err := buildTrackFilter(args)
+	if err != nil {
+		color.Red("Error: %v", err)
 		os.Exit(1)
 	}
 
-	if trackStatus != "" {
-		trackStatus = strings.ToUpper(trackStatus)
-	}
+	db := openTrackDB()
 
-	var records []struct {
-		Domain    string
-		Subdomain string
-		Status    string
-		FirstSeen string
-		LastSeen  string
+	records, err := db.FindSubdomains(filter)
+	if err != nil {
+		color.Red("Failed to query database: %v", err)
+		os.Exit(1)
 	}
 
-	if trackAll {
-		results, err := db.QueryAllSubdomains(trackStatus)
-		if err != nil {
-			color.Red("Failed to query database: %v", err)
-			os.Exit(1)
-		}
-		for _, r := range results {
-			records = append(records, struct {
-				Domain    string
-				Subdomain string
-				Status    string
-				FirstSeen string
-				LastSeen  string
-			}{
-				Domain:    r.Domain,
-				Subdomain: r.Subdomain,
-				Status:    r.Status,
-				FirstSeen: r.FirstSeen.Format("2006-01-02 15:04:05"),
-				LastSeen:  r.LastSeen.Format("2006-01-02 15:04:05"),
+	switch {
+	case trackJSON:
+		out := make([]trackRecordJSON, 0, len(records))
+		for _, r := range records {
+			out = append(out, trackRecordJSON{
+				Domain:      r.Domain,
+				Subdomain:   r.Subdomain,
+				Status:      r.Status,
+				FirstSeen:   r.FirstSeen,
+				LastSeen:    r.LastSeen,
+				TriageState: r.TriageState,
+				Tags:        append([]string{}, r.Tags...),
+				HTTPStatus:  r.HTTPStatus,
+				HTTPTitle:   r.HTTPTitle,
 			})
 		}
-	} else {
-		domain := args[0]
-		results, err := db.QuerySubdomains(domain, trackStatus)
-		if err != nil {
-			color.Red("Failed to query database: %v", err)
-			os.Exit(1)
-		}
-
-		if len(results) == 0 {
-			color.Yellow("[INF] Domain %s not found in database.", domain)
-			os.Exit(0)
-		}
-
-		for _, r := range results {
-			records = append(records, struct {
-				Domain    string
-				Subdomain string
-				Status    string
-				FirstSeen string
-				LastSeen  string
-			}{
-				Domain:    r.Domain,
-				Subdomain: r.Subdomain,
-				Status:    r.Status,
-				FirstSeen: r.FirstSeen.Format("2006-01-02 15:04:05"),
-				LastSeen:  r.LastSeen.Format("2006-01-02 15:04:05"),
-			})
+		writeJSON(out)
+		return
+
+	case trackCSV:
+		rows := [][]string{{"domain", "subdomain", "status", "first_seen", "last_seen", "triage_state", "tags", "http_status", "http_title"}}
+		for _, r := range records {
+			rows = append(rows, []string{r.Domain, r.Subdomain, r.Status,
+				r.FirstSeen.Format(time.RFC3339), r.LastSeen.Format(time.RFC3339),
+				r.TriageState, strings.Join(r.Tags, ","), strconv.Itoa(r.HTTPStatus), r.HTTPTitle})
+		}
+		writeCSV(rows)
+		return
+	}
+
+	if len(records) == 0 {
+		if filter.Domain != "" && !trackFiltered(cmd) {
+			color.Yellow("[INF] Domain %s not found in database.", filter.Domain)
+		} else {
+			color.Yellow("[INF] No subdomains match the given filters.")
 		}
+		os.Exit(0)
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, color.CyanString("DOMAIN\tSUBDOMAIN\tSTATUS\tFIRST_SEEN\tLAST_SEEN"))
+	fmt.Fprintln(w, color.CyanString("DOMAIN\tSUBDOMAIN\tSTATUS\tFIRST_SEEN\tLAST_SEEN\tHTTP\tSTATE\tTAGS"))
 	fmt.Fprintln(w, strings.Repeat("-", 100))
 
 	for _, r := range records {
@@ -129,15 +154,417 @@ func runTrack(cmd *cobra.Command, args []string) {
 			statusColor = color.YellowString
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		httpStatus := "-"
+		if r.HTTPStatus != 0 {
+			httpStatus = strconv.Itoa(r.HTTPStatus)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			r.Domain,
 			r.Subdomain,
 			statusColor(r.Status),
-			r.FirstSeen,
-			r.LastSeen,
+			r.FirstSeen.Format("2006-01-02 15:04:05"),
+			r.LastSeen.Format("2006-01-02 15:04:05"),
+			httpStatus,
+			r.TriageState,
+			strings.Join(r.Tags, ","),
 		)
 	}
 	w.Flush()
 
 	color.Green("\nTotal records: %d", len(records))
 }
+
+func buildTrackFilter(args []string) (database.SubdomainFilter, error) {
+	filter := database.SubdomainFilter{
+		Status:           strings.ToUpper(trackStatus),
+		Match:            strings.ToLower(trackMatch),
+		NewSinceLastScan: trackNewSinceLastScan,
+		TriageState:      strings.ToLower(trackState),
+	}
+
+	if filter.TriageState != "" && !database.ValidTriageState(filter.TriageState) {
+		return filter, fmt.Errorf("invalid --state %q (valid: %s)", trackState, strings.Join(database.TriageStates, ", "))
+	}
+
+	for _, tag := range trackTags {
+		tag, err := normalizeTag(tag)
+		if err != nil {
+			return filter, err
+		}
+		filter.Tags = append(filter.Tags, tag)
+	}
+
+	if len(args) > 0 {
+		filter.Domain = args[0]
+	}
+
+	if trackRegex != "" {
+		re, err := regexp.Compile(trackRegex)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --regex: %w", err)
+		}
+		filter.Regex = re
+	}
+
+	since, err := parseTrackTime(trackSince)
+	if err != nil {
+		return filter, fmt.Errorf("invalid --since: %w", err)
+	}
+	until, err := parseTrackTime(trackUntil)
+	if err != nil {
+		return filter, fmt.Errorf("invalid --until: %w", err)
+	}
+
+	switch trackTimeField {
+	case "first_seen":
+		filter.FirstSeenSince, filter.FirstSeenUntil = since, until
+	case "last_seen":
+		filter.LastSeenSince, filter.LastSeenUntil = since, until
+	default:
+		return filter, fmt.Errorf("invalid --time-field %q (use first_seen or last_seen)", trackTimeField)
+	}
+
+	return filter, nil
+}
+
+func trackFiltered(cmd *cobra.Command) bool {
+	for _, name := range []string{"status", "since", "until", "new-since-last-scan", "match", "regex", "tag", "state"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrackTime accepts an absolute date/time or a duration counted back from
+// now; "d" and "w" suffixes are allowed on top of time.ParseDuration units.
+func parseTrackTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	unit := value[len(value)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+		}
+		days := n
+		if unit == 'w' {
+			days = n * 7
+		}
+		return time.Now().AddDate(0, 0, -days), nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+	}
+
+	return time.Now().Add(-d), nil
+}
+
+func writeJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		color.Red("Failed to write JSON: %v", err)
+		os.Exit(1)
+	}
+}
+
+func writeCSV(rows [][]string) {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.WriteAll(rows); err != nil {
+		color.Red("Failed to write CSV: %v", err)
+		os.Exit(1)
+	}
+}
+
+var trackTimelineCmd = &cobra.Command{
+	Use:   "timeline <subdomain>",
+	Short: "Show per-scan history for a single subdomain",
+	Long:  `Show every scan of the subdomain's domain since it was first seen and whether each scan observed it`,
+	Args:  cobra.ExactArgs(1),
+	Run:   runTrackTimeline,
+}
+
+var trackScansCmd = &cobra.Command{
+	Use:   "scans [domain]",
+	Short: "List recorded scans",
+	Long:  `List recorded scans for a domain, or for all domains when no domain is given`,
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runTrackScans,
+}
+
+var trackScanCmd = &cobra.Command{
+	Use:   "scan <scan-id>",
+	Short: "Show subdomains observed by a single scan",
+	Args:  cobra.ExactArgs(1),
+	Run:   runTrackScan,
+}
+
+var trackDiffCmd = &cobra.Command{
+	Use:   "diff <domain>",
+	Short: "Show hosts added and removed between two scans",
+	Long: `Show hosts observed by the --to scan but not the --from scan (added) and the other
+way round (removed). --to defaults to the latest scan of the domain and --from to the
+scan before it.`,
+	Example: `  samoscout track diff example.com
+  samoscout track diff example.com --from 40 --to 42 --json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runTrackDiff,
+}
+
+var (
+	trackDiffFrom int64
+	trackDiffTo   int64
+)
+
+type trackDiffJSON struct {
+	Domain  string   `json:"domain"`
+	From    int64    `json:"from"`
+	To      int64    `json:"to"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+func init() {
+	trackDiffCmd.Flags().Int64Var(&trackDiffFrom, "from", 0, "base scan id (default: scan before --to)")
+	trackDiffCmd.Flags().Int64Var(&trackDiffTo, "to", 0, "target scan id (default: latest scan)")
+	trackDiffCmd.Flags().BoolVar(&trackJSON, "json", false, "write results as JSON")
+	trackDiffCmd.Flags().BoolVar(&trackCSV, "csv", false, "write results as CSV")
+
+	trackCmd.AddCommand(trackTimelineCmd)
+	trackCmd.AddCommand(trackScansCmd)
+	trackCmd.AddCommand(trackScanCmd)
+	trackCmd.AddCommand(trackDiffCmd)
+}
+
+func openTrackDB() *database.DB {
+	orch, err := orchestrator.NewOrchestrator(configFile)
+	if err != nil {
+		color.Red("Failed to initialize orchestrator: %v", err)
+		os.Exit(1)
+	}
+	configureLogging(orch.GetConfig().Logging)
+
+	db := orch.GetDB()
+	if db == nil || !db.IsEnabled() {
+		color.Red("Error: Database is not enabled. Please enable it in config.yaml")
+		os.Exit(1)
+	}
+
+	return db
+}
+
+func runTrackTimeline(cmd *cobra.Command, args []string) {
+	db := openTrackDB()
+
+	subdomain := strings.ToLower(strings.TrimSpace(args[0]))
+	records, err := db.QuerySubdomainTimeline(subdomain)
+	if err != nil {
+		color.Red("Failed to query database: %v", err)
+		os.Exit(1)
+	}
+
+	if len(records) == 0 {
+		color.Yellow("[INF] Subdomain %s not found in any recorded scan.", subdomain)
+		os.Exit(0)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, color.CyanString("SCAN\tSTARTED\tDOMAIN\tSEEN\tEVENT\tSOURCES"))
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	seenBefore := false
+	lastSeen := false
+	for _, r := range records {
+		event := ""
+		switch {
+		case r.Seen && !seenBefore:
+			event = color.YellowString("appeared")
+		case r.Seen && !lastSeen:
+			event = color.GreenString("reappeared")
+		case !r.Seen && lastSeen:
+			event = color.RedString("disappeared")
+		}
+
+		seen := color.RedString("no")
+		if r.Seen {
+			seen = color.GreenString("yes")
+			seenBefore = true
+		}
+		lastSeen = r.Seen
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			r.ScanID,
+			r.StartedAt.Format("2006-01-02 15:04:05"),
+			r.Domain,
+			seen,
+			event,
+			strings.Join(r.Sources, ","),
+		)
+	}
+	w.Flush()
+
+	color.Green("\nTotal scans: %d", len(records))
+
+	changes, err := db.QueryStatusChanges(subdomain)
+	if err != nil {
+		color.Red("Failed to query status changes: %v", err)
+		os.Exit(1)
+	}
+
+	if len(changes) == 0 {
+		return
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, color.CyanString("CHANGED_AT	SCAN	FROM	TO	REASON"))
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	for _, c := range changes {
+		from := c.OldStatus
+		if from == "" {
+			from = "-"
+		}
+		// changes made by track verify have no scan
+		scan := "-"
+		if c.ScanID != 0 {
+			scan = strconv.FormatInt(c.ScanID, 10)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			c.ChangedAt.Format("2006-01-02 15:04:05"),
+			scan,
+			from,
+			c.NewStatus,
+			c.Reason,
+		)
+	}
+	w.Flush()
+}
+
+func runTrackScans(cmd *cobra.Command, args []string) {
+	db := openTrackDB()
+
+	domain := ""
+	if len(args) > 0 {
+		domain = args[0]
+	}
+
+	scans, err := db.QueryScans(domain)
+	if err != nil {
+		color.Red("Failed to query database: %v", err)
+		os.Exit(1)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, color.CyanString("ID\tDOMAIN\tTOOL\tSTARTED\tDURATION\tTOTAL\tNEW\tREVIVED\tDEAD\tSOURCES"))
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	for _, s := range scans {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\n",
+			s.ID,
+			s.Domain,
+			s.Tool,
+			s.StartedAt.Format("2006-01-02 15:04:05"),
+			s.EndedAt.Sub(s.StartedAt).Round(time.Second),
+			s.TotalSubdomains,
+			s.NewSubdomains,
+			s.Reactivated,
+			s.DeadSubdomains,
+			len(s.Sources),
+		)
+	}
+	w.Flush()
+
+	color.Green("\nTotal scans: %d", len(scans))
+}
+
+func runTrackScan(cmd *cobra.Command, args []string) {
+	scanID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		color.Red("Error: invalid scan id %q", args[0])
+		os.Exit(1)
+	}
+
+	db := openTrackDB()
+
+	records, err := db.QueryScanObservations(scanID)
+	if err != nil {
+		color.Red("Failed to query database: %v", err)
+		os.Exit(1)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, color.CyanString("DOMAIN\tSUBDOMAIN\tSOURCES"))
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	for _, r := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.Domain, r.Subdomain, strings.Join(r.Sources, ","))
+	}
+	w.Flush()
+
+	color.Green("\nTotal records: %d", len(records))
+}
+
+func runTrackDiff(cmd *cobra.Command, args []string) {
+	if trackJSON && trackCSV {
+		color.Red("Error: --json and --csv cannot be used together")
+		os.Exit(1)
+	}
+
+	db := openTrackDB()
+
+	diff, err := db.DiffScans(args[0], trackDiffFrom, trackDiffTo)
+	if err != nil {
+		color.Red("Failed to diff scans: %v", err)
+		os.Exit(1)
+	}
+
+	switch {
+	case trackJSON:
+		out := trackDiffJSON{
+			Domain:  diff.Domain,
+			From:    diff.From,
+			To:      diff.To,
+			Added:   append([]string{}, diff.Added...),
+			Removed: append([]string{}, diff.Removed...),
+		}
+		writeJSON(out)
+		return
+
+	case trackCSV:
+		rows := [][]string{{"change", "subdomain"}}
+		for _, host := range diff.Added {
+			rows = append(rows, []string{"added", host})
+		}
+		for _, host := range diff.Removed {
+			rows = append(rows, []string{"removed", host})
+		}
+		writeCSV(rows)
+		return
+	}
+
+	color.Cyan("Scan #%d -> #%d for %s", diff.From, diff.To, diff.Domain)
+	fmt.Println()
+
+	for _, host := range diff.Added {
+		color.Green("+ %s", host)
+	}
+	for _, host := range diff.Removed {
+		color.Red("- %s", host)
+	}
+
+	color.Green("\nAdded: %d, Removed: %d", len(diff.Added), len(diff.Removed))
+}
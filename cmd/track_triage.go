@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samogod/samoscout/pkg/database"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var trackTagRemove bool
+
+var trackTagCmd = &cobra.Command{
+	Use:   "tag <subdomain> <tag>...",
+	Short: "Add or remove tags on a tracked subdomain",
+	Example: `  samoscout track tag vpn.corp.example.com scope:corp owner:netops
+  samoscout track tag vpn.corp.example.com owner:netops --remove`,
+	Args: cobra.MinimumNArgs(2),
+	Run:  runTrackTag,
+}
+
+var trackNoteCmd = &cobra.Command{
+	Use:   "note <subdomain> [text]",
+	Short: "Add a note to a tracked subdomain, or list its notes",
+	Example: `  samoscout track note vpn.corp.example.com "reported to netops, ticket SEC-1234"
+  samoscout track note vpn.corp.example.com`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runTrackNote,
+}
+
+var trackSetStateCmd = &cobra.Command{
+	Use:   "set-state <subdomain> <state>",
+	Short: "Set the triage state of a tracked subdomain",
+	Long: `Set the triage state of a tracked subdomain. Valid states: ` + strings.Join(database.TriageStates, ", ") + `.
+Triage state is never changed by scans, including DEAD/ACTIVE transitions.`,
+	Example: `  samoscout track set-state vpn.corp.example.com in_scope
+  samoscout track set-state staging.example.com false_positive`,
+	Args: cobra.ExactArgs(2),
+	Run:  runTrackSetState,
+}
+
+func init() {
+	trackTagCmd.Flags().BoolVar(&trackTagRemove, "remove", false, "remove the given tags instead of adding them")
+
+	trackCmd.AddCommand(trackTagCmd)
+	trackCmd.AddCommand(trackNoteCmd)
+	trackCmd.AddCommand(trackSetStateCmd)
+}
+
+// tags are stored lower-case and listed comma-separated, so commas and
+// whitespace are not allowed inside a tag.
+func normalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || strings.ContainsAny(tag, ", \t") {
+		return "", fmt.Errorf("invalid tag %q: tags cannot be empty or contain commas or whitespace", tag)
+	}
+	return tag, nil
+}
+
+func runTrackTag(cmd *cobra.Command, args []string) {
+	subdomain := strings.ToLower(strings.TrimSpace(args[0]))
+
+	var tags []string
+	for _, arg := range args[1:] {
+		tag, err := normalizeTag(arg)
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		tags = append(tags, tag)
+	}
+
+	db := openTrackDB()
+
+	if trackTagRemove {
+		removed, err := db.RemoveTags(subdomain, tags)
+		if err != nil {
+			color.Red("Failed to remove tags: %v", err)
+			os.Exit(1)
+		}
+		color.Green("[INF] Removed %d tag(s) from %s", removed, subdomain)
+		return
+	}
+
+	if _, err := db.AddTags(subdomain, tags); err != nil {
+		color.Red("Failed to add tags: %v", err)
+		os.Exit(1)
+	}
+	color.Green("[INF] Tagged %s: %s", subdomain, strings.Join(tags, ", "))
+}
+
+func runTrackNote(cmd *cobra.Command, args []string) {
+	subdomain := strings.ToLower(strings.TrimSpace(args[0]))
+	db := openTrackDB()
+
+	if len(args) > 1 {
+		note := strings.TrimSpace(strings.Join(args[1:], " "))
+		if note == "" {
+			color.Red("Error: note text is empty")
+			os.Exit(1)
+		}
+		if _, err := db.AddNote(subdomain, note); err != nil {
+			color.Red("Failed to add note: %v", err)
+			os.Exit(1)
+		}
+		color.Green("[INF] Note added to %s", subdomain)
+		return
+	}
+
+	notes, err := db.QueryNotes(subdomain)
+	if err != nil {
+		color.Red("Failed to query notes: %v", err)
+		os.Exit(1)
+	}
+
+	if len(notes) == 0 {
+		color.Yellow("[INF] No notes for %s.", subdomain)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, color.CyanString("CREATED_AT\tDOMAIN\tNOTE"))
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+	for _, n := range notes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", n.CreatedAt.Format("2006-01-02 15:04:05"), n.Domain, n.Note)
+	}
+	w.Flush()
+
+	color.Green("\nTotal notes: %d", len(notes))
+}
+
+func runTrackSetState(cmd *cobra.Command, args []string) {
+	subdomain := strings.ToLower(strings.TrimSpace(args[0]))
+	state := strings.ToLower(strings.TrimSpace(args[1]))
+
+	if !database.ValidTriageState(state) {
+		color.Red("Error: invalid state %q (valid: %s)", state, strings.Join(database.TriageStates, ", "))
+		os.Exit(1)
+	}
+
+	db := openTrackDB()
+
+	if _, err := db.SetTriageState(subdomain, state); err != nil {
+		color.Red("Failed to set state: %v", err)
+		os.Exit(1)
+	}
+	color.Green("[INF] %s is now %s", subdomain, state)
+}
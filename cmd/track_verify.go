@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/orchestrator"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyAll     bool
+	verifyHttpx   bool
+	verifyThreads int
+	verifyTimeout int
+)
+
+var trackVerifyCmd = &cobra.Command{
+	Use:   "verify [domain]",
+	Short: "Re-resolve tracked hosts and update their status without a scan",
+	Long: `Re-resolve every tracked subdomain of a domain (or of all domains with --all) and set
+its status from live evidence: hosts that resolve become ACTIVE, hosts that return NXDOMAIN
+become DEAD. Lookups that time out or fail are retried once and otherwise left unchanged.
+With --httpx the resolving hosts are also probed and their HTTP status and title stored.
+No passive sources are queried, so no API quota is used.`,
+	Example: `  samoscout track verify example.com
+  samoscout track verify example.com --httpx
+  samoscout track verify --all --threads 100`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runTrackVerify,
+}
+
+func init() {
+	trackVerifyCmd.Flags().BoolVar(&verifyAll, "all", false, "verify every tracked domain")
+	trackVerifyCmd.Flags().BoolVar(&verifyHttpx, "httpx", false, "probe resolving hosts over HTTP and store status and title")
+	trackVerifyCmd.Flags().IntVar(&verifyThreads, "threads", 50, "concurrent DNS lookups")
+	trackVerifyCmd.Flags().IntVar(&verifyTimeout, "timeout", 5, "DNS lookup timeout in seconds")
+	trackCmd.AddCommand(trackVerifyCmd)
+}
+
+func runTrackVerify(cmd *cobra.Command, args []string) {
+	if !verifyAll && len(args) == 0 {
+		color.Red("Error: either provide a domain or use --all flag")
+		cmd.Help()
+		os.Exit(1)
+	}
+
+	if verifyAll && len(args) > 0 {
+		color.Red("Error: cannot use both domain and --all flag together")
+		cmd.Help()
+		os.Exit(1)
+	}
+
+	orch, err := orchestrator.NewOrchestrator(configFile)
+	if err != nil {
+		color.Red("Failed to initialize orchestrator: %v", err)
+		os.Exit(1)
+	}
+	configureLogging(orch.GetConfig().Logging)
+
+	db := orch.GetDB()
+	if db == nil || !db.IsEnabled() {
+		color.Red("Error: Database is not enabled. Please enable it in config.yaml")
+		os.Exit(1)
+	}
+
+	var domains []string
+	if verifyAll {
+		records, err := db.QueryAllSubdomains("")
+		if err != nil {
+			color.Red("Failed to query database: %v", err)
+			os.Exit(1)
+		}
+		seen := make(map[string]bool)
+		for _, r := range records {
+			if !seen[r.Domain] {
+				seen[r.Domain] = true
+				domains = append(domains, r.Domain)
+			}
+		}
+		sort.Strings(domains)
+	} else {
+		domains = []string{strings.ToLower(strings.TrimSpace(args[0]))}
+	}
+
+	var summaries []*database.VerifySummary
+	failed := false
+	for _, domain := range domains {
+		color.Cyan("[INF] Verifying tracked subdomains of %s", domain)
+
+		summary, err := orch.VerifyTracked(orchestrator.VerifyOptions{
+			Domain:  domain,
+			Probe:   verifyHttpx,
+			Threads: verifyThreads,
+			Timeout: time.Duration(verifyTimeout) * time.Second,
+		})
+		if err != nil {
+			color.Red("Verification of %s failed: %v", domain, err)
+			failed = true
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	header := "DOMAIN\tCHECKED\tALIVE\tREVIVED\tDIED\tUNKNOWN"
+	if verifyHttpx {
+		header += "\tHTTP"
+	}
+	fmt.Fprintln(w, color.CyanString(header))
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	for _, s := range summaries {
+		line := fmt.Sprintf("%s\t%d\t%d\t%d\t%d\t%d", s.Domain, s.Checked, s.Alive, s.Revived, s.Died, s.Unknown)
+		if verifyHttpx {
+			line += fmt.Sprintf("\t%d", s.Responding)
+		}
+		fmt.Fprintln(w, line)
+	}
+	w.Flush()
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -126,9 +126,7 @@ func writeWordlistFile(words []string, filePath string) error {
 func CleanAndSaveWordlist(inputFile, outputFile string, verbose bool) error {
 	cleaner := NewWordlistCleaner()
 
-	if verbose {
-		fmt.Printf("[DBG] cleaning wordlist: %s\n", inputFile)
-	}
+	log.Debugf("cleaning wordlist: %s", inputFile)
 
 	originalSize, newSize, err := cleaner.CleanWordlistFile(inputFile, outputFile)
 	if err != nil {
@@ -137,9 +135,7 @@ func CleanAndSaveWordlist(inputFile, outputFile string, verbose bool) error {
 
 	removed := originalSize - newSize
 
-	if verbose {
-		fmt.Printf("[DBG] removed %d lines, wordlist now has %d lines\n", removed, newSize)
-	}
+	log.Debugf("removed %d lines, wordlist now has %d lines", removed, newSize)
 
 	return nil
 }
@@ -6,19 +6,15 @@ import (
 	"sync"
 )
 
-func RunDeepEnumeration(resolvedSubdomains []string, outputDir, domain string, verbose bool) ([]string, error) {
-	if verbose {
-		fmt.Println("[DBG] starting deep level enumeration...")
-	}
+func RunDeepEnumeration(resolvedSubdomains []string, outputDir, domain string, verbose bool, inScope func(string) bool) ([]string, error) {
+	log.Debugf("starting deep level enumeration...")
 
 	finalSubsFile := filepath.Join(outputDir, "final_subdomains.txt")
 	if err := writeSubdomainsToFile(resolvedSubdomains, finalSubsFile); err != nil {
 		return nil, fmt.Errorf("failed to write final subdomains: %w", err)
 	}
 
-	if verbose {
-		fmt.Printf("[DBG] running parallel dsieve (f3, f4, f5) on %d subdomains...\n", len(resolvedSubdomains))
-	}
+	log.Debugf("running parallel dsieve (f3, f4, f5) on %d subdomains...", len(resolvedSubdomains))
 
 	type dsieveResult struct {
 		factor     string
@@ -53,7 +49,7 @@ func RunDeepEnumeration(resolvedSubdomains []string, outputDir, domain string, v
 			}
 
 			if verbose && err == nil {
-				fmt.Printf("[DBG] dsieve f%s: generated %d subdomains\n", factor, len(subs))
+				log.Debugf("dsieve f%s: generated %d subdomains", factor, len(subs))
 			}
 		}(f.factor, f.level)
 	}
@@ -79,11 +75,11 @@ func RunDeepEnumeration(resolvedSubdomains []string, outputDir, domain string, v
 		}
 	}
 
-	fmt.Printf("[DEEP] Dsieve generated: f3=%d, f4=%d, f5=%d subdomains\n", len(f3), len(f4), len(f5))
+	f3, f4, f5 = filterScope(f3, inScope), filterScope(f4, inScope), filterScope(f5, inScope)
 
-	if verbose {
-		fmt.Println("[DBG] downloading and merging Trickest wordlists...")
-	}
+	log.Infof("Dsieve generated: f3=%d, f4=%d, f5=%d subdomains", len(f3), len(f4), len(f5))
+
+	log.Debugf("downloading and merging Trickest wordlists...")
 
 	level2, level3, level4plus, err := DownloadAndMergeTrickestWordlists(outputDir, verbose)
 	if err != nil {
@@ -93,40 +89,38 @@ func RunDeepEnumeration(resolvedSubdomains []string, outputDir, domain string, v
 	normalResolverFile := filepath.Join(outputDir, "resolvers.txt")
 	trustedResolverFile := filepath.Join(outputDir, "resolvers_trusted.txt")
 
-	if verbose {
-		fmt.Println("[DBG] running puredns bruteforce (3 levels)...")
-	}
+	log.Debugf("running puredns bruteforce (3 levels)...")
 
-	fmt.Println("[DEEP] Starting bruteforce with level2 wordlist...")
+	log.Infof("Starting bruteforce with level2 wordlist...")
 	resolvedF3, err := RunPurednsBruteforce(level2, f3, normalResolverFile, trustedResolverFile, outputDir, domain, verbose)
 	if err != nil {
 		return nil, fmt.Errorf("bruteforce f3 failed: %w", err)
 	}
-	fmt.Printf("[DEEP] Level2 bruteforce: %d/%d resolved\n", len(resolvedF3), len(f3))
+	log.Infof("Level2 bruteforce: %d/%d resolved", len(resolvedF3), len(f3))
 
 	outputF3File := filepath.Join(outputDir, "deep_f3_resolved.txt")
 	if err := writeSubdomainsToFile(resolvedF3, outputF3File); err != nil {
 		return nil, fmt.Errorf("failed to write f3 results: %w", err)
 	}
 
-	fmt.Println("[DEEP] Starting bruteforce with level3 wordlist...")
+	log.Infof("Starting bruteforce with level3 wordlist...")
 	resolvedF4, err := RunPurednsBruteforce(level3, f4, normalResolverFile, trustedResolverFile, outputDir, domain, verbose)
 	if err != nil {
 		return nil, fmt.Errorf("bruteforce f4 failed: %w", err)
 	}
-	fmt.Printf("[DEEP] Level3 bruteforce: %d/%d resolved\n", len(resolvedF4), len(f4))
+	log.Infof("Level3 bruteforce: %d/%d resolved", len(resolvedF4), len(f4))
 
 	outputF4File := filepath.Join(outputDir, "deep_f4_resolved.txt")
 	if err := writeSubdomainsToFile(resolvedF4, outputF4File); err != nil {
 		return nil, fmt.Errorf("failed to write f4 results: %w", err)
 	}
 
-	fmt.Println("[DEEP] Starting bruteforce with level4plus wordlist...")
+	log.Infof("Starting bruteforce with level4plus wordlist...")
 	resolvedF5, err := RunPurednsBruteforce(level4plus, f5, normalResolverFile, trustedResolverFile, outputDir, domain, verbose)
 	if err != nil {
 		return nil, fmt.Errorf("bruteforce f5 failed: %w", err)
 	}
-	fmt.Printf("[DEEP] Level4plus bruteforce: %d/%d resolved\n", len(resolvedF5), len(f5))
+	log.Infof("Level4plus bruteforce: %d/%d resolved", len(resolvedF5), len(f5))
 
 	outputF5File := filepath.Join(outputDir, "deep_f5_resolved.txt")
 	if err := writeSubdomainsToFile(resolvedF5, outputF5File); err != nil {
@@ -135,9 +129,7 @@ func RunDeepEnumeration(resolvedSubdomains []string, outputDir, domain string, v
 
 	allDeepSubdomains := MergeAndDeduplicate(resolvedF3, resolvedF4, resolvedF5)
 
-	if verbose {
-		fmt.Printf("[DBG] deep enumeration complete: %d total unique subdomains\n", len(allDeepSubdomains))
-	}
+	log.Debugf("deep enumeration complete: %d total unique subdomains", len(allDeepSubdomains))
 
 	return allDeepSubdomains, nil
 }
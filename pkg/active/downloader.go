@@ -134,9 +134,7 @@ func MergeWordlistFiles(file1, file2, outputFile string) error {
 }
 
 func DownloadAndMergeTrickestWordlists(outputDir string, verbose bool) (level2, level3, level4plus string, err error) {
-	if verbose {
-		fmt.Println("[DBG] downloading Trickest wordlists...")
-	}
+	log.Debugf("downloading Trickest wordlists...")
 
 	invLevel2 := filepath.Join(outputDir, "trickest_inventory_level2.txt")
 	invLevel3 := filepath.Join(outputDir, "trickest_inventory_level3.txt")
@@ -159,9 +157,7 @@ func DownloadAndMergeTrickestWordlists(outputDir string, verbose bool) (level2,
 	}
 
 	for _, dl := range downloads {
-		if verbose {
-			fmt.Printf("[DBG] downloading %s...\n", dl.name)
-		}
+		log.Debugf("downloading %s...", dl.name)
 		if err := DownloadTrickestWordlist(dl.url, dl.path); err != nil {
 			return "", "", "", fmt.Errorf("failed to download %s: %w", dl.name, err)
 		}
@@ -171,9 +167,7 @@ func DownloadAndMergeTrickestWordlists(outputDir string, verbose bool) (level2,
 	level3 = filepath.Join(outputDir, "trickest_level3_merged.txt")
 	level4plus = filepath.Join(outputDir, "trickest_level4plus_merged.txt")
 
-	if verbose {
-		fmt.Println("[DBG] merging wordlists...")
-	}
+	log.Debugf("merging wordlists...")
 
 	if err := MergeWordlistFiles(invLevel2, cloudLevel2, level2); err != nil {
 		return "", "", "", fmt.Errorf("failed to merge level2: %w", err)
@@ -185,9 +179,7 @@ func DownloadAndMergeTrickestWordlists(outputDir string, verbose bool) (level2,
 		return "", "", "", fmt.Errorf("failed to merge level4plus: %w", err)
 	}
 
-	if verbose {
-		fmt.Println("[DBG] wordlist merge complete")
-	}
+	log.Debugf("wordlist merge complete")
 
 	return level2, level3, level4plus, nil
 }
@@ -8,6 +8,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/samogod/samoscout/pkg/domainutil"
 )
 
 // Dsieve filters and enriches subdomains by level
@@ -51,15 +53,19 @@ func RunDsieve(inputFile, outputFile string, level string, top int) ([]string, e
 			targetLevel = minLevel
 		}
 
+		// levels count the public suffix as one label, the labels inside it
+		// are not domains of their own
+		depth := domainutil.Level(strings.Join(parts, "."))
+
 		if top > 0 {
-			for i := range parts {
+			for i := 0; i < depth; i++ {
 				d := strings.Join(parts[i:], ".")
 				subdomainCounts[d]++
 			}
 		}
 
-		for i := range parts {
-			level := len(parts) - i
+		for i := 0; i < depth; i++ {
+			level := depth - i
 			if (minLevel == -1 || level >= minLevel) && (maxLevel == -1 || level <= maxLevel) {
 				d := strings.Join(parts[i:], ".")
 				if !domainMap[d] {
@@ -100,7 +106,7 @@ func extractDomainParts(input string) []string {
 		input = input[:idx]
 	}
 
-	parts := strings.Split(input, ".")
+	parts := strings.Split(strings.ToLower(input), ".")
 
 	var filtered []string
 	for _, part := range parts {
@@ -154,8 +160,7 @@ func parseLevel(level string) (int, int, error) {
 func applyTopFilter(domains []string, counts map[string]int, top int, targetLevel int) []string {
 	var sorted []domainCount
 	for d, c := range counts {
-		parts := strings.Split(d, ".")
-		if len(parts) == targetLevel {
+		if domainutil.Level(d) == targetLevel {
 			sorted = append(sorted, domainCount{d, c})
 		}
 	}
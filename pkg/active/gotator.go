@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/samogod/samoscout/pkg/domainutil"
 )
 
 // Gotator - Subdomain permutation tool
@@ -37,9 +39,7 @@ func RunGotator(subdomainFile, permFile, outputFile string, threads int, verbose
 	gotatorPermutations = DefaultPermutations
 	gotatorMinimizeDuplicates = true
 
-	if verbose {
-		fmt.Printf("[DBG] loaded %d subdomains and %d permutation words\n", len(subdomains), len(gotatorPermutations))
-	}
+	log.Debugf("loaded %d subdomains and %d permutation words", len(subdomains), len(gotatorPermutations))
 
 	var results []string
 	var mu sync.Mutex
@@ -66,9 +66,7 @@ func RunGotator(subdomainFile, permFile, outputFile string, threads int, verbose
 
 	wg.Wait()
 
-	if verbose {
-		fmt.Printf("[DBG] generated %d permutations\n", len(results))
-	}
+	log.Debugf("generated %d permutations", len(results))
 
 	if outputFile != "" {
 		if err := writeGotatorOutput(results, outputFile); err != nil {
@@ -187,7 +185,7 @@ func readGotatorSubdomains(filePath string) ([]string, error) {
 	
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
-		if line != "" && !strings.HasPrefix(line, "#") && len(strings.Split(line, ".")) >= 2 {
+		if line != "" && !strings.HasPrefix(line, "#") && domainutil.RegistrableDomain(line) != "" {
 			subdomains = append(subdomains, line)
 		}
 	}
@@ -19,6 +19,9 @@ type HttpxResult struct {
 	WebServer     string   `json:"webserver"`
 	ContentType   string   `json:"content_type"`
 	ContentLength int      `json:"content_length"`
+
+	// Raw is the complete httpx JSON line, for consumers that need every field.
+	Raw json.RawMessage `json:"-"`
 }
 
 func getHttpxPath() (string, error) {
@@ -47,18 +50,14 @@ func getHttpxPath() (string, error) {
 
 func EnsureHttpx(verbose bool) error {
 	if path, err := getHttpxPath(); err == nil {
-		if verbose {
-			fmt.Printf("[DBG] httpx binary found: %s\n", path)
-		}
+		log.Debugf("httpx binary found: %s", path)
 		return nil
 	}
 
-	if verbose {
-		fmt.Println("[DBG] httpx not found, installing via go install...")
-	}
+	log.Debugf("httpx not found, installing via go install...")
 
 	cmd := exec.Command("go", "install", "-v", "github.com/projectdiscovery/httpx/cmd/httpx@latest")
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
@@ -66,9 +65,9 @@ func EnsureHttpx(verbose bool) error {
 	}
 
 	if path, err := getHttpxPath(); err == nil {
-		fmt.Printf("[ACTIVE] httpx installed successfully: %s\n", path)
+		log.Infof("httpx installed successfully: %s", path)
 	} else {
-		fmt.Println("[ACTIVE] httpx installed successfully")
+		log.Infof("httpx installed successfully")
 	}
 
 	return nil
@@ -104,12 +103,10 @@ func RunHttpx(subdomainFile, outputFile string, verbose bool) ([]HttpxResult, er
 		"-o", absOutputFile,
 	}
 
-	if verbose {
-		fmt.Printf("[DBG] executing: %s %s\n", httpxPath, strings.Join(args, " "))
-	}
+	log.Debugf("executing: %s %s", httpxPath, strings.Join(args, " "))
 
 	cmd := exec.Command(httpxPath, args...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
@@ -146,6 +143,7 @@ func readHttpxResults(filePath string) ([]HttpxResult, error) {
 		if err := json.Unmarshal([]byte(line), &result); err != nil {
 			continue
 		}
+		result.Raw = json.RawMessage(line)
 
 		results = append(results, result)
 	}
@@ -185,7 +183,7 @@ func ProbeHTTPSimple(subdomains []string, outputDir string, verbose bool) ([]str
 		return nil, fmt.Errorf("failed to write subdomains for httpx: %w", err)
 	}
 
-	fmt.Printf("[ACTIVE] Probing HTTP/HTTPS on %d subdomains\n", len(subdomains))
+	log.Infof("Probing HTTP/HTTPS on %d subdomains", len(subdomains))
 
 	outputFile := filepath.Join(outputDir, "active_web_services.txt")
 
@@ -205,9 +203,7 @@ func ProbeHTTPSimple(subdomains []string, outputDir string, verbose bool) ([]str
 		"-o", outputFile,
 	}
 
-	if verbose {
-		fmt.Printf("[DBG] executing: %s %s\n", httpxPath, strings.Join(args, " "))
-	}
+	log.Debugf("executing: %s %s", httpxPath, strings.Join(args, " "))
 
 	cmd := exec.Command(httpxPath, args...)
 	if err := cmd.Run(); err != nil {
@@ -219,7 +215,7 @@ func ProbeHTTPSimple(subdomains []string, outputDir string, verbose bool) ([]str
 		return nil, fmt.Errorf("failed to read httpx output: %w", err)
 	}
 
-	fmt.Printf("[ACTIVE] Found %d active web services\n", len(activeURLs))
+	log.Infof("Found %d active web services", len(activeURLs))
 
 	return activeURLs, nil
 }
@@ -238,7 +234,7 @@ func ProbeHTTP(subdomains []string, outputDir string, inputFileName string, verb
 		return nil, nil, fmt.Errorf("failed to write subdomains for httpx: %w", err)
 	}
 
-	fmt.Printf("[ACTIVE] Starting HTTP probing: %d subdomains\n", len(subdomains))
+	log.Infof("Starting HTTP probing: %d subdomains", len(subdomains))
 
     outputFile := filepath.Join(outputDir, "httpx_results.json")
 	results, err := RunHttpx(subdomainFile, outputFile, verbose)
@@ -251,17 +247,17 @@ func ProbeHTTP(subdomains []string, outputDir string, inputFileName string, verb
 		activeHosts = append(activeHosts, result.Host)
 	}
 
-	fmt.Printf("[ACTIVE] HTTP probing complete: %d/%d hosts are active web services\n",
+	log.Infof("HTTP probing complete: %d/%d hosts are active web services",
 		len(activeHosts), len(subdomains))
 
 	if verbose && len(results) > 0 {
-		fmt.Printf("[DBG] Active web services summary:\n")
+		log.Debugf("Active web services summary:")
 		statusCounts := make(map[int]int)
 		for _, result := range results {
 			statusCounts[result.StatusCode]++
 		}
 		for status, count := range statusCounts {
-			fmt.Printf("[DBG]   HTTP %d: %d hosts\n", status, count)
+			log.Debugf("  HTTP %d: %d hosts", status, count)
 		}
 	}
 
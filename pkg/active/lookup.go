@@ -0,0 +1,169 @@
+package active
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/metrics"
+)
+
+// Resolution is the outcome of resolving one host. NotFound is only set for
+// an authoritative NXDOMAIN; timeouts and server failures leave it false with
+// Err set, so callers can tell "dead" from "unknown".
+type Resolution struct {
+	Addrs    []string
+	NotFound bool
+	Err      error
+}
+
+func (r Resolution) Resolves() bool {
+	return len(r.Addrs) > 0
+}
+
+// LookupHosts resolves hosts with the system resolver and returns the addresses
+// of every host that resolved. It is meant for small re-check batches; bulk
+// resolution of generated candidates goes through puredns.
+func LookupHosts(hosts []string, threads int, timeout time.Duration) map[string][]string {
+	resolved := make(map[string][]string)
+	for host, r := range ResolveHosts(hosts, threads, timeout) {
+		if r.Resolves() {
+			resolved[host] = r.Addrs
+		}
+	}
+	return resolved
+}
+
+// ResolveHosts resolves every host with the system resolver and reports the
+// outcome per host.
+func ResolveHosts(hosts []string, threads int, timeout time.Duration) map[string]Resolution {
+	if threads <= 0 {
+		threads = 50
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	results := make(map[string]Resolution, len(hosts))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, threads)
+
+	for _, host := range hosts {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(h string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			var r Resolution
+			start := time.Now()
+			addrs, err := net.DefaultResolver.LookupHost(ctx, h)
+			metrics.Lookup("system", start)
+			switch {
+			case err == nil && len(addrs) > 0:
+				r.Addrs = addrs
+				metrics.Resolution("system", "resolved", 1)
+			case err == nil:
+				r.NotFound = true
+				metrics.Resolution("system", "not_found", 1)
+			default:
+				var dnsErr *net.DNSError
+				if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+					r.NotFound = true
+					metrics.Resolution("system", "not_found", 1)
+				} else {
+					r.Err = err
+					metrics.Resolution("system", "error", 1)
+				}
+			}
+
+			mu.Lock()
+			results[h] = r
+			mu.Unlock()
+		}(host)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// DNSRecords holds the address and alias records of one host.
+type DNSRecords struct {
+	A     []string
+	AAAA  []string
+	CNAME string
+}
+
+// LookupRecords fetches the A, AAAA and CNAME records of every host. Hosts
+// that do not resolve are absent from the result.
+func LookupRecords(hosts []string, threads int, timeout time.Duration) map[string]DNSRecords {
+	if threads <= 0 {
+		threads = 50
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	results := make(map[string]DNSRecords, len(hosts))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, threads)
+
+	for _, host := range hosts {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(h string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			start := time.Now()
+			addrs, err := net.DefaultResolver.LookupIPAddr(ctx, h)
+			metrics.Lookup("system", start)
+			if err != nil || len(addrs) == 0 {
+				var dnsErr *net.DNSError
+				if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+					metrics.Resolution("system", "error", 1)
+				} else {
+					metrics.Resolution("system", "not_found", 1)
+				}
+				return
+			}
+			metrics.Resolution("system", "resolved", 1)
+
+			var records DNSRecords
+			for _, addr := range addrs {
+				if addr.IP.To4() != nil {
+					records.A = append(records.A, addr.IP.String())
+				} else {
+					records.AAAA = append(records.AAAA, addr.IP.String())
+				}
+			}
+
+			if cname, err := net.DefaultResolver.LookupCNAME(ctx, h); err == nil {
+				cname = strings.TrimSuffix(cname, ".")
+				if !strings.EqualFold(cname, h) {
+					records.CNAME = cname
+				}
+			}
+
+			mu.Lock()
+			results[h] = records
+			mu.Unlock()
+		}(host)
+	}
+
+	wg.Wait()
+	return results
+}
@@ -12,29 +12,21 @@ import (
 // Mksub - Generate subdomain combinations
 // Direct copy from https://github.com/trickest/mksub (MIT License)
 
-func RunMksub(wordlistFile, domain, outputFile string, verbose bool) ([]string, error) {
+func RunMksub(wordlistFile string, domains []string, outputFile string, verbose bool) ([]string, error) {
 	words, err := readWordlist(wordlistFile, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to read wordlist: %w", err)
 	}
 	
-	if verbose {
-		fmt.Printf("[DBG] loaded %d unique words from wordlist\n", len(words))
-	}
-	
-	domains := []string{domain}
+	log.Debugf("loaded %d unique words from wordlist", len(words))
 	
-	if verbose {
-		fmt.Printf("[DBG] target domain: %s\n", domain)
-		fmt.Printf("[DBG] generating combinations: %d words × 1 domain = ~%d subdomains\n", 
-			len(words), len(words))
-	}
+	log.Debugf("target domains: %s", strings.Join(domains, ", "))
+	log.Debugf("generating combinations: %d words × %d domains = ~%d subdomains", 
+			len(words), len(domains), len(words)*len(domains))
 	
 	subdomains := generateSubdomains(words, domains, 1, 100, verbose)
 	
-	if verbose {
-		fmt.Printf("[DBG] generated %d unique subdomains\n", len(subdomains))
-	}
+	log.Debugf("generated %d unique subdomains", len(subdomains))
 	
 	if outputFile != "" {
 		if err := writeSubdomainsList(subdomains, outputFile); err != nil {
@@ -125,7 +117,7 @@ func generateSubdomains(words []string, domains []string, level int, threads int
 					totalProcessed++
 					
 					if verbose && totalProcessed%10000 == 0 {
-						fmt.Printf("[DBG] progress: %d subdomains generated...\r", totalProcessed)
+						log.Debugf("progress: %d subdomains generated...", totalProcessed)
 					}
 					mu.Unlock()
 					}(baseDomain, word)
@@ -134,10 +126,6 @@ func generateSubdomains(words []string, domains []string, level int, threads int
 			
 			wg.Wait()
 			
-			if verbose {
-				fmt.Printf("\n")
-			}
-			
 			currentLevel = make([]string, 0, len(nextLevel))
 			for sub := range nextLevel {
 				currentLevel = append(currentLevel, sub)
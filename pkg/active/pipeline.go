@@ -8,8 +8,12 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/samogod/samoscout/pkg/logging"
 )
 
+var log = logging.Component("active")
+
 type PipelineConfig struct {
 	Domain             string
 	PassiveSubdomains  []string
@@ -19,6 +23,11 @@ type PipelineConfig struct {
 	Verbose            bool
 	DeepEnum           bool
 	CustomWordlistPath string
+	InScope            func(host string) bool
+	// WildcardZones are the zones of "*.zone" names found by sources. They
+	// are bruteforced first with the custom wordlist, unless they turn out to
+	// be DNS wildcards.
+	WildcardZones []string
 }
 
 type PipelineResult struct {
@@ -26,6 +35,7 @@ type PipelineResult struct {
 	DsieveSubdomains   []string
 	MksubSubdomains    []string
 	ActiveSubdomains   []string
+	WildcardDNS        []string
 	TotalNewSubdomains int
 	Duration           time.Duration
 }
@@ -34,9 +44,7 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 	startTime := time.Now()
 	result := &PipelineResult{}
 
-	if config.Verbose {
-		fmt.Println("[DBG] starting active enumeration pipeline...")
-	}
+	log.Debugf("starting active enumeration pipeline...")
 
 	domainDir := filepath.Join(config.OutputDir, config.Domain)
 	if err := os.MkdirAll(domainDir, 0755); err != nil {
@@ -49,21 +57,15 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 	if err := writeSubdomainsToFile(config.PassiveSubdomains, passiveFile); err != nil {
 		return nil, fmt.Errorf("failed to save passive subdomains: %w", err)
 	}
-	if config.Verbose {
-		fmt.Printf("[DBG] saved %d passive subdomains to %s\n", len(config.PassiveSubdomains), passiveFile)
-	}
+	log.Debugf("saved %d passive subdomains to %s", len(config.PassiveSubdomains), passiveFile)
 
-	if config.Verbose {
-		fmt.Println("[DBG] extracting keywords from passive subdomains...")
-	}
+	log.Debugf("extracting keywords from passive subdomains...")
 	keywords, err := ExtractKeywords(config.PassiveSubdomains, config.Domain)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract keywords: %w", err)
 	}
 
-	if config.Verbose {
-		fmt.Println("[DBG] cleaning wordlist...")
-	}
+	log.Debugf("cleaning wordlist...")
 	cleaner := NewWordlistCleaner()
 	cleanedKeywords := cleaner.CleanWordlist(keywords)
 	result.CustomWordlist = cleanedKeywords
@@ -74,32 +76,24 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 	}
 	if config.Verbose {
 		removed := len(keywords) - len(cleanedKeywords)
-		fmt.Printf("[DBG] custom wordlist: %d keywords (%d noise removed)\n", len(cleanedKeywords), removed)
+		log.Debugf("custom wordlist: %d keywords (%d noise removed)", len(cleanedKeywords), removed)
 	}
 
-	if config.Verbose {
-		fmt.Println("[DBG] running dsieve with factor=3...")
-	}
+	log.Debugf("running dsieve with factor=3...")
 	dsieveF3Output := filepath.Join(config.OutputDir, "dsieve_f3.txt")
 	dsieveF3Subdomains, err := runDsieve(passiveFile, dsieveF3Output, config.DsieveTop, 3)
 	if err != nil {
 		return nil, fmt.Errorf("dsieve factor=3 failed: %w", err)
 	}
-	if config.Verbose {
-		fmt.Printf("[DBG] dsieve factor=3: generated %d potential subdomains\n", len(dsieveF3Subdomains))
-	}
+	log.Debugf("dsieve factor=3: generated %d potential subdomains", len(dsieveF3Subdomains))
 
-	if config.Verbose {
-		fmt.Println("[DBG] running dsieve with factor=4...")
-	}
+	log.Debugf("running dsieve with factor=4...")
 	dsieveF4Output := filepath.Join(config.OutputDir, "dsieve_f4.txt")
 	dsieveF4Subdomains, err := runDsieve(passiveFile, dsieveF4Output, config.DsieveTop, config.DsieveFactor)
 	if err != nil {
 		return nil, fmt.Errorf("dsieve factor=4 failed: %w", err)
 	}
-	if config.Verbose {
-		fmt.Printf("[DBG] dsieve factor=4: generated %d potential subdomains\n", len(dsieveF4Subdomains))
-	}
+	log.Debugf("dsieve factor=4: generated %d potential subdomains", len(dsieveF4Subdomains))
 
 	combinedDsieve := MergeAndDeduplicate(dsieveF3Subdomains, dsieveF4Subdomains)
 	result.DsieveSubdomains = combinedDsieve
@@ -107,9 +101,7 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 	var baseWordlistWords []string
 
 	if config.CustomWordlistPath != "" {
-		if config.Verbose {
-			fmt.Printf("[DBG] using custom wordlist from: %s\n", config.CustomWordlistPath)
-		}
+		log.Debugf("using custom wordlist from: %s", config.CustomWordlistPath)
 
 		if _, err := os.Stat(config.CustomWordlistPath); os.IsNotExist(err) {
 			return nil, fmt.Errorf("custom wordlist file not found: %s", config.CustomWordlistPath)
@@ -119,13 +111,9 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to read custom wordlist: %w", err)
 		}
-		if config.Verbose {
-			fmt.Printf("[DBG] custom wordlist: %d words loaded\n", len(baseWordlistWords))
-		}
+		log.Debugf("custom wordlist: %d words loaded", len(baseWordlistWords))
 	} else {
-		if config.Verbose {
-			fmt.Println("[DBG] downloading six2dez default wordlist...")
-		}
+		log.Debugf("downloading six2dez default wordlist...")
 		six2dezFile := filepath.Join(config.OutputDir, "six2dez_wordlist.txt")
 		if err := DownloadSix2dezWordlist(six2dezFile); err != nil {
 			return nil, fmt.Errorf("failed to download six2dez wordlist: %w", err)
@@ -134,42 +122,73 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to read six2dez wordlist: %w", err)
 		}
-		if config.Verbose {
-			fmt.Printf("[DBG] six2dez wordlist: %d words loaded\n", len(baseWordlistWords))
-		}
+		log.Debugf("six2dez wordlist: %d words loaded", len(baseWordlistWords))
 	}
 
-	if config.Verbose {
-		fmt.Println("[DBG] combining wordlists...")
-	}
+	log.Debugf("combining wordlists...")
 	combinedWords := CombineWordlists(cleanedKeywords, baseWordlistWords)
 	combinedWordlistFile := filepath.Join(config.OutputDir, "combined_wordlist.txt")
 	if err := WriteWordlist(combinedWords, combinedWordlistFile); err != nil {
 		return nil, fmt.Errorf("failed to write combined wordlist: %w", err)
 	}
-	if config.Verbose {
-		fmt.Printf("[DBG] combined wordlist: %d unique words\n", len(combinedWords))
-	}
+	log.Debugf("combined wordlist: %d unique words", len(combinedWords))
 
-	if config.Verbose {
-		fmt.Println("[DBG] running mksub for subdomain generation...")
-		fmt.Printf("[DBG] note: using root domain only (%s) to avoid combinatorial explosion\n", config.Domain)
-	}
+	log.Debugf("running mksub for subdomain generation...")
+	log.Debugf("note: using root domain only (%s) to avoid combinatorial explosion", config.Domain)
 	mksubOutput := filepath.Join(config.OutputDir, "mksub_output.txt")
-	mksubSubdomains, err := runMksub(combinedWordlistFile, config.Domain, mksubOutput, config.Verbose)
+	mksubSubdomains, err := runMksub(combinedWordlistFile, []string{config.Domain}, mksubOutput, config.Verbose)
 	if err != nil {
 		return nil, fmt.Errorf("mksub failed: %w", err)
 	}
-	result.MksubSubdomains = mksubSubdomains
-	if config.Verbose {
-		fmt.Printf("[DBG] mksub: generated %d potential subdomains\n", len(mksubSubdomains))
-	}
 
 	passiveSet := make(map[string]bool)
 	for _, sub := range config.PassiveSubdomains {
 		passiveSet[strings.ToLower(sub)] = true
 	}
 
+	// wildcard zones are bruteforced with the custom wordlist only, the
+	// combined one times every zone would crowd out the root domain
+	var zones []string
+	for _, zone := range filterScope(config.WildcardZones, config.InScope) {
+		if !strings.EqualFold(zone, config.Domain) {
+			zones = append(zones, zone)
+		}
+	}
+
+	wildcardDNS := make(map[string]bool)
+	var zoneSubdomains []string
+	if len(zones) > 0 {
+		log.Debugf("checking %d wildcard certificate zones for wildcard DNS...", len(zones))
+		wildcardDNS = DetectWildcards(zones)
+
+		var bruteZones []string
+		for _, zone := range zones {
+			if wildcardDNS[zone] {
+				result.WildcardDNS = append(result.WildcardDNS, zone)
+			} else {
+				bruteZones = append(bruteZones, zone)
+			}
+		}
+		if len(result.WildcardDNS) > 0 {
+			log.Infof("Skipping %d wildcard DNS zones", len(result.WildcardDNS))
+		}
+
+		if len(bruteZones) > 0 && len(cleanedKeywords) > 0 {
+			zoneOutput := filepath.Join(config.OutputDir, "mksub_wildcard_zones.txt")
+			zoneSubdomains, err = runMksub(wordlistFile, bruteZones, zoneOutput, config.Verbose)
+			if err != nil {
+				return nil, fmt.Errorf("mksub failed: %w", err)
+			}
+			zoneSubdomains = MergeAndDeduplicate(filterScope(zoneSubdomains, config.InScope))
+			log.Debugf("mksub: generated %d potential subdomains under %d wildcard zones", len(zoneSubdomains), len(bruteZones))
+		}
+	}
+
+	combinedDsieve = dropWildcarded(combinedDsieve, wildcardDNS, passiveSet)
+	mksubSubdomains = MergeAndDeduplicate(zoneSubdomains, mksubSubdomains)
+	result.MksubSubdomains = mksubSubdomains
+	log.Debugf("mksub: generated %d potential subdomains", len(mksubSubdomains))
+
 	newSubdomains := 0
 	for _, sub := range append(combinedDsieve, mksubSubdomains...) {
 		if !passiveSet[strings.ToLower(sub)] {
@@ -178,29 +197,32 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 	}
 	result.TotalNewSubdomains = newSubdomains
 
+	// candidates under wildcard zones come first so that the limit below
+	// never cuts them
 	allGeneratedSubdomains := MergeAndDeduplicate(
+		zoneSubdomains,
 		config.PassiveSubdomains,
 		combinedDsieve,
 		mksubSubdomains,
 	)
+	allGeneratedSubdomains = filterScope(allGeneratedSubdomains, config.InScope)
 
 	totalGenerated := len(allGeneratedSubdomains)
 	const maxSubdomains = 25000
 
 	if totalGenerated > maxSubdomains {
-		if config.Verbose {
-			fmt.Printf("[DBG] shuffling %d subdomains and limiting to %d for resolution\n", totalGenerated, maxSubdomains)
-		}
+		log.Debugf("shuffling %d subdomains and limiting to %d for resolution", totalGenerated, maxSubdomains)
 
+		rest := allGeneratedSubdomains[len(zoneSubdomains):]
 		rand.Seed(time.Now().UnixNano())
-		rand.Shuffle(len(allGeneratedSubdomains), func(i, j int) {
-			allGeneratedSubdomains[i], allGeneratedSubdomains[j] = allGeneratedSubdomains[j], allGeneratedSubdomains[i]
+		rand.Shuffle(len(rest), func(i, j int) {
+			rest[i], rest[j] = rest[j], rest[i]
 		})
 
 		allGeneratedSubdomains = allGeneratedSubdomains[:maxSubdomains]
-		fmt.Printf("[INF] Limited to %d subdomains (from %d total) for resolution\n", maxSubdomains, totalGenerated)
+		log.Infof("Limited to %d subdomains (from %d total) for resolution", maxSubdomains, totalGenerated)
 	} else {
-		fmt.Printf("[INF] Total subdomains to resolve: %d\n", len(allGeneratedSubdomains))
+		log.Infof("Total subdomains to resolve: %d", len(allGeneratedSubdomains))
 	}
 
 	resolvedSubdomains, err := ResolveDNS(allGeneratedSubdomains, config.OutputDir, config.Domain, config.Verbose)
@@ -208,11 +230,9 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 		return nil, fmt.Errorf("DNS resolution failed: %w", err)
 	}
 
-	fmt.Println("[ACTIVE] Running gotator permutations")
+	log.Infof("Running gotator permutations")
 
-	if config.Verbose {
-		fmt.Printf("[DBG] using %d active subdomains for permutation\n", len(resolvedSubdomains))
-	}
+	log.Debugf("using %d active subdomains for permutation", len(resolvedSubdomains))
 
 	gotatorInputFile := filepath.Join(config.OutputDir, "resolved_subdomains.txt")
 
@@ -222,12 +242,17 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 		return nil, fmt.Errorf("gotator failed: %w", err)
 	}
 
-	fmt.Printf("[ACTIVE] Generated %d permutations\n", len(gotatorPerms))
+	log.Infof("Generated %d permutations", len(gotatorPerms))
 
-	if config.Verbose {
-		fmt.Println("[DBG] resolving gotator permutations with puredns...")
+	if config.InScope != nil || len(wildcardDNS) > 0 {
+		gotatorPerms = dropWildcarded(filterScope(gotatorPerms, config.InScope), wildcardDNS, nil)
+		if err := writeSubdomainsToFile(gotatorPerms, gotatorOutputFile); err != nil {
+			return nil, fmt.Errorf("failed to write in-scope permutations: %w", err)
+		}
 	}
 
+	log.Debugf("resolving gotator permutations with puredns...")
+
 	gotatorResolvedFile := filepath.Join(config.OutputDir, "gotator_resolved.txt")
 	normalResolverFile := filepath.Join(config.OutputDir, "resolvers.txt")
 	trustedResolverFile := filepath.Join(config.OutputDir, "resolvers_trusted.txt")
@@ -237,32 +262,33 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 		return nil, fmt.Errorf("gotator permutation resolution failed: %w", err)
 	}
 
-	fmt.Printf("[ACTIVE] Resolved %d/%d permutations\n", len(gotatorResolved), len(gotatorPerms))
+	log.Infof("Resolved %d/%d permutations", len(gotatorResolved), len(gotatorPerms))
 
 	finalActiveSubdomains := MergeAndDeduplicate(resolvedSubdomains, gotatorResolved)
 	result.ActiveSubdomains = finalActiveSubdomains
 
-	fmt.Printf("[ACTIVE] Enumeration complete: %d total active subdomains\n", len(finalActiveSubdomains))
+	log.Infof("Enumeration complete: %d total active subdomains", len(finalActiveSubdomains))
 
 	if config.DeepEnum {
-		fmt.Println("[DEEP] Starting deep level enumeration...")
+		log.Infof("Starting deep level enumeration...")
 
 		deepSubdomains, err := RunDeepEnumeration(
 			finalActiveSubdomains,
 			config.OutputDir,
 			config.Domain,
 			config.Verbose,
+			config.InScope,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("deep enumeration failed: %w", err)
 		}
 
-		fmt.Printf("[DEEP] Found %d additional subdomains\n", len(deepSubdomains))
+		log.Infof("Found %d additional subdomains", len(deepSubdomains))
 
 		finalActiveSubdomains = MergeAndDeduplicate(finalActiveSubdomains, deepSubdomains)
 		result.ActiveSubdomains = finalActiveSubdomains
 
-		fmt.Printf("[DEEP] Total after deep enumeration: %d subdomains\n", len(finalActiveSubdomains))
+		log.Infof("Total after deep enumeration: %d subdomains", len(finalActiveSubdomains))
 	}
 
 	newActiveSubdomains := 0
@@ -277,6 +303,25 @@ func RunActivePipeline(config PipelineConfig) (*PipelineResult, error) {
 	return result, nil
 }
 
+// filterScope drops the candidates outside inScope before they are resolved
+// or used as bruteforce bases.
+func filterScope(subdomains []string, inScope func(string) bool) []string {
+	if inScope == nil {
+		return subdomains
+	}
+
+	var kept []string
+	for _, subdomain := range subdomains {
+		if inScope(subdomain) {
+			kept = append(kept, subdomain)
+		}
+	}
+	if dropped := len(subdomains) - len(kept); dropped > 0 {
+		log.Debugf("skipped %d out-of-scope candidates", dropped)
+	}
+	return kept
+}
+
 func runDsieve(inputFile, outputFile string, top, factor int) ([]string, error) {
 	levelFilter := fmt.Sprintf("%d", factor)
 
@@ -288,8 +333,8 @@ func runDsieve(inputFile, outputFile string, top, factor int) ([]string, error)
 	return results, nil
 }
 
-func runMksub(wordlistFile, domain, outputFile string, verbose bool) ([]string, error) {
-	results, err := RunMksub(wordlistFile, domain, outputFile, verbose)
+func runMksub(wordlistFile string, domains []string, outputFile string, verbose bool) ([]string, error) {
+	results, err := RunMksub(wordlistFile, domains, outputFile, verbose)
 	if err != nil {
 		return nil, fmt.Errorf("mksub failed: %w", err)
 	}
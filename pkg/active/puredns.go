@@ -7,6 +7,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/samogod/samoscout/pkg/metrics"
 )
 
 func getPureDnsPath() (string, error) {
@@ -35,18 +37,14 @@ func getPureDnsPath() (string, error) {
 
 func EnsurePureDns(verbose bool) error {
 	if path, err := getPureDnsPath(); err == nil {
-		if verbose {
-			fmt.Printf("[DBG] puredns binary found: %s\n", path)
-		}
+		log.Debugf("puredns binary found: %s", path)
 		return nil
 	}
 
-	if verbose {
-		fmt.Println("[DBG] puredns not found, installing via go install...")
-	}
+	log.Debugf("puredns not found, installing via go install...")
 
 	cmd := exec.Command("go", "install", "github.com/d3mondev/puredns/v2@latest")
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
@@ -54,9 +52,9 @@ func EnsurePureDns(verbose bool) error {
 	}
 
 	if path, err := getPureDnsPath(); err == nil {
-		fmt.Printf("[ACTIVE] puredns installed successfully: %s\n", path)
+		log.Infof("puredns installed successfully: %s", path)
 	} else {
-		fmt.Println("[ACTIVE] puredns installed successfully")
+		log.Infof("puredns installed successfully")
 	}
 
 	return nil
@@ -97,15 +95,15 @@ func RunPureDns(subdomainFile, resolverFile, outputFile, domain string, verbose
 		"--wildcard-batch", "1000000",
 	}
 
-	if verbose {
-		fmt.Printf("[DBG] executing: %s %s\n", purednsPath, strings.Join(args, " "))
-	}
+	log.Debugf("executing: %s %s", purednsPath, strings.Join(args, " "))
 
 	cmd := exec.Command(purednsPath, args...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 
+	candidates := countLines(absSubdomainFile)
 	if err := cmd.Run(); err != nil {
+		metrics.Resolution("puredns", "error", candidates)
 		return nil, fmt.Errorf("puredns failed: %w", err)
 	}
 
@@ -113,6 +111,7 @@ func RunPureDns(subdomainFile, resolverFile, outputFile, domain string, verbose
 	if err != nil {
 		return nil, fmt.Errorf("failed to read puredns output: %w", err)
 	}
+	observePureDns(candidates, len(resolvedSubdomains))
 
 	return resolvedSubdomains, nil
 }
@@ -155,15 +154,15 @@ func RunPureDnsWithTrusted(subdomainFile, normalResolverFile, trustedResolverFil
 		"--wildcard-batch", "1000000",
 	}
 
-	if verbose {
-		fmt.Printf("[DBG] executing: %s %s\n", purednsPath, strings.Join(args, " "))
-	}
+	log.Debugf("executing: %s %s", purednsPath, strings.Join(args, " "))
 
 	cmd := exec.Command(purednsPath, args...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 
+	candidates := countLines(absSubdomainFile)
 	if err := cmd.Run(); err != nil {
+		metrics.Resolution("puredns", "error", candidates)
 		return nil, fmt.Errorf("puredns failed: %w", err)
 	}
 
@@ -171,6 +170,7 @@ func RunPureDnsWithTrusted(subdomainFile, normalResolverFile, trustedResolverFil
 	if err != nil {
 		return nil, fmt.Errorf("failed to read puredns output: %w", err)
 	}
+	observePureDns(candidates, len(resolvedSubdomains))
 
 	return resolvedSubdomains, nil
 }
@@ -200,9 +200,7 @@ func ResolveDNS(subdomains []string, outputDir string, domain string, verbose bo
 		return nil, fmt.Errorf("puredns setup failed: %w", err)
 	}
 
-	if verbose {
-		fmt.Println("[DBG] preparing dns resolvers...")
-	}
+	log.Debugf("preparing dns resolvers...")
 	normalResolverFile, trustedResolverFile, err := DownloadResolvers(outputDir, verbose)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare resolvers: %w", err)
@@ -213,7 +211,7 @@ func ResolveDNS(subdomains []string, outputDir string, domain string, verbose bo
 		return nil, fmt.Errorf("failed to write subdomains: %w", err)
 	}
 
-	fmt.Printf("[ACTIVE] Starting DNS resolution: %d subdomains\n", len(subdomains))
+	log.Infof("Starting DNS resolution: %d subdomains", len(subdomains))
 
 	outputFile := filepath.Join(outputDir, "resolved_subdomains.txt")
 	resolvedSubdomains, err := RunPureDnsWithTrusted(subdomainFile, normalResolverFile, trustedResolverFile, outputFile, domain, verbose)
@@ -221,7 +219,7 @@ func ResolveDNS(subdomains []string, outputDir string, domain string, verbose bo
 		return nil, err
 	}
 
-	fmt.Printf("[ACTIVE] DNS resolution complete: %d/%d subdomains resolved\n",
+	log.Infof("DNS resolution complete: %d/%d subdomains resolved",
 		len(resolvedSubdomains), len(subdomains))
 
 	return resolvedSubdomains, nil
@@ -278,15 +276,15 @@ func RunPurednsBruteforce(wordlistFile string, domains []string, normalResolver,
 		"--wildcard-batch", "1000000",
 	}
 
-	if verbose {
-		fmt.Printf("[DBG] executing: %s %s\n", purednsPath, strings.Join(args, " "))
-	}
+	log.Debugf("executing: %s %s", purednsPath, strings.Join(args, " "))
 
 	cmd := exec.Command(purednsPath, args...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 
+	candidates := countLines(absWordlistFile) * len(domains)
 	if err := cmd.Run(); err != nil {
+		metrics.Resolution("puredns", "error", candidates)
 		return nil, fmt.Errorf("puredns bruteforce failed: %w", err)
 	}
 
@@ -294,6 +292,35 @@ func RunPurednsBruteforce(wordlistFile string, domains []string, normalResolver,
 	if err != nil {
 		return nil, fmt.Errorf("failed to read puredns output: %w", err)
 	}
+	observePureDns(candidates, len(resolvedSubdomains))
 
 	return resolvedSubdomains, nil
 }
+
+// observePureDns counts the candidates of a puredns run. puredns does not
+// report failed queries, so everything that did not resolve is not_found.
+func observePureDns(candidates, resolved int) {
+	metrics.Resolution("puredns", "resolved", resolved)
+	if candidates > resolved {
+		metrics.Resolution("puredns", "not_found", candidates-resolved)
+	}
+}
+
+// countLines returns the number of non-empty lines of a candidate file, or
+// 0 when it cannot be read.
+func countLines(filePath string) int {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			count++
+		}
+	}
+	return count
+}
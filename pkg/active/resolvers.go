@@ -24,15 +24,11 @@ func DownloadResolvers(outputDir string, verbose bool) (string, string, error) {
 	trustedExists := isResolverFileFresh(trustedResolverFile)
 
 	if normalExists && trustedExists {
-		if verbose {
-			fmt.Println("[DBG] using cached resolver files (fresh within 24h)")
-		}
+		log.Debugf("using cached resolver files (fresh within 24h)")
 		return normalResolverFile, trustedResolverFile, nil
 	}
 
-	if verbose {
-		fmt.Println("[DBG] resolver cache expired or missing, downloading fresh resolvers...")
-	}
+	log.Debugf("resolver cache expired or missing, downloading fresh resolvers...")
 
 	normalURLs := []string{
 		ResolverTrickest,
@@ -43,15 +39,11 @@ func DownloadResolvers(outputDir string, verbose bool) (string, string, error) {
 	normalSet := make(map[string]bool)
 
 	for i, url := range normalURLs {
-		if verbose {
-			fmt.Printf("[DBG] downloading normal resolver list %d/2 from %s\n", i+1, url)
-		}
+		log.Debugf("downloading normal resolver list %d/2 from %s", i+1, url)
 
 		resolvers, err := downloadResolverList(url)
 		if err != nil {
-			if verbose {
-				fmt.Printf("[DBG] warning: failed to download %s: %v\n", url, err)
-			}
+			log.Warnf("failed to download %s: %v", url, err)
 			continue
 		}
 
@@ -62,23 +54,17 @@ func DownloadResolvers(outputDir string, verbose bool) (string, string, error) {
 			}
 		}
 
-		if verbose {
-			fmt.Printf("[DBG] downloaded %d resolvers from source %d/2\n", len(resolvers), i+1)
-		}
+		log.Debugf("downloaded %d resolvers from source %d/2", len(resolvers), i+1)
 	}
 
-	if verbose {
-		fmt.Println("[DBG] downloading trusted resolver list from trickest...")
-	}
+	log.Debugf("downloading trusted resolver list from trickest...")
 
 	trustedResolvers, err := downloadResolverList(ResolverTrickestTrusted)
 	if err != nil {
-		if verbose {
-			fmt.Printf("[DBG] warning: failed to download trusted resolvers: %v\n", err)
-		}
+		log.Warnf("failed to download trusted resolvers: %v", err)
 		trustedResolvers = []string{}
 	} else if verbose {
-		fmt.Printf("[DBG] downloaded %d trusted resolvers\n", len(trustedResolvers))
+		log.Debugf("downloaded %d trusted resolvers", len(trustedResolvers))
 	}
 
 	if len(normalResolvers) == 0 && len(trustedResolvers) == 0 {
@@ -89,18 +75,14 @@ func DownloadResolvers(outputDir string, verbose bool) (string, string, error) {
 		if err := writeResolvers(normalResolvers, normalResolverFile); err != nil {
 			return "", "", fmt.Errorf("failed to write normal resolvers: %w", err)
 		}
-		if verbose {
-			fmt.Printf("[DBG] %d normal resolvers saved to %s\n", len(normalResolvers), normalResolverFile)
-		}
+		log.Debugf("%d normal resolvers saved to %s", len(normalResolvers), normalResolverFile)
 	}
 
 	if len(trustedResolvers) > 0 {
 		if err := writeResolvers(trustedResolvers, trustedResolverFile); err != nil {
 			return "", "", fmt.Errorf("failed to write trusted resolvers: %w", err)
 		}
-		if verbose {
-			fmt.Printf("[DBG] %d trusted resolvers saved to %s\n", len(trustedResolvers), trustedResolverFile)
-		}
+		log.Debugf("%d trusted resolvers saved to %s", len(trustedResolvers), trustedResolverFile)
 	}
 
 	return normalResolverFile, trustedResolverFile, nil
@@ -0,0 +1,59 @@
+package active
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// DetectWildcards reports which zones answer for any name below them, by
+// resolving a random label under each zone.
+func DetectWildcards(zones []string) map[string]bool {
+	probes := make(map[string]string, len(zones))
+	hosts := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		probe := fmt.Sprintf("samoscout-%x.%s", rand.Int63(), zone)
+		probes[probe] = zone
+		hosts = append(hosts, probe)
+	}
+
+	wildcard := make(map[string]bool)
+	for probe := range LookupHosts(hosts, 50, 5*time.Second) {
+		wildcard[probes[probe]] = true
+	}
+	return wildcard
+}
+
+// underWildcard reports whether host is strictly below one of zones. The zone
+// itself is a real name and does not count.
+func underWildcard(host string, zones map[string]bool) bool {
+	host = strings.ToLower(host)
+	for i := strings.IndexByte(host, '.'); i >= 0; i = strings.IndexByte(host, '.') {
+		host = host[i+1:]
+		if zones[host] {
+			return true
+		}
+	}
+	return false
+}
+
+// dropWildcarded removes the generated names below DNS wildcard zones, which
+// resolve whether they exist or not. Names in keep were found by sources and
+// stay.
+func dropWildcarded(subdomains []string, zones map[string]bool, keep map[string]bool) []string {
+	if len(zones) == 0 {
+		return subdomains
+	}
+
+	var kept []string
+	for _, subdomain := range subdomains {
+		if keep[strings.ToLower(subdomain)] || !underWildcard(subdomain, zones) {
+			kept = append(kept, subdomain)
+		}
+	}
+	if dropped := len(subdomains) - len(kept); dropped > 0 {
+		log.Debugf("skipped %d candidates under wildcard DNS zones", dropped)
+	}
+	return kept
+}
@@ -6,19 +6,24 @@ import (
 	"os"
 	"sort"
 	"strings"
+
+	"github.com/samogod/samoscout/pkg/domainutil"
 )
 
 func ExtractKeywords(subdomains []string, rootDomain string) ([]string, error) {
 	wordCount := make(map[string]int)
 	
 	for _, subdomain := range subdomains {
-		subdomain = strings.ToLower(strings.TrimSpace(subdomain))
-		
-		if strings.HasSuffix(subdomain, "."+rootDomain) {
-			subdomain = strings.TrimSuffix(subdomain, "."+rootDomain)
-		} else if subdomain == rootDomain {
+		// hosts outside rootDomain only give the labels left of their own
+		// registrable domain
+		label, ok := domainutil.Subdomain(subdomain, rootDomain)
+		if !ok {
+			label, ok = domainutil.Subdomain(subdomain, "")
+		}
+		if !ok || label == "" {
 			continue
 		}
+		subdomain = label
 		
 		parts := strings.Split(subdomain, ".")
 		for _, part := range parts {
@@ -10,10 +10,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/samogod/samoscout/pkg/logging"
+
 	"gopkg.in/yaml.v3"
 )
 
-var DebugLog func(string, ...interface{})
+var log = logging.Component("config")
 
 type Config struct {
 	APIKeys           APIKeys           `yaml:"api_keys"`
@@ -22,6 +24,13 @@ type Config struct {
 	LLMEnumeration    LLMEnumeration    `yaml:"llm_enumeration"`
 	Database          Database          `yaml:"database"`
     Elasticsearch     Elasticsearch     `yaml:"elasticsearch"`
+	Sinks             []Sink            `yaml:"sinks"`
+	Notifications     Notifications     `yaml:"notifications"`
+	Server            Server            `yaml:"server"`
+	CTLog             CTLog             `yaml:"ctlog"`
+	Metrics           Metrics           `yaml:"metrics"`
+	Tracing           Tracing           `yaml:"tracing"`
+	Logging           Logging           `yaml:"logging"`
 }
 
 type APIKeys struct {
@@ -63,8 +72,14 @@ type APIKeys struct {
 	ZoomEyeAPI      string `yaml:"zoomeyeapi"`
 }
 
+// DefaultSettings.Scope is the scope file of the scans, CT log discoveries
+// and imports that do not name their own. PSL is a Public Suffix List file
+// replacing the embedded one; without it the copy left by "samoscout psl
+// update" is used if there is one.
 type DefaultSettings struct {
-	Timeout int `yaml:"timeout"`
+	Timeout int    `yaml:"timeout"`
+	Scope   string `yaml:"scope"`
+	PSL     string `yaml:"psl"`
 }
 
 type ActiveEnumeration struct {
@@ -75,19 +90,129 @@ type ActiveEnumeration struct {
 }
 
 type Database struct {
-	Enabled  bool   `yaml:"enabled"`
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	User     string `yaml:"user"`
-	Password string `yaml:"password"`
+	Enabled     bool       `yaml:"enabled"`
+	Driver      string     `yaml:"driver"`
+	Path        string     `yaml:"path"`
+	Host        string     `yaml:"host"`
+	Port        int        `yaml:"port"`
+	User        string     `yaml:"user"`
+	Password    string     `yaml:"password"`
+	SSLMode     string     `yaml:"sslmode"`
+	AutoMigrate bool       `yaml:"auto_migrate"`
+	DeadPolicy  DeadPolicy `yaml:"dead_policy"`
+}
+
+// DeadPolicy decides when a host missing from scans is marked DEAD. Settings
+// missing from the config file keep the values of DefaultDeadPolicy.
+type DeadPolicy struct {
+	MinMisses          int  `yaml:"min_misses"`
+	DNSCheck           bool `yaml:"dns_check"`
+	SkipPartialScans   bool `yaml:"skip_partial_scans"`
+	SkipOnSourceErrors bool `yaml:"skip_on_source_errors"`
+}
+
+var DefaultDeadPolicy = DeadPolicy{
+	MinMisses:        2,
+	SkipPartialScans: true,
 }
 
 type Elasticsearch struct {
-    Enabled  bool   `yaml:"enabled"`
-    URL      string `yaml:"url"`
-    Username string `yaml:"username"`
-    Password string `yaml:"password"`
-    Index    string `yaml:"index"`
+    Enabled         bool   `yaml:"enabled"`
+    URL             string `yaml:"url"`
+    Username        string `yaml:"username"`
+    Password        string `yaml:"password"`
+    Index           string `yaml:"index"`
+    IndexSubdomains bool   `yaml:"index_subdomains"`
+    SubdomainIndex  string `yaml:"subdomain_index"`
+}
+
+// Sink configures one result destination. Type selects the implementation;
+// the remaining fields are used by the types that need them.
+type Sink struct {
+	Type      string            `yaml:"type"`
+	Name      string            `yaml:"name"`
+	Events    []string          `yaml:"events"`
+	Retries   int               `yaml:"retries"`
+	Backoff   int               `yaml:"backoff"`
+	Path      string            `yaml:"path"`
+	Format    string            `yaml:"format"`
+	URL       string            `yaml:"url"`
+	Headers   map[string]string `yaml:"headers"`
+	BatchSize int               `yaml:"batch_size"`
+	Timeout   int               `yaml:"timeout"`
+}
+
+type Notifications struct {
+	Enabled  bool      `yaml:"enabled"`
+	MaxHosts int       `yaml:"max_hosts"`
+	Webhooks []Webhook `yaml:"webhooks"`
+}
+
+// Webhook is one notification target. Domains routes it: when set, only
+// changes of matching domains ("example.com" or "*.example.com") are sent.
+type Webhook struct {
+	Name      string            `yaml:"name"`
+	URL       string            `yaml:"url"`
+	Format    string            `yaml:"format"`
+	Template  string            `yaml:"template"`
+	Events    []string          `yaml:"events"`
+	Domains   []string          `yaml:"domains"`
+	Headers   map[string]string `yaml:"headers"`
+	RateLimit float64           `yaml:"rate_limit"`
+	Retries   int               `yaml:"retries"`
+	Timeout   int               `yaml:"timeout"`
+}
+
+// Server configures "samoscout serve". Requests must carry one of Tokens.
+// The wordlist and scope of a request name files in FilesDir; without it
+// they are rejected.
+type Server struct {
+	Listen      string   `yaml:"listen"`
+	Tokens      []string `yaml:"tokens"`
+	Concurrency int      `yaml:"concurrency"`
+	FilesDir    string   `yaml:"files_dir"`
+}
+
+// CTLog configures "samoscout ctlog". Start is where a log that was never
+// read begins: "latest" (the current tree size) or "beginning".
+type CTLog struct {
+	Logs         []CTLogSource `yaml:"logs"`
+	Domains      []string      `yaml:"domains"`
+	PollInterval int           `yaml:"poll_interval"`
+	BatchSize    int           `yaml:"batch_size"`
+	Start        string        `yaml:"start"`
+}
+
+type CTLogSource struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+// Metrics configures the Prometheus endpoint of "samoscout monitor" and
+// "samoscout ctlog"; an empty Listen disables it. "samoscout serve" always
+// serves /metrics on its API address.
+type Metrics struct {
+	Listen string `yaml:"listen"`
+}
+
+// Tracing configures OpenTelemetry span export. Endpoint is the OTLP/HTTP
+// collector URL, e.g. http://localhost:4318; when empty the standard
+// OTEL_EXPORTER_OTLP_* environment variables apply.
+type Tracing struct {
+	Enabled     bool              `yaml:"enabled"`
+	Endpoint    string            `yaml:"endpoint"`
+	Headers     map[string]string `yaml:"headers"`
+	ServiceName string            `yaml:"service_name"`
+	SampleRatio float64           `yaml:"sample_ratio"`
+}
+
+// Logging configures the log output of every component. Level is debug,
+// info, warn or error, Format is text or json, and an empty File means
+// stderr. The --log-* flags take precedence.
+type Logging struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+	File   string `yaml:"file"`
 }
 
 type LLMEnumeration struct {
@@ -99,6 +224,10 @@ type LLMEnumeration struct {
 	Temperature     float32 `yaml:"temperature"`
 	RunAfterPassive bool    `yaml:"run_after_passive"`
 	RunAfterActive  bool    `yaml:"run_after_active"`
+	ModelPath       string  `yaml:"model_path"`
+	ModelSHA256     string  `yaml:"model_sha256"`
+	TokenizerSHA256 string  `yaml:"tokenizer_sha256"`
+	ConfigSHA256    string  `yaml:"config_sha256"`
 }
 
 type Manager struct {
@@ -119,17 +248,13 @@ func (m *Manager) LoadConfig() error {
 		m.configPath = m.findConfigFile()
 	}
 
-	if DebugLog != nil {
-		DebugLog("loading provider config from %s", m.configPath)
-	}
+	log.Debugf("loading provider config from %s", m.configPath)
 
 	if _, err := os.Stat(m.configPath); os.IsNotExist(err) {
 		if createErr := m.createDefaultConfig(); createErr != nil {
 			return fmt.Errorf("config file not found at %s and failed to create default: %w", m.configPath, createErr)
 		}
-		if DebugLog != nil {
-			DebugLog("created default config file at %s", m.configPath)
-		}
+		log.Debugf("created default config file at %s", m.configPath)
 	}
 
 	data, err := os.ReadFile(m.configPath)
@@ -137,7 +262,8 @@ func (m *Manager) LoadConfig() error {
 		return fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var config Config
+	// keys missing from the file keep these values
+	config := Config{Database: Database{DeadPolicy: DefaultDeadPolicy}}
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return fmt.Errorf("failed to parse config file: %w", err)
 	}
@@ -146,7 +272,7 @@ func (m *Manager) LoadConfig() error {
 		return fmt.Errorf("config validation failed: %w", err)
 	}
 
-	if DebugLog != nil {
+	if log.DebugEnabled() {
 		m.logFoundAPIKeys(&config.APIKeys)
 	}
 
@@ -168,7 +294,7 @@ func (m *Manager) logFoundAPIKeys(apiKeys *APIKeys) {
 		}
 
 		if field.Kind() == reflect.String && field.String() != "" {
-			DebugLog("api key(s) found for %s.", yamlTag)
+			log.Debugf("api key(s) found for %s.", yamlTag)
 		}
 	}
 }
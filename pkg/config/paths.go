@@ -95,3 +95,11 @@ func GetLLMCacheDir() string {
 	return filepath.Join(GetCacheDir(), "llm")
 }
 
+func GetPSLCachePath() string {
+	return filepath.Join(GetCacheDir(), "public_suffix_list.dat")
+}
+
+func GetDefaultTrackDBPath() string {
+	return filepath.Join(GetConfigDir(), "samoscout_track.db")
+}
+
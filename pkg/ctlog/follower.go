@@ -0,0 +1,208 @@
+package ctlog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/logging"
+)
+
+var log = logging.Component("ctlog")
+
+type Log struct {
+	Name string
+	URL  string
+}
+
+// Discovery is the hosts one batch of a log holds for one watched domain.
+type Discovery struct {
+	Log    string
+	Domain string
+	Hosts  []string
+}
+
+type Options struct {
+	Logs         []Log
+	Domains      []string
+	PollInterval time.Duration
+	BatchSize    int
+	// FromBeginning makes logs that were never read start at entry 0 instead
+	// of the current tree size.
+	FromBeginning bool
+}
+
+// Follower tails CT logs and reports certificate names under the watched
+// domains. The position of each log is stored in the tracking database after
+// every batch, so a restarted follower resumes where it stopped; a batch whose
+// discoveries could not be handled is read again.
+type Follower struct {
+	db      *database.DB
+	options Options
+	matcher *Matcher
+
+	OnDiscovery func(ctx context.Context, d Discovery) error
+	OnError     func(log Log, err error)
+}
+
+func New(db *database.DB, options Options) *Follower {
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Minute
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 256
+	}
+	for i, l := range options.Logs {
+		if l.Name == "" {
+			options.Logs[i].Name = logName(l.URL)
+		}
+	}
+	return &Follower{
+		db:      db,
+		options: options,
+		matcher: NewMatcher(options.Domains),
+	}
+}
+
+// logName derives a name from the log URL, e.g. "ct.googleapis.com/logs/us1/argon2026h1".
+func logName(url string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+	return strings.TrimSuffix(name, "/")
+}
+
+// Run follows every log until ctx is cancelled.
+func (f *Follower) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, l := range f.options.Logs {
+		wg.Add(1)
+		go func(l Log) {
+			defer wg.Done()
+			f.follow(ctx, l)
+		}(l)
+	}
+	wg.Wait()
+}
+
+func (f *Follower) follow(ctx context.Context, l Log) {
+	for {
+		if err := f.Poll(ctx, l); err != nil && ctx.Err() == nil && f.OnError != nil {
+			f.OnError(l, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.options.PollInterval):
+		}
+	}
+}
+
+// Poll reads a log up to its current tree size.
+func (f *Follower) Poll(ctx context.Context, l Log) error {
+	client := NewClient(l.URL, 0)
+
+	sth, err := client.GetSTH(ctx)
+	if err != nil {
+		return err
+	}
+
+	state, err := f.db.GetCTLogState(l.URL)
+	if err != nil {
+		return fmt.Errorf("failed to load log position: %w", err)
+	}
+	if state == nil {
+		state = &database.CTLogState{URL: l.URL, Position: sth.TreeSize}
+		if f.options.FromBeginning {
+			state.Position = 0
+		}
+		log.Debugf("starting %s at entry %d of %d", l.Name, state.Position, sth.TreeSize)
+	}
+	state.TreeSize = sth.TreeSize
+
+	// a log that was rebuilt or swapped behind the same URL
+	if state.Position > sth.TreeSize {
+		state.Position = sth.TreeSize
+	}
+
+	if err := f.db.SetCTLogState(*state); err != nil {
+		return fmt.Errorf("failed to store log position: %w", err)
+	}
+
+	for state.Position < sth.TreeSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := state.Position + int64(f.options.BatchSize) - 1
+		if end >= sth.TreeSize {
+			end = sth.TreeSize - 1
+		}
+
+		entries, err := client.GetEntries(ctx, state.Position, end)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("get-entries returned no entries for %d-%d", state.Position, end)
+		}
+
+		if err := f.process(ctx, l, state.Position, entries); err != nil {
+			return err
+		}
+
+		state.Position += int64(len(entries))
+		if err := f.db.SetCTLogState(*state); err != nil {
+			return fmt.Errorf("failed to store log position: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (f *Follower) process(ctx context.Context, l Log, start int64, entries []RawEntry) error {
+	hosts := make(map[string]map[string]bool)
+	for i, entry := range entries {
+		names, err := Names(entry)
+		if err != nil {
+			log.Debugf("skipping entry %d of %s: %v", start+int64(i), l.Name, err)
+			continue
+		}
+
+		for _, name := range names {
+			host, apex, ok := f.matcher.Match(name)
+			if !ok {
+				continue
+			}
+			if hosts[apex] == nil {
+				hosts[apex] = make(map[string]bool)
+			}
+			hosts[apex][host] = true
+		}
+	}
+
+	domains := make([]string, 0, len(hosts))
+	for domain := range hosts {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	for _, domain := range domains {
+		d := Discovery{Log: l.Name, Domain: domain}
+		for host := range hosts[domain] {
+			d.Hosts = append(d.Hosts, host)
+		}
+		sort.Strings(d.Hosts)
+
+		if f.OnDiscovery != nil {
+			if err := f.OnDiscovery(ctx, d); err != nil {
+				return fmt.Errorf("failed to handle hosts of %s: %w", domain, err)
+			}
+		}
+	}
+
+	return nil
+}
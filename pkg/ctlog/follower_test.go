@@ -0,0 +1,222 @@
+package ctlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/database"
+)
+
+// fakeLog serves get-sth and get-entries for the entries appended to it.
+// Like real logs, it returns at most maxEntries entries per request.
+type fakeLog struct {
+	mu         sync.Mutex
+	entries    []RawEntry
+	maxEntries int
+}
+
+func (l *fakeLog) append(entries ...RawEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entries...)
+}
+
+func (l *fakeLog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/ct/v1/get-sth":
+		json.NewEncoder(w).Encode(STH{TreeSize: int64(len(l.entries))})
+
+	case "/ct/v1/get-entries":
+		start, err1 := strconv.Atoi(r.URL.Query().Get("start"))
+		end, err2 := strconv.Atoi(r.URL.Query().Get("end"))
+		if err1 != nil || err2 != nil || start < 0 || end < start || start >= len(l.entries) {
+			http.Error(w, "bad range", http.StatusBadRequest)
+			return
+		}
+		if end >= len(l.entries) {
+			end = len(l.entries) - 1
+		}
+		if l.maxEntries > 0 && end-start+1 > l.maxEntries {
+			end = start + l.maxEntries - 1
+		}
+		json.NewEncoder(w).Encode(map[string][]RawEntry{"entries": l.entries[start : end+1]})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+
+	db, err := database.New(&config.Database{
+		Enabled:     true,
+		Driver:      "sqlite",
+		Path:        filepath.Join(t.TempDir(), "track.db"),
+		AutoMigrate: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// newTestFollower returns a follower of one fake log and the discoveries it
+// reports.
+func newTestFollower(t *testing.T, db *database.DB, url string, fromBeginning bool) (*Follower, *[]Discovery) {
+	f := New(db, Options{
+		Logs:          []Log{{Name: "test", URL: url}},
+		Domains:       []string{"example.com", "dev.example.com"},
+		BatchSize:     2,
+		FromBeginning: fromBeginning,
+	})
+
+	var discoveries []Discovery
+	f.OnDiscovery = func(ctx context.Context, d Discovery) error {
+		discoveries = append(discoveries, d)
+		return nil
+	}
+	return f, &discoveries
+}
+
+func position(t *testing.T, db *database.DB, url string) int64 {
+	t.Helper()
+
+	state, err := db.GetCTLogState(url)
+	if err != nil {
+		t.Fatalf("GetCTLogState() error = %v", err)
+	}
+	if state == nil {
+		t.Fatalf("no position stored for %s", url)
+	}
+	return state.Position
+}
+
+func TestFollowerPoll(t *testing.T) {
+	ct := &fakeLog{maxEntries: 1}
+	ct.append(
+		x509Leaf(newCert(t, "www.example.com", []string{"api.example.com", "www.other.org"}, false)),
+		precertLeaf(newCert(t, "", []string{"*.dev.example.com", "a.dev.example.com"}, true)),
+		RawEntry{LeafInput: []byte{0, 0}},
+		x509Leaf(newCert(t, "", []string{"notexample.com"}, false)),
+	)
+	srv := httptest.NewServer(ct)
+	defer srv.Close()
+
+	db := newTestDB(t)
+	f, discoveries := newTestFollower(t, db, srv.URL, true)
+	if err := f.Poll(context.Background(), f.options.Logs[0]); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	want := []Discovery{
+		{Log: "test", Domain: "example.com", Hosts: []string{"api.example.com", "www.example.com"}},
+		{Log: "test", Domain: "dev.example.com", Hosts: []string{"a.dev.example.com", "dev.example.com"}},
+	}
+	if !reflect.DeepEqual(*discoveries, want) {
+		t.Errorf("discoveries = %+v, want %+v", *discoveries, want)
+	}
+	if got := position(t, db, srv.URL); got != 4 {
+		t.Errorf("position = %d, want 4", got)
+	}
+}
+
+func TestFollowerResume(t *testing.T) {
+	ct := &fakeLog{}
+	ct.append(x509Leaf(newCert(t, "", []string{"old.example.com"}, false)))
+	srv := httptest.NewServer(ct)
+	defer srv.Close()
+
+	db := newTestDB(t)
+
+	// a log that was never read starts at its current end
+	f, discoveries := newTestFollower(t, db, srv.URL, false)
+	if err := f.Poll(context.Background(), f.options.Logs[0]); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(*discoveries) != 0 {
+		t.Errorf("first poll reported %+v, want nothing", *discoveries)
+	}
+	if got := position(t, db, srv.URL); got != 1 {
+		t.Errorf("position = %d, want 1", got)
+	}
+
+	ct.append(
+		x509Leaf(newCert(t, "", []string{"new.example.com"}, false)),
+		x509Leaf(newCert(t, "", []string{"next.example.com"}, false)),
+		x509Leaf(newCert(t, "", []string{"last.example.com"}, false)),
+	)
+
+	// a restarted follower continues at the stored position, whatever
+	// FromBeginning says
+	f, discoveries = newTestFollower(t, db, srv.URL, true)
+	if err := f.Poll(context.Background(), f.options.Logs[0]); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	var hosts []string
+	for _, d := range *discoveries {
+		hosts = append(hosts, d.Hosts...)
+	}
+	if want := []string{"new.example.com", "next.example.com", "last.example.com"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %v, want %v", hosts, want)
+	}
+	if got := position(t, db, srv.URL); got != 4 {
+		t.Errorf("position = %d, want 4", got)
+	}
+}
+
+func TestFollowerRereadsFailedBatch(t *testing.T) {
+	ct := &fakeLog{}
+	ct.append(
+		x509Leaf(newCert(t, "", []string{"a.example.com"}, false)),
+		x509Leaf(newCert(t, "", []string{"b.example.com"}, false)),
+		x509Leaf(newCert(t, "", []string{"c.example.com"}, false)),
+	)
+	srv := httptest.NewServer(ct)
+	defer srv.Close()
+
+	db := newTestDB(t)
+	f, _ := newTestFollower(t, db, srv.URL, true)
+
+	var hosts []string
+	fail := true
+	f.OnDiscovery = func(ctx context.Context, d Discovery) error {
+		if d.Hosts[0] == "c.example.com" && fail {
+			fail = false
+			return errors.New("store unavailable")
+		}
+		hosts = append(hosts, d.Hosts...)
+		return nil
+	}
+
+	if err := f.Poll(context.Background(), f.options.Logs[0]); err == nil {
+		t.Fatal("Poll() succeeded although a discovery failed")
+	}
+	if got := position(t, db, srv.URL); got != 2 {
+		t.Errorf("position after the failed batch = %d, want 2", got)
+	}
+
+	if err := f.Poll(context.Background(), f.options.Logs[0]); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if want := []string{"a.example.com", "b.example.com", "c.example.com"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %v, want %v", hosts, want)
+	}
+	if got := position(t, db, srv.URL); got != 3 {
+		t.Errorf("position = %d, want 3", got)
+	}
+}
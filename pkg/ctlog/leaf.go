@@ -0,0 +1,104 @@
+package ctlog
+
+import (
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/samogod/samoscout/pkg/domainutil"
+)
+
+const (
+	x509Entry    = 0
+	precertEntry = 1
+)
+
+// Names returns the DNS names of the certificate an entry logs: the SANs and
+// the subject common name. For a precertificate the names come from the
+// pre-certificate in extra_data, since the leaf only holds its TBS part.
+func Names(entry RawEntry) ([]string, error) {
+	leaf := entry.LeafInput
+	// version, leaf type, timestamp, entry type
+	if len(leaf) < 12 {
+		return nil, fmt.Errorf("leaf too short")
+	}
+	if leaf[0] != 0 || leaf[1] != 0 {
+		return nil, fmt.Errorf("unsupported leaf version %d or type %d", leaf[0], leaf[1])
+	}
+
+	var der []byte
+	var err error
+	switch binary.BigEndian.Uint16(leaf[10:12]) {
+	case x509Entry:
+		der, _, err = readOpaque24(leaf[12:])
+	case precertEntry:
+		der, _, err = readOpaque24(entry.ExtraData)
+	default:
+		return nil, fmt.Errorf("unknown entry type %d", binary.BigEndian.Uint16(leaf[10:12]))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// precertificates carry a critical poison extension; parsing ignores it
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	names := append([]string{}, cert.DNSNames...)
+	if cn := cert.Subject.CommonName; cn != "" && strings.Contains(cn, ".") && !strings.Contains(cn, " ") {
+		names = append(names, cn)
+	}
+	return names, nil
+}
+
+// readOpaque24 reads a TLS opaque value with a 3-byte length prefix.
+func readOpaque24(data []byte) ([]byte, []byte, error) {
+	if len(data) < 3 {
+		return nil, nil, fmt.Errorf("truncated length")
+	}
+	n := int(data[0])<<16 | int(data[1])<<8 | int(data[2])
+	if len(data) < 3+n {
+		return nil, nil, fmt.Errorf("truncated certificate: want %d bytes, have %d", n, len(data)-3)
+	}
+	return data[3 : 3+n], data[3+n:], nil
+}
+
+// Matcher maps certificate names to the watched apex domains they belong to.
+type Matcher struct {
+	apexes []string
+}
+
+func NewMatcher(domains []string) *Matcher {
+	m := &Matcher{}
+	for _, d := range domains {
+		d = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			m.apexes = append(m.apexes, d)
+		}
+	}
+	return m
+}
+
+func (m *Matcher) Domains() []string {
+	return m.apexes
+}
+
+// Match returns the host a name stands for and the most specific watched apex
+// it belongs to. Wildcard names match as their base ("*.dev.example.com"
+// is dev.example.com).
+func (m *Matcher) Match(name string) (host, apex string, ok bool) {
+	host, err := domainutil.NormalizeHostname(name)
+	if err != nil {
+		return "", "", false
+	}
+
+	for _, a := range m.apexes {
+		if (host == a || strings.HasSuffix(host, "."+a)) && len(a) > len(apex) {
+			apex = a
+		}
+	}
+	return host, apex, apex != ""
+}
@@ -0,0 +1,166 @@
+package ctlog
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testKey, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+// poisonOID marks a precertificate (RFC 6962, section 3.1).
+var poisonOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 3}
+
+func newCert(t *testing.T, cn string, sans []string, precert bool) *x509.Certificate {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     sans,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if precert {
+		template.ExtraExtensions = []pkix.Extension{{Id: poisonOID, Critical: true, Value: []byte{0x05, 0x00}}}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &testKey.PublicKey, testKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return cert
+}
+
+func opaque24(data []byte) []byte {
+	return append([]byte{byte(len(data) >> 16), byte(len(data) >> 8), byte(len(data))}, data...)
+}
+
+// leafHeader is a MerkleTreeLeaf up to and including the entry type.
+func leafHeader(entryType uint16) []byte {
+	leaf := make([]byte, 12)
+	binary.BigEndian.PutUint64(leaf[2:10], uint64(time.Now().UnixMilli()))
+	binary.BigEndian.PutUint16(leaf[10:12], entryType)
+	return leaf
+}
+
+// x509Leaf logs a certificate the way a log stores it.
+func x509Leaf(cert *x509.Certificate) RawEntry {
+	leaf := append(leafHeader(x509Entry), opaque24(cert.Raw)...)
+	leaf = append(leaf, 0, 0) // no extensions
+	return RawEntry{LeafInput: leaf, ExtraData: opaque24(nil)}
+}
+
+// precertLeaf logs a precertificate: the leaf holds the issuer key hash and
+// the TBS part, extra_data the precertificate itself and its chain.
+func precertLeaf(cert *x509.Certificate) RawEntry {
+	leaf := append(leafHeader(precertEntry), make([]byte, 32)...)
+	leaf = append(leaf, opaque24(cert.RawTBSCertificate)...)
+	leaf = append(leaf, 0, 0)
+	return RawEntry{LeafInput: leaf, ExtraData: append(opaque24(cert.Raw), opaque24(nil)...)}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry RawEntry
+		want  []string
+	}{
+		{
+			name:  "x509 SANs and common name",
+			entry: x509Leaf(newCert(t, "www.example.com", []string{"api.example.com", "*.dev.example.com"}, false)),
+			want:  []string{"api.example.com", "*.dev.example.com", "www.example.com"},
+		},
+		{
+			name:  "precert",
+			entry: precertLeaf(newCert(t, "", []string{"mail.example.com"}, true)),
+			want:  []string{"mail.example.com"},
+		},
+		{
+			name:  "common name that is no hostname",
+			entry: x509Leaf(newCert(t, "Example Inc Root", []string{"a.example.com"}, false)),
+			want:  []string{"a.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Names(tt.entry)
+			if err != nil {
+				t.Fatalf("Names() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Names() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamesInvalid(t *testing.T) {
+	valid := x509Leaf(newCert(t, "", []string{"a.example.com"}, false))
+
+	tests := []struct {
+		name  string
+		entry RawEntry
+	}{
+		{"short leaf", RawEntry{LeafInput: []byte{0, 0, 1}}},
+		{"unknown version", RawEntry{LeafInput: append([]byte{1}, valid.LeafInput[1:]...)}},
+		{"unknown entry type", RawEntry{LeafInput: append(leafHeader(7), 0, 0, 0)}},
+		{"truncated certificate", RawEntry{LeafInput: valid.LeafInput[:40]}},
+		{"precert without extra data", RawEntry{LeafInput: leafHeader(precertEntry)}},
+		{"garbage certificate", RawEntry{LeafInput: append(leafHeader(x509Entry), opaque24([]byte("not a certificate"))...)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if names, err := Names(tt.entry); err == nil {
+				t.Errorf("Names() = %v, want an error", names)
+			}
+		})
+	}
+}
+
+func TestMatcherMatch(t *testing.T) {
+	m := NewMatcher([]string{"Example.com.", " dev.example.com", "example.org", ""})
+
+	tests := []struct {
+		name string
+		host string
+		apex string
+		ok   bool
+	}{
+		{"api.example.com", "api.example.com", "example.com", true},
+		{"example.com", "example.com", "example.com", true},
+		{"WWW.Example.COM.", "www.example.com", "example.com", true},
+		{"*.example.com", "example.com", "example.com", true},
+		{"a.dev.example.com", "a.dev.example.com", "dev.example.com", true},
+		{"*.dev.example.com", "dev.example.com", "dev.example.com", true},
+		{"www.example.org", "www.example.org", "example.org", true},
+		{"notexample.com", "", "", false},
+		{"example.com.evil.net", "", "", false},
+		{"bad name.example.com", "", "", false},
+	}
+
+	if got, want := m.Domains(), []string{"example.com", "dev.example.com", "example.org"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Domains() = %v, want %v", got, want)
+	}
+
+	for _, tt := range tests {
+		host, apex, ok := m.Match(tt.name)
+		if ok != tt.ok || (ok && (host != tt.host || apex != tt.apex)) {
+			t.Errorf("Match(%q) = %q, %q, %v, want %q, %q, %v", tt.name, host, apex, ok, tt.host, tt.apex, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"database/sql"
+)
+
+// statusChanges lists the NEW, revived and DEAD transitions recorded for a
+// scan, with the sources that reported each host.
+func (s *sqlStore) statusChanges(tx *sql.Tx, scanID int64) ([]HostChange, error) {
+	rows, err := tx.Query(s.dialect.rebind(`
+		SELECT s.subdomain, c.old_status, c.new_status, COALESCE(b.sources, '')
+		FROM status_changes c
+		JOIN subdomains s ON s.id = c.subdomain_id
+		LEFT JOIN track_batch b ON b.subdomain = s.subdomain
+		WHERE c.scan_id = $1
+			AND (c.new_status IN ('NEW', 'DEAD') OR c.old_status = 'DEAD')
+		ORDER BY s.subdomain
+	`), scanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []HostChange
+	for rows.Next() {
+		var c HostChange
+		var sources string
+		if err := rows.Scan(&c.Subdomain, &c.OldStatus, &c.NewStatus, &sources); err != nil {
+			return nil, err
+		}
+		c.Sources = splitSources(sources)
+
+		switch {
+		case c.NewStatus == "NEW":
+			c.Kind = ChangeNew
+		case c.NewStatus == "DEAD":
+			c.Kind = ChangeDied
+		default:
+			c.Kind = ChangeRevived
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, rows.Err()
+}
+
+// applyHTTP stores the scan's probe results and returns the hosts whose status
+// code or title changed. Hosts that never answered before (http_status 0) are
+// not reported, so the first probe of a domain does not flood notifications.
+func (s *sqlStore) applyHTTP(tx *sql.Tx, domain string, probes map[string]HTTPProbe) ([]HostChange, error) {
+	err := s.dialect.createTempTable(tx, "track_http", `(
+		subdomain TEXT PRIMARY KEY,
+		http_status INTEGER NOT NULL,
+		http_title TEXT NOT NULL
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	var batch [][]interface{}
+	for subdomain, p := range probes {
+		batch = append(batch, []interface{}{subdomain, p.Status, p.Title})
+	}
+	if err := s.dialect.copyRows(tx, "track_http", []string{"subdomain", "http_status", "http_title"}, batch); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(s.dialect.rebind(`
+		SELECT s.subdomain, s.status, s.http_status, h.http_status, s.http_title, h.http_title
+		FROM track_http h
+		JOIN subdomains s ON s.domain = $1 AND s.subdomain = h.subdomain
+		WHERE s.http_status != 0
+			AND (s.http_status != h.http_status OR s.http_title != h.http_title)
+		ORDER BY s.subdomain
+	`), domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []HostChange
+	for rows.Next() {
+		c := HostChange{Kind: ChangeHTTP}
+		if err := rows.Scan(&c.Subdomain, &c.NewStatus, &c.OldHTTPStatus, &c.NewHTTPStatus, &c.OldHTTPTitle, &c.NewHTTPTitle); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		c.OldStatus = c.NewStatus
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(s.dialect.rebind(`
+		UPDATE subdomains
+		SET http_status = (SELECT h.http_status FROM track_http h WHERE h.subdomain = subdomains.subdomain),
+			http_title = (SELECT h.http_title FROM track_http h WHERE h.subdomain = subdomains.subdomain)
+		WHERE domain = $1
+			AND subdomain IN (SELECT subdomain FROM track_http)
+	`), domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
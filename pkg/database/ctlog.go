@@ -0,0 +1,42 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CTLogState is how far the CT log follower has read a log: entries before
+// Position have been processed.
+type CTLogState struct {
+	URL       string
+	Position  int64
+	TreeSize  int64
+	UpdatedAt time.Time
+}
+
+// GetCTLogState returns nil without an error for a log that was never read.
+func (s *sqlStore) GetCTLogState(url string) (*CTLogState, error) {
+	state := &CTLogState{URL: url}
+	err := s.conn.QueryRow(s.dialect.rebind(`
+		SELECT position, tree_size, updated_at FROM ct_log_state WHERE log_url = $1
+	`), url).Scan(&state.Position, &state.TreeSize, &state.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *sqlStore) SetCTLogState(state CTLogState) error {
+	_, err := s.conn.Exec(s.dialect.rebind(`
+		INSERT INTO ct_log_state (log_url, position, tree_size, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (log_url) DO UPDATE
+		SET position = EXCLUDED.position,
+			tree_size = EXCLUDED.tree_size,
+			updated_at = EXCLUDED.updated_at
+	`), state.URL, state.Position, state.TreeSize)
+	return err
+}
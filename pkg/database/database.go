@@ -1,289 +1,329 @@
 package database
 
 import (
-	"database/sql"
 	"fmt"
 	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/logging"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
-
-	_ "github.com/lib/pq"
 )
 
-var DebugLog func(string, ...interface{})
+var log = logging.Component("database")
+
+// Store is a tracking backend. Postgres and SQLite implementations share the
+// same semantics; database.driver picks one.
+type Store interface {
+	TrackSubdomains(domain string, subdomains []string, scan *ScanInfo) (*TrackSummary, error)
+	QuerySubdomains(domain string, status string) ([]SubdomainRecord, error)
+	QueryAllSubdomains(status string) ([]SubdomainRecord, error)
+	FindSubdomains(filter SubdomainFilter) ([]SubdomainRecord, error)
+	DiffScans(domain string, from, to int64) (*ScanDiff, error)
+	AddTags(subdomain string, tags []string) (int, error)
+	RemoveTags(subdomain string, tags []string) (int, error)
+	SetTriageState(subdomain, state string) (int, error)
+	AddNote(subdomain, note string) (int, error)
+	QueryNotes(subdomain string) ([]Note, error)
+	ApplyVerification(domain string, results []VerifyResult) (*VerifySummary, error)
+	QueryScans(domain string) ([]ScanRecord, error)
+	QueryScanObservations(scanID int64) ([]ObservationRecord, error)
+	QuerySubdomainTimeline(subdomain string) ([]ObservationRecord, error)
+	QueryStatusChanges(subdomain string) ([]StatusChange, error)
+	CreateJob(domain, options string) (*Job, error)
+	UpdateJob(job *Job) error
+	GetJob(id int64) (*Job, error)
+	QueryJobs(filter JobFilter) ([]Job, error)
+	RequeueJobs() (int, error)
+	QueryMonitorRuns() ([]MonitorRun, error)
+	RecordMonitorRun(run MonitorRun) error
+	GetCTLogState(url string) (*CTLogState, error)
+	SetCTLogState(state CTLogState) error
+	Migrations() ([]Migration, error)
+	Migrate() ([]Migration, error)
+	Close() error
+}
 
 type DB struct {
-	conn    *sql.DB
+	Store
 	enabled bool
 }
 
 type SubdomainRecord struct {
-	Domain     string
-	Subdomain  string
-	Status     string
-	FirstSeen  time.Time
-	LastSeen   time.Time
+	Domain      string
+	Subdomain   string
+	Status      string
+	FirstSeen   time.Time
+	LastSeen    time.Time
+	TriageState string
+	Tags        []string
+	HTTPStatus  int
+	HTTPTitle   string
 }
 
-const DBName = "samoscout_track"
-
-func New(cfg *config.Database) (*DB, error) {
-	db := &DB{
-		enabled: cfg.Enabled,
-	}
-
-	if !cfg.Enabled {
-		fmt.Println("[INF] Database connection disabled.")
-		return db, nil
-	}
-
-	postgresConnStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=postgres sslmode=disable",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password)
-
-	postgresConn, err := sql.Open("postgres", postgresConnStr)
-	if err != nil {
-		fmt.Println("[INF] Database connection disabled.")
-		return db, fmt.Errorf("failed to connect to postgres: %w", err)
-	}
-	defer postgresConn.Close()
-
-	if err := postgresConn.Ping(); err != nil {
-		fmt.Println("[INF] Database connection disabled.")
-		return db, fmt.Errorf("failed to ping postgres: %w", err)
-	}
+// TriageStates are the values accepted by SetTriageState. Triage state is
+// analyst-owned: scans never change it.
+var TriageStates = []string{"untriaged", "in_scope", "out_of_scope", "triaged", "false_positive"}
 
-	var exists bool
-	err = postgresConn.QueryRow("SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", DBName).Scan(&exists)
-	if err != nil {
-		fmt.Println("[INF] Database connection disabled.")
-		return db, fmt.Errorf("failed to check database existence: %w", err)
-	}
-
-	if !exists {
-		_, err = postgresConn.Exec(fmt.Sprintf("CREATE DATABASE %s", DBName))
-		if err != nil {
-			fmt.Println("[INF] Database connection disabled.")
-			return db, fmt.Errorf("failed to create database: %w", err)
+func ValidTriageState(state string) bool {
+	for _, s := range TriageStates {
+		if s == state {
+			return true
 		}
-		fmt.Printf("[INF] Database '%s' created successfully.\n", DBName)
-	}
-
-	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, DBName)
-
-	conn, err := sql.Open("postgres", connStr)
-	if err != nil {
-		fmt.Println("[INF] Database connection disabled.")
-		return db, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	return false
+}
 
-	if err := conn.Ping(); err != nil {
-		conn.Close()
-		fmt.Println("[INF] Database connection disabled.")
-		return db, fmt.Errorf("failed to ping database: %w", err)
-	}
+type Note struct {
+	Domain    string
+	Subdomain string
+	Note      string
+	CreatedAt time.Time
+}
 
-	db.conn = conn
-	fmt.Println("[INF] Database connection active.")
+// SubdomainFilter narrows FindSubdomains. Zero values mean "no constraint";
+// an empty Domain matches every domain.
+type SubdomainFilter struct {
+	Domain           string
+	Status           string
+	FirstSeenSince   time.Time
+	FirstSeenUntil   time.Time
+	LastSeenSince    time.Time
+	LastSeenUntil    time.Time
+	Match            string
+	Regex            *regexp.Regexp
+	NewSinceLastScan bool
+	TriageState      string
+	Tags             []string
+}
 
-	if err := db.initSchema(); err != nil {
-		return db, fmt.Errorf("failed to initialize schema: %w", err)
-	}
+// ScanDiff lists hosts observed by the To scan but not the From scan (Added)
+// and the other way round (Removed).
+type ScanDiff struct {
+	Domain  string
+	From    int64
+	To      int64
+	Added   []string
+	Removed []string
+}
 
-	return db, nil
+// VerifyResult is live evidence for one tracked host. Only definitive answers
+// belong here: a host whose lookup timed out must be left out, not reported
+// with Resolves false.
+type VerifyResult struct {
+	Subdomain  string
+	Resolves   bool
+	Probed     bool
+	HTTPStatus int
+	HTTPTitle  string
 }
 
-func (db *DB) initSchema() error {
-	if !db.enabled || db.conn == nil {
-		return nil
-	}
+type VerifySummary struct {
+	Domain     string
+	Checked    int
+	Alive      int
+	Revived    int
+	Died       int
+	Unknown    int
+	Responding int
+}
 
-	schema := `
-	CREATE TABLE IF NOT EXISTS subdomains (
-		id SERIAL PRIMARY KEY,
-		domain VARCHAR(255) NOT NULL,
-		subdomain VARCHAR(255) NOT NULL,
-		status VARCHAR(20) NOT NULL DEFAULT 'NEW',
-		first_seen TIMESTAMP NOT NULL DEFAULT NOW(),
-		last_seen TIMESTAMP NOT NULL DEFAULT NOW(),
-		UNIQUE(domain, subdomain)
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_domain ON subdomains(domain);
-	CREATE INDEX IF NOT EXISTS idx_status ON subdomains(status);
-	CREATE INDEX IF NOT EXISTS idx_subdomain ON subdomains(subdomain);
-	`
-
-	_, err := db.conn.Exec(schema)
-	return err
+// ScanInfo describes the scan a TrackSubdomains call belongs to. Partial,
+// SourceErrors and FailedSources describe coverage and feed the DEAD marking
+// policy; Resolve,
+// when set, is used to re-check hosts before they are marked DEAD. Imported
+// results only add evidence and never mark hosts DEAD; Tool names the tool
+// that produced them.
+type ScanInfo struct {
+	StartedAt        time.Time
+	EndedAt          time.Time
+	Options          string
+	Sources          []string
+	SubdomainSources map[string][]string
+	Partial          bool
+	SourceErrors     int
+	FailedSources    []string
+	Imported         bool
+	Tool             string
+	Resolve          func(hosts []string) map[string]bool
+	HTTP             map[string]HTTPProbe
 }
 
-func (db *DB) Close() error {
-	if db.conn != nil {
-		return db.conn.Close()
-	}
-	return nil
+// HTTPProbe is the HTTP response a scan saw for one host. Hosts in
+// ScanInfo.HTTP get their stored http_status and http_title updated.
+type HTTPProbe struct {
+	Status int
+	Title  string
 }
 
-func (db *DB) IsEnabled() bool {
-	return db.enabled && db.conn != nil
+// TrackSummary is what a TrackSubdomains call changed, for the scan summary.
+// Changes lists every host behind the counters plus HTTP changes.
+type TrackSummary struct {
+	ScanID      int64        `json:"scan_id"`
+	Total       int          `json:"total"`
+	New         int          `json:"new"`
+	Reactivated int          `json:"revived"`
+	Died        int          `json:"died"`
+	Changes     []HostChange `json:"changes,omitempty"`
 }
 
-func (db *DB) TrackSubdomains(domain string, subdomains []string) error {
-	if !db.IsEnabled() {
-		return nil
-	}
+const (
+	ChangeNew     = "new"
+	ChangeRevived = "revived"
+	ChangeDied    = "died"
+	ChangeHTTP    = "http_changed"
+)
 
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+// HostChange is one host whose status or HTTP response changed in a scan.
+type HostChange struct {
+	Subdomain     string   `json:"subdomain"`
+	Kind          string   `json:"kind"`
+	OldStatus     string   `json:"old_status,omitempty"`
+	NewStatus     string   `json:"new_status,omitempty"`
+	Sources       []string `json:"sources,omitempty"`
+	OldHTTPStatus int      `json:"old_http_status,omitempty"`
+	NewHTTPStatus int      `json:"new_http_status,omitempty"`
+	OldHTTPTitle  string   `json:"old_http_title,omitempty"`
+	NewHTTPTitle  string   `json:"new_http_title,omitempty"`
+}
 
-	currentSubdomains := make(map[string]bool)
-	for _, subdomain := range subdomains {
-		currentSubdomains[subdomain] = true
-	}
+type StatusChange struct {
+	ScanID    int64
+	Subdomain string
+	OldStatus string
+	NewStatus string
+	Reason    string
+	ChangedAt time.Time
+}
 
-	for subdomain := range currentSubdomains {
-		var exists bool
-		err := tx.QueryRow(`
-			SELECT EXISTS(SELECT 1 FROM subdomains WHERE domain = $1 AND subdomain = $2)
-		`, domain, subdomain).Scan(&exists)
-		if err != nil {
-			return err
-		}
+type ScanRecord struct {
+	ID              int64
+	Domain          string
+	StartedAt       time.Time
+	EndedAt         time.Time
+	Options         string
+	Sources         []string
+	Tool            string
+	TotalSubdomains int
+	NewSubdomains   int
+	Reactivated     int
+	DeadSubdomains  int
+}
 
-		if exists {
-			if DebugLog != nil {
-				DebugLog("updating subdomain %s to ACTIVE in database", subdomain)
-			}
-			_, err = tx.Exec(`
-				UPDATE subdomains 
-				SET status = 'ACTIVE', last_seen = NOW()
-				WHERE domain = $1 AND subdomain = $2
-			`, domain, subdomain)
-		} else {
-			if DebugLog != nil {
-				DebugLog("inserting new subdomain %s with status NEW into database", subdomain)
-			}
-			_, err = tx.Exec(`
-				INSERT INTO subdomains (domain, subdomain, status, first_seen, last_seen)
-				VALUES ($1, $2, 'NEW', NOW(), NOW())
-			`, domain, subdomain)
-		}
+type ObservationRecord struct {
+	ScanID    int64
+	StartedAt time.Time
+	Domain    string
+	Subdomain string
+	Seen      bool
+	Sources   []string
+}
 
-		if err != nil {
-			return err
-		}
-	}
+const DBName = "samoscout_track"
 
-	rows, err := tx.Query(`
-		SELECT subdomain FROM subdomains 
-		WHERE domain = $1 AND status != 'DEAD'
-	`, domain)
-	if err != nil {
-		return err
+// New opens the configured backend and brings its schema up to date, applying
+// pending migrations when auto_migrate is set and refusing to track otherwise.
+func New(cfg *config.Database) (*DB, error) {
+	db, err := Open(cfg)
+	if err == nil && db.IsEnabled() {
+		err = db.checkSchema(cfg.AutoMigrate)
 	}
-	defer rows.Close()
 
-	var deadSubdomains []string
-	for rows.Next() {
-		var subdomain string
-		if err := rows.Scan(&subdomain); err != nil {
-			return err
-		}
-		if !currentSubdomains[subdomain] {
-			deadSubdomains = append(deadSubdomains, subdomain)
+	if err != nil || !db.IsEnabled() {
+		if db.Store != nil {
+			db.Store.Close()
+			db.Store = nil
 		}
+		log.Infof("Database connection disabled.")
+		return db, err
 	}
 
-	for _, subdomain := range deadSubdomains {
-		if DebugLog != nil {
-			DebugLog("marking subdomain %s as DEAD in database (not found in current scan)", subdomain)
-		}
-		_, err = tx.Exec(`
-			UPDATE subdomains 
-			SET status = 'DEAD', last_seen = NOW()
-			WHERE domain = $1 AND subdomain = $2
-		`, domain, subdomain)
-		if err != nil {
-			return err
-		}
-	}
+	log.Infof("Database connection active.")
 
-	return tx.Commit()
+	return db, nil
 }
 
-func (db *DB) QuerySubdomains(domain string, status string) ([]SubdomainRecord, error) {
-	if !db.IsEnabled() {
-		return nil, fmt.Errorf("database is not enabled")
+// Open connects to the configured backend without touching its schema.
+func Open(cfg *config.Database) (*DB, error) {
+	db := &DB{
+		enabled: cfg.Enabled,
 	}
 
-	query := `
-		SELECT domain, subdomain, status, first_seen, last_seen
-		FROM subdomains
-		WHERE domain = $1
-	`
-	args := []interface{}{domain}
-
-	if status != "" {
-		query += " AND status = $2"
-		args = append(args, status)
+	if !cfg.Enabled {
+		return db, nil
 	}
 
-	query += " ORDER BY first_seen DESC"
-
-	rows, err := db.conn.Query(query, args...)
+	var store Store
+	var err error
+	switch strings.ToLower(cfg.Driver) {
+	case "", "postgres", "postgresql":
+		store, err = openPostgres(cfg)
+	case "sqlite", "sqlite3":
+		store, err = openSQLite(cfg)
+	default:
+		err = fmt.Errorf("unknown database driver: %s", cfg.Driver)
+	}
 	if err != nil {
-		return nil, err
+		return db, err
 	}
-	defer rows.Close()
 
-	var records []SubdomainRecord
-	for rows.Next() {
-		var r SubdomainRecord
-		if err := rows.Scan(&r.Domain, &r.Subdomain, &r.Status, &r.FirstSeen, &r.LastSeen); err != nil {
-			return nil, err
-		}
-		records = append(records, r)
-	}
+	db.Store = timedStore{store}
 
-	return records, nil
+	return db, nil
 }
 
-func (db *DB) QueryAllSubdomains(status string) ([]SubdomainRecord, error) {
-	if !db.IsEnabled() {
-		return nil, fmt.Errorf("database is not enabled")
+func (db *DB) checkSchema(autoMigrate bool) error {
+	if autoMigrate {
+		applied, err := db.Migrate()
+		for _, m := range applied {
+			log.Infof("Applied database migration %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+		return nil
 	}
 
-	query := `
-		SELECT domain, subdomain, status, first_seen, last_seen
-		FROM subdomains
-	`
-	var args []interface{}
+	migrations, err := db.Migrations()
+	if err != nil {
+		return err
+	}
 
-	if status != "" {
-		query += " WHERE status = $1"
-		args = append(args, status)
+	if pending := pendingMigrations(migrations); pending > 0 {
+		return fmt.Errorf("database schema has %d pending migration(s), run 'samoscout db migrate'", pending)
 	}
 
-	query += " ORDER BY domain, first_seen DESC"
+	return nil
+}
 
-	rows, err := db.conn.Query(query, args...)
-	if err != nil {
-		return nil, err
+func (db *DB) Close() error {
+	if db.Store != nil {
+		return db.Store.Close()
 	}
-	defer rows.Close()
+	return nil
+}
+
+func (db *DB) IsEnabled() bool {
+	return db.enabled && db.Store != nil
+}
 
-	var records []SubdomainRecord
-	for rows.Next() {
-		var r SubdomainRecord
-		if err := rows.Scan(&r.Domain, &r.Subdomain, &r.Status, &r.FirstSeen, &r.LastSeen); err != nil {
-			return nil, err
+func joinSources(sources []string) string {
+	unique := make(map[string]bool)
+	var list []string
+	for _, s := range sources {
+		s = strings.TrimSpace(s)
+		if s != "" && !unique[s] {
+			unique[s] = true
+			list = append(list, s)
 		}
-		records = append(records, r)
 	}
-
-	return records, nil
+	sort.Strings(list)
+	return strings.Join(list, ",")
 }
 
+func splitSources(sources string) []string {
+	if sources == "" {
+		return nil
+	}
+	return strings.Split(sources, ",")
+}
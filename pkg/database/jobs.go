@@ -0,0 +1,164 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobFinished  = "finished"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// Job is a scan queued through the API. Options holds the scan options as
+// JSON; ScanID links a finished job to the scan it recorded.
+type Job struct {
+	ID         int64
+	Domain     string
+	Options    string
+	Status     string
+	Error      string
+	ScanID     int64
+	Subdomains int
+	CreatedAt  time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// JobFilter narrows QueryJobs; empty fields match every job.
+type JobFilter struct {
+	Domain string
+	Status string
+	Limit  int
+}
+
+const jobColumns = `id, domain, options, status, error, COALESCE(scan_id, 0), subdomains, created_at, started_at, finished_at`
+
+func (s *sqlStore) CreateJob(domain, options string) (*Job, error) {
+	job := &Job{Domain: domain, Options: options, Status: JobQueued}
+	err := s.conn.QueryRow(s.dialect.rebind(`
+		INSERT INTO jobs (domain, options, status, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING id, created_at
+	`), domain, options, JobQueued).Scan(&job.ID, &job.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// UpdateJob stores the mutable state of a job: status, error, scan, counts
+// and timestamps.
+func (s *sqlStore) UpdateJob(job *Job) error {
+	var scanID interface{}
+	if job.ScanID != 0 {
+		scanID = job.ScanID
+	}
+
+	_, err := s.conn.Exec(s.dialect.rebind(`
+		UPDATE jobs
+		SET status = $2, error = $3, scan_id = $4, subdomains = $5, started_at = $6, finished_at = $7
+		WHERE id = $1
+	`), job.ID, job.Status, job.Error, scanID, job.Subdomains,
+		s.nullTimestamp(job.StartedAt), s.nullTimestamp(job.FinishedAt))
+	return err
+}
+
+// GetJob returns nil without an error when no job has the given id.
+func (s *sqlStore) GetJob(id int64) (*Job, error) {
+	row := s.conn.QueryRow(s.dialect.rebind("SELECT "+jobColumns+" FROM jobs WHERE id = $1"), id)
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+func (s *sqlStore) QueryJobs(filter JobFilter) ([]Job, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Domain != "" {
+		conditions = append(conditions, "domain = "+arg(filter.Domain))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+arg(filter.Status))
+	}
+
+	query := "SELECT " + jobColumns + " FROM jobs"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// queued jobs are listed in the order they will run
+	if filter.Status == JobQueued {
+		query += " ORDER BY id ASC"
+	} else {
+		query += " ORDER BY id DESC"
+	}
+	if filter.Limit > 0 {
+		query += " LIMIT " + arg(filter.Limit)
+	}
+
+	rows, err := s.conn.Query(s.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// RequeueJobs puts jobs left running by a previous process back in the queue.
+func (s *sqlStore) RequeueJobs() (int, error) {
+	res, err := s.conn.Exec(s.dialect.rebind(`
+		UPDATE jobs SET status = $1, started_at = NULL WHERE status = $2
+	`), JobQueued, JobRunning)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (s *sqlStore) nullTimestamp(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return s.dialect.timestamp(t)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row rowScanner) (*Job, error) {
+	var job Job
+	var startedAt, finishedAt sql.NullTime
+	err := row.Scan(&job.ID, &job.Domain, &job.Options, &job.Status, &job.Error, &job.ScanID,
+		&job.Subdomains, &job.CreatedAt, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	job.StartedAt = startedAt.Time
+	job.FinishedAt = finishedAt.Time
+	return &job, nil
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"time"
+
+	"github.com/samogod/samoscout/pkg/metrics"
+)
+
+// timedStore records the latency of the writes a backend makes; reads go
+// straight to the wrapped store.
+type timedStore struct {
+	Store
+}
+
+func (s timedStore) TrackSubdomains(domain string, subdomains []string, scan *ScanInfo) (summary *TrackSummary, err error) {
+	defer func(start time.Time) { metrics.DBWrite("track_subdomains", start, err) }(time.Now())
+	return s.Store.TrackSubdomains(domain, subdomains, scan)
+}
+
+func (s timedStore) ApplyVerification(domain string, results []VerifyResult) (summary *VerifySummary, err error) {
+	defer func(start time.Time) { metrics.DBWrite("apply_verification", start, err) }(time.Now())
+	return s.Store.ApplyVerification(domain, results)
+}
+
+func (s timedStore) CreateJob(domain, options string) (job *Job, err error) {
+	defer func(start time.Time) { metrics.DBWrite("create_job", start, err) }(time.Now())
+	return s.Store.CreateJob(domain, options)
+}
+
+func (s timedStore) UpdateJob(job *Job) (err error) {
+	defer func(start time.Time) { metrics.DBWrite("update_job", start, err) }(time.Now())
+	return s.Store.UpdateJob(job)
+}
+
+func (s timedStore) RecordMonitorRun(run MonitorRun) (err error) {
+	defer func(start time.Time) { metrics.DBWrite("record_monitor_run", start, err) }(time.Now())
+	return s.Store.RecordMonitorRun(run)
+}
+
+func (s timedStore) SetCTLogState(state CTLogState) (err error) {
+	defer func(start time.Time) { metrics.DBWrite("set_ctlog_state", start, err) }(time.Now())
+	return s.Store.SetCTLogState(state)
+}
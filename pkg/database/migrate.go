@@ -0,0 +1,173 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migrations live in migrations/<dialect>/NNNN_name.sql and are applied in
+// version order, each in its own transaction. Released files must never be
+// edited; schema changes always go into a new file.
+//
+//go:embed migrations
+var migrationFS embed.FS
+
+type Migration struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	sql       string
+}
+
+func loadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := migrationFS.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		parts := strings.SplitN(base, "_", 2)
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) != 2 {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		data, err := migrationFS.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    parts[1],
+			sql:     string(data),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (s *sqlStore) ensureMigrationsTable() error {
+	_, err := s.conn.Exec(s.dialect.rebind(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT (NOW())
+		)
+	`))
+	return err
+}
+
+// Migrations returns every known migration with its applied state.
+func (s *sqlStore) Migrations() ([]Migration, error) {
+	if err := s.ensureMigrationsTable(); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	migrations, err := loadMigrations(s.dialect.name())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range migrations {
+		if appliedAt, ok := applied[migrations[i].Version]; ok {
+			migrations[i].Applied = true
+			migrations[i].AppliedAt = appliedAt
+		}
+	}
+
+	return migrations, nil
+}
+
+// Migrate applies every pending migration and returns the ones it applied.
+func (s *sqlStore) Migrate() ([]Migration, error) {
+	migrations, err := s.Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		if m.Applied {
+			continue
+		}
+
+		log.Debugf("applying migration %04d_%s", m.Version, m.Name)
+
+		tx, err := s.conn.Begin()
+		if err != nil {
+			return applied, err
+		}
+
+		if _, err := tx.Exec(m.sql); err != nil {
+			tx.Rollback()
+			return applied, fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+
+		_, err = tx.Exec(s.dialect.rebind("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)"), m.Version, m.Name)
+		if err != nil {
+			tx.Rollback()
+			return applied, fmt.Errorf("failed to record migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return applied, err
+		}
+
+		m.Applied = true
+		applied = append(applied, m)
+	}
+
+	return applied, nil
+}
+
+func pendingMigrations(migrations []Migration) int {
+	pending := 0
+	for _, m := range migrations {
+		if !m.Applied {
+			pending++
+		}
+	}
+	return pending
+}
@@ -0,0 +1,21 @@
+ALTER TABLE subdomains ADD COLUMN IF NOT EXISTS triage_state VARCHAR(32) NOT NULL DEFAULT 'untriaged';
+
+CREATE INDEX IF NOT EXISTS idx_triage_state ON subdomains(triage_state);
+
+CREATE TABLE IF NOT EXISTS subdomain_tags (
+	subdomain_id INTEGER NOT NULL REFERENCES subdomains(id) ON DELETE CASCADE,
+	tag VARCHAR(64) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (subdomain_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subdomain_tags_tag ON subdomain_tags(tag);
+
+CREATE TABLE IF NOT EXISTS subdomain_notes (
+	id SERIAL PRIMARY KEY,
+	subdomain_id INTEGER NOT NULL REFERENCES subdomains(id) ON DELETE CASCADE,
+	note TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subdomain_notes_subdomain ON subdomain_notes(subdomain_id);
@@ -0,0 +1,3 @@
+ALTER TABLE subdomains ADD COLUMN IF NOT EXISTS http_status INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE subdomains ADD COLUMN IF NOT EXISTS http_title TEXT NOT NULL DEFAULT '';
+ALTER TABLE subdomains ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP;
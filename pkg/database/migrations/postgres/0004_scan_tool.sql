@@ -0,0 +1 @@
+ALTER TABLE scans ADD COLUMN IF NOT EXISTS tool TEXT NOT NULL DEFAULT 'samoscout';
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS jobs (
+	id SERIAL PRIMARY KEY,
+	domain VARCHAR(255) NOT NULL,
+	options TEXT NOT NULL DEFAULT '{}',
+	status VARCHAR(20) NOT NULL DEFAULT 'queued',
+	error TEXT NOT NULL DEFAULT '',
+	scan_id INTEGER REFERENCES scans(id) ON DELETE SET NULL,
+	subdomains INTEGER NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	started_at TIMESTAMP,
+	finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status, id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS monitor_runs (
+	domain VARCHAR(255) NOT NULL,
+	schedule VARCHAR(255) NOT NULL,
+	last_run_at TIMESTAMP NOT NULL,
+	last_scan_id INTEGER REFERENCES scans(id) ON DELETE SET NULL,
+	last_error TEXT NOT NULL DEFAULT '',
+	runs INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (domain, schedule)
+);
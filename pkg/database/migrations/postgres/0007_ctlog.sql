@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS ct_log_state (
+	log_url TEXT PRIMARY KEY,
+	position BIGINT NOT NULL DEFAULT 0,
+	tree_size BIGINT NOT NULL DEFAULT 0,
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+ALTER TABLE scans ADD COLUMN IF NOT EXISTS imported BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- ctlog batches and imports of other tools recorded before the flag existed
+UPDATE scans SET imported = TRUE WHERE tool <> 'samoscout';
@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS subdomains (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	domain TEXT NOT NULL,
+	subdomain TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'NEW',
+	first_seen TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+	last_seen TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+	miss_count INTEGER NOT NULL DEFAULT 0,
+	status_reason TEXT NOT NULL DEFAULT '',
+	UNIQUE(domain, subdomain)
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain ON subdomains(domain);
+CREATE INDEX IF NOT EXISTS idx_status ON subdomains(status);
+CREATE INDEX IF NOT EXISTS idx_subdomain ON subdomains(subdomain);
+
+CREATE TABLE IF NOT EXISTS scans (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	domain TEXT NOT NULL,
+	started_at TIMESTAMP NOT NULL,
+	ended_at TIMESTAMP NOT NULL,
+	options TEXT NOT NULL DEFAULT '{}',
+	sources TEXT NOT NULL DEFAULT '',
+	total_subdomains INTEGER NOT NULL DEFAULT 0,
+	new_subdomains INTEGER NOT NULL DEFAULT 0,
+	reactivated_subdomains INTEGER NOT NULL DEFAULT 0,
+	dead_subdomains INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_scans_domain ON scans(domain, started_at);
+
+CREATE TABLE IF NOT EXISTS scan_observations (
+	scan_id INTEGER NOT NULL REFERENCES scans(id) ON DELETE CASCADE,
+	subdomain_id INTEGER NOT NULL REFERENCES subdomains(id) ON DELETE CASCADE,
+	sources TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (scan_id, subdomain_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_observations_subdomain ON scan_observations(subdomain_id);
+
+CREATE TABLE IF NOT EXISTS status_changes (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	subdomain_id INTEGER NOT NULL REFERENCES subdomains(id) ON DELETE CASCADE,
+	scan_id INTEGER REFERENCES scans(id) ON DELETE SET NULL,
+	old_status TEXT NOT NULL DEFAULT '',
+	new_status TEXT NOT NULL,
+	reason TEXT NOT NULL DEFAULT '',
+	changed_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_changes_subdomain ON status_changes(subdomain_id);
@@ -0,0 +1,21 @@
+ALTER TABLE subdomains ADD COLUMN triage_state TEXT NOT NULL DEFAULT 'untriaged';
+
+CREATE INDEX IF NOT EXISTS idx_triage_state ON subdomains(triage_state);
+
+CREATE TABLE IF NOT EXISTS subdomain_tags (
+	subdomain_id INTEGER NOT NULL REFERENCES subdomains(id) ON DELETE CASCADE,
+	tag TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+	PRIMARY KEY (subdomain_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subdomain_tags_tag ON subdomain_tags(tag);
+
+CREATE TABLE IF NOT EXISTS subdomain_notes (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	subdomain_id INTEGER NOT NULL REFERENCES subdomains(id) ON DELETE CASCADE,
+	note TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_subdomain_notes_subdomain ON subdomain_notes(subdomain_id);
@@ -0,0 +1,3 @@
+ALTER TABLE subdomains ADD COLUMN http_status INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE subdomains ADD COLUMN http_title TEXT NOT NULL DEFAULT '';
+ALTER TABLE subdomains ADD COLUMN verified_at TIMESTAMP;
@@ -0,0 +1 @@
+ALTER TABLE scans ADD COLUMN tool TEXT NOT NULL DEFAULT 'samoscout';
@@ -0,0 +1,4 @@
+ALTER TABLE scans ADD COLUMN imported BOOLEAN NOT NULL DEFAULT 0;
+
+-- ctlog batches and imports of other tools recorded before the flag existed
+UPDATE scans SET imported = 1 WHERE tool <> 'samoscout';
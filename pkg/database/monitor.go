@@ -0,0 +1,58 @@
+package database
+
+import (
+	"time"
+)
+
+// MonitorRun is the last run of one monitor schedule, used to work out when
+// the schedule is due again.
+type MonitorRun struct {
+	Domain    string
+	Schedule  string
+	LastRunAt time.Time
+	ScanID    int64
+	Error     string
+	Runs      int
+}
+
+func (s *sqlStore) QueryMonitorRuns() ([]MonitorRun, error) {
+	rows, err := s.conn.Query(`
+		SELECT domain, schedule, last_run_at, COALESCE(last_scan_id, 0), last_error, runs
+		FROM monitor_runs
+		ORDER BY domain, schedule
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []MonitorRun
+	for rows.Next() {
+		var r MonitorRun
+		if err := rows.Scan(&r.Domain, &r.Schedule, &r.LastRunAt, &r.ScanID, &r.Error, &r.Runs); err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+
+	return runs, rows.Err()
+}
+
+// RecordMonitorRun stores a finished run and counts it.
+func (s *sqlStore) RecordMonitorRun(run MonitorRun) error {
+	var scanID interface{}
+	if run.ScanID != 0 {
+		scanID = run.ScanID
+	}
+
+	_, err := s.conn.Exec(s.dialect.rebind(`
+		INSERT INTO monitor_runs (domain, schedule, last_run_at, last_scan_id, last_error, runs)
+		VALUES ($1, $2, $3, $4, $5, 1)
+		ON CONFLICT (domain, schedule) DO UPDATE
+		SET last_run_at = EXCLUDED.last_run_at,
+			last_scan_id = EXCLUDED.last_scan_id,
+			last_error = EXCLUDED.last_error,
+			runs = monitor_runs.runs + 1
+	`), run.Domain, run.Schedule, s.dialect.timestamp(run.LastRunAt), scanID, run.Error)
+	return err
+}
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/samogod/samoscout/pkg/config"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type postgresDialect struct{}
+
+func openPostgres(cfg *config.Database) (Store, error) {
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	postgresConnStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=postgres sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, sslMode)
+
+	postgresConn, err := sql.Open("postgres", postgresConnStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	defer postgresConn.Close()
+
+	if err := postgresConn.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	var exists bool
+	err = postgresConn.QueryRow("SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", DBName).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check database existence: %w", err)
+	}
+
+	if !exists {
+		_, err = postgresConn.Exec(fmt.Sprintf("CREATE DATABASE %s", DBName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create database: %w", err)
+		}
+		log.Infof("Database '%s' created successfully.", DBName)
+	}
+
+	// the timestamp columns have no time zone: NOW() and the values written
+	// by the store must both be UTC, which is also how lib/pq reads them back
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s timezone=UTC",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, DBName, sslMode)
+
+	conn, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &sqlStore{
+		conn:       conn,
+		dialect:    postgresDialect{},
+		deadPolicy: cfg.DeadPolicy,
+	}, nil
+}
+
+func (postgresDialect) name() string {
+	return "postgres"
+}
+
+func (postgresDialect) rebind(query string) string {
+	return query
+}
+
+// timestamp passes times as UTC: Postgres drops the offset of a time written
+// into a TIMESTAMP column and keeps its wall clock.
+func (postgresDialect) timestamp(t time.Time) interface{} {
+	return t.UTC()
+}
+
+func (postgresDialect) createTempTable(tx *sql.Tx, name, definition string, args ...interface{}) error {
+	var query string
+	if strings.HasPrefix(strings.TrimSpace(definition), "AS ") {
+		query = fmt.Sprintf("CREATE TEMP TABLE %s ON COMMIT DROP %s", name, definition)
+	} else {
+		query = fmt.Sprintf("CREATE TEMP TABLE %s %s ON COMMIT DROP", name, definition)
+	}
+	_, err := tx.Exec(query, args...)
+	return err
+}
+
+func (postgresDialect) copyRows(tx *sql.Tx, table string, columns []string, rows [][]interface{}) error {
+	stmt, err := tx.Prepare(pq.CopyIn(table, columns...))
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if _, err := stmt.Exec(row...); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return err
+	}
+
+	return stmt.Close()
+}
+
+func (postgresDialect) stringAgg(column string) string {
+	return "string_agg(" + column + ", ',')"
+}
+
+func (postgresDialect) markDead(tx *sql.Tx, domain string, minMisses int, alive []string, suffix string, scanID int64) (int, error) {
+	res, err := tx.Exec(`
+		WITH candidates AS (
+			SELECT s.id, s.status AS old_status
+			FROM subdomains s
+			WHERE s.domain = $1 AND s.status != 'DEAD' AND s.miss_count >= $2
+				AND NOT (s.subdomain = ANY($3))
+				AND NOT EXISTS (SELECT 1 FROM track_batch b WHERE b.subdomain = s.subdomain)
+			FOR UPDATE
+		), died AS (
+			UPDATE subdomains s
+			SET status = 'DEAD',
+				last_seen = NOW(),
+				status_reason = 'missed ' || s.miss_count || ' consecutive scan(s)' || $4
+			FROM candidates c
+			WHERE s.id = c.id
+			RETURNING s.id, c.old_status, s.status_reason
+		)
+		INSERT INTO status_changes (subdomain_id, scan_id, old_status, new_status, reason, changed_at)
+		SELECT id, $5, old_status, 'DEAD', status_reason, NOW() FROM died
+	`, domain, minMisses, pq.Array(alive), suffix, scanID)
+	if err != nil {
+		return 0, err
+	}
+
+	died, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(died), nil
+}
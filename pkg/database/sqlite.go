@@ -0,0 +1,149 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/samogod/samoscout/pkg/config"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// timestamps are stored as UTC text in this layout so they compare correctly
+// as strings and match what sqliteNow produces.
+const sqliteTimeFormat = "2006-01-02 15:04:05.000"
+
+const sqliteNow = "strftime('%Y-%m-%d %H:%M:%f', 'now')"
+
+var placeholderRe = regexp.MustCompile(`\$(\d+)`)
+
+type sqliteDialect struct{}
+
+func openSQLite(cfg *config.Database) (Store, error) {
+	path := cfg.Path
+	if path == "" {
+		path = config.GetDefaultTrackDBPath()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	conn, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL&_loc=auto")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// temp tables are per connection and SQLite serializes writers anyway
+	conn.SetMaxOpenConns(1)
+
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open sqlite database %s: %w", path, err)
+	}
+
+	log.Debugf("using sqlite tracking database at %s", path)
+
+	return &sqlStore{
+		conn:       conn,
+		dialect:    sqliteDialect{},
+		deadPolicy: cfg.DeadPolicy,
+	}, nil
+}
+
+func (sqliteDialect) name() string {
+	return "sqlite"
+}
+
+func (sqliteDialect) rebind(query string) string {
+	query = strings.ReplaceAll(query, "NOW()", sqliteNow)
+	return placeholderRe.ReplaceAllString(query, "?$1")
+}
+
+func (sqliteDialect) timestamp(t time.Time) interface{} {
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+// SQLite has no ON COMMIT DROP, so a table left over from an earlier scan on
+// the same connection is dropped first.
+func (sqliteDialect) createTempTable(tx *sql.Tx, name, definition string, args ...interface{}) error {
+	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS temp.%s", name)); err != nil {
+		return err
+	}
+	_, err := tx.Exec(fmt.Sprintf("CREATE TEMP TABLE %s %s", name, definition), args...)
+	return err
+}
+
+func (sqliteDialect) copyRows(tx *sql.Tx, table string, columns []string, rows [][]interface{}) error {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",")
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), placeholders))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.Exec(row...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sqliteDialect) stringAgg(column string) string {
+	return "group_concat(" + column + ", ',')"
+}
+
+// markDead records the status changes before flipping the rows, since SQLite
+// has no data-modifying CTEs; both statements select the same candidates.
+func (d sqliteDialect) markDead(tx *sql.Tx, domain string, minMisses int, alive []string, suffix string, scanID int64) (int, error) {
+	err := d.createTempTable(tx, "track_alive", "(subdomain TEXT PRIMARY KEY)")
+	if err != nil {
+		return 0, err
+	}
+
+	var rows [][]interface{}
+	for _, host := range alive {
+		rows = append(rows, []interface{}{host})
+	}
+	if err := d.copyRows(tx, "track_alive", []string{"subdomain"}, rows); err != nil {
+		return 0, err
+	}
+
+	candidates := `
+		domain = $1 AND status != 'DEAD' AND miss_count >= $2
+			AND subdomain NOT IN (SELECT subdomain FROM track_alive)
+			AND NOT EXISTS (SELECT 1 FROM track_batch b WHERE b.subdomain = subdomains.subdomain)
+	`
+
+	_, err = tx.Exec(d.rebind(`
+		INSERT INTO status_changes (subdomain_id, scan_id, old_status, new_status, reason, changed_at)
+		SELECT id, $4, status, 'DEAD', 'missed ' || miss_count || ' consecutive scan(s)' || $3, NOW()
+		FROM subdomains
+		WHERE `+candidates), domain, minMisses, suffix, scanID)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.Exec(d.rebind(`
+		UPDATE subdomains
+		SET status = 'DEAD',
+			last_seen = NOW(),
+			status_reason = 'missed ' || miss_count || ' consecutive scan(s)' || $3
+		WHERE `+candidates), domain, minMisses, suffix)
+	if err != nil {
+		return 0, err
+	}
+
+	died, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(died), nil
+}
@@ -0,0 +1,880 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/samogod/samoscout/pkg/config"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dialect holds the parts of the tracking SQL that differ between backends.
+// Queries are written with Postgres $N placeholders and NOW(); rebind turns
+// them into the backend's syntax.
+type dialect interface {
+	name() string
+	rebind(query string) string
+	timestamp(t time.Time) interface{}
+	createTempTable(tx *sql.Tx, name, definition string, args ...interface{}) error
+	copyRows(tx *sql.Tx, table string, columns []string, rows [][]interface{}) error
+	stringAgg(column string) string
+	markDead(tx *sql.Tx, domain string, minMisses int, alive []string, suffix string, scanID int64) (int, error)
+}
+
+type sqlStore struct {
+	conn       *sql.DB
+	dialect    dialect
+	deadPolicy config.DeadPolicy
+}
+
+func (s *sqlStore) Close() error {
+	return s.conn.Close()
+}
+
+// TrackSubdomains bulk-loads the scan's subdomains into a temporary table and
+// applies every status transition with set-based statements, so the number of
+// round trips does not grow with the number of hosts.
+func (s *sqlStore) TrackSubdomains(domain string, subdomains []string, scan *ScanInfo) (*TrackSummary, error) {
+	summary := &TrackSummary{}
+
+	if scan == nil {
+		now := time.Now()
+		scan = &ScanInfo{StartedAt: now, EndedAt: now}
+	}
+
+	tool := scan.Tool
+	if tool == "" {
+		tool = "samoscout"
+	}
+
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(s.dialect.rebind(`
+		INSERT INTO scans (domain, started_at, ended_at, options, sources, tool, imported)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`), domain, s.dialect.timestamp(scan.StartedAt), s.dialect.timestamp(scan.EndedAt),
+		scan.Options, joinSources(scan.Sources), tool, scan.Imported).Scan(&summary.ScanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record scan: %w", err)
+	}
+
+	if err := s.loadTrackBatch(tx, subdomains, scan.SubdomainSources); err != nil {
+		return nil, fmt.Errorf("failed to load scan batch: %w", err)
+	}
+
+	// snapshot the previous status of every observed host before upserting
+	err = s.dialect.createTempTable(tx, "track_result", s.dialect.rebind(`
+		AS SELECT b.subdomain, b.sources, s.status AS old_status,
+			CASE
+				WHEN s.status IS NULL THEN 'first seen by ' || b.sources
+				WHEN s.status = 'DEAD' THEN 'revived: seen again by ' || b.sources
+				WHEN s.status = 'NEW' THEN 'seen again by ' || b.sources
+				ELSE ''
+			END AS reason
+		FROM track_batch b
+		LEFT JOIN subdomains s ON s.domain = $1 AND s.subdomain = b.subdomain
+	`), domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage scan batch: %w", err)
+	}
+
+	err = tx.QueryRow(`
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE old_status IS NULL),
+			COUNT(*) FILTER (WHERE old_status = 'DEAD')
+		FROM track_result
+	`).Scan(&summary.Total, &summary.New, &summary.Reactivated)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("tracking %d subdomains for %s (%d new, %d reactivated)", summary.Total, domain, summary.New, summary.Reactivated)
+
+	// WHERE true keeps SQLite from parsing ON CONFLICT as a join constraint
+	_, err = tx.Exec(s.dialect.rebind(`
+		INSERT INTO subdomains (domain, subdomain, status, first_seen, last_seen, miss_count, status_reason)
+		SELECT $1, subdomain, 'NEW', NOW(), NOW(), 0, reason
+		FROM track_result
+		WHERE true
+		ON CONFLICT (domain, subdomain) DO UPDATE
+		SET status = 'ACTIVE',
+			last_seen = NOW(),
+			miss_count = 0,
+			status_reason = CASE
+				WHEN subdomains.status = 'ACTIVE' THEN subdomains.status_reason
+				ELSE EXCLUDED.status_reason
+			END
+	`), domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert subdomains: %w", err)
+	}
+
+	_, err = tx.Exec(s.dialect.rebind(`
+		INSERT INTO status_changes (subdomain_id, scan_id, old_status, new_status, reason, changed_at)
+		SELECT s.id, $2, COALESCE(r.old_status, ''),
+			CASE WHEN r.old_status IS NULL THEN 'NEW' ELSE 'ACTIVE' END,
+			r.reason, NOW()
+		FROM track_result r
+		JOIN subdomains s ON s.domain = $1 AND s.subdomain = r.subdomain
+		WHERE r.old_status IS DISTINCT FROM 'ACTIVE'
+	`), domain, summary.ScanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record status changes: %w", err)
+	}
+
+	_, err = tx.Exec(s.dialect.rebind(`
+		INSERT INTO scan_observations (scan_id, subdomain_id, sources)
+		SELECT $2, s.id, b.sources
+		FROM track_batch b
+		JOIN subdomains s ON s.domain = $1 AND s.subdomain = b.subdomain
+	`), domain, summary.ScanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record observations: %w", err)
+	}
+
+	summary.Died, err = s.markMissing(tx, domain, summary.ScanID, scan)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Changes, err = s.statusChanges(tx, summary.ScanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect changes: %w", err)
+	}
+
+	if len(scan.HTTP) > 0 {
+		httpChanges, err := s.applyHTTP(tx, domain, scan.HTTP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store probe results: %w", err)
+		}
+		summary.Changes = append(summary.Changes, httpChanges...)
+	}
+
+	_, err = tx.Exec(s.dialect.rebind(`
+		UPDATE scans
+		SET total_subdomains = $2, new_subdomains = $3, reactivated_subdomains = $4, dead_subdomains = $5
+		WHERE id = $1
+	`), summary.ScanID, summary.Total, summary.New, summary.Reactivated, summary.Died)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func (s *sqlStore) loadTrackBatch(tx *sql.Tx, subdomains []string, sources map[string][]string) error {
+	err := s.dialect.createTempTable(tx, "track_batch", `(
+		subdomain TEXT PRIMARY KEY,
+		sources TEXT NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	var rows [][]interface{}
+	for _, subdomain := range subdomains {
+		if seen[subdomain] {
+			continue
+		}
+		seen[subdomain] = true
+		rows = append(rows, []interface{}{subdomain, joinSources(sources[subdomain])})
+	}
+
+	return s.dialect.copyRows(tx, "track_batch", []string{"subdomain", "sources"}, rows)
+}
+
+// deadMarkingSkipReason reports why this scan may not mark hosts DEAD at all.
+func (s *sqlStore) deadMarkingSkipReason(scan *ScanInfo) string {
+	if scan.Imported {
+		return "imported results"
+	}
+	if scan.Partial && s.deadPolicy.SkipPartialScans {
+		return "scan used a subset of sources"
+	}
+	if scan.SourceErrors > 0 && s.deadPolicy.SkipOnSourceErrors {
+		return fmt.Sprintf("%d source error(s) during scan", scan.SourceErrors)
+	}
+	return ""
+}
+
+// coveredByFailedSources returns a condition on the subdomains table that
+// holds for hosts one of sources has reported in an earlier scan, and its
+// arguments, numbered from first. It is empty without failed sources.
+func coveredByFailedSources(sources []string, first int) (string, []interface{}) {
+	if len(sources) == 0 {
+		return "", nil
+	}
+
+	var matches []string
+	var args []interface{}
+	for i, source := range sources {
+		matches = append(matches, fmt.Sprintf("',' || o.sources || ',' LIKE $%d", first+i))
+		args = append(args, "%,"+source+",%")
+	}
+	return `EXISTS (SELECT 1 FROM scan_observations o
+		WHERE o.subdomain_id = subdomains.id AND (` + strings.Join(matches, " OR ") + `))`, args
+}
+
+// markMissing applies the dead policy to every non-DEAD subdomain missing from
+// track_batch and returns how many were marked DEAD.
+func (s *sqlStore) markMissing(tx *sql.Tx, domain string, scanID int64, scan *ScanInfo) (int, error) {
+	if skip := s.deadMarkingSkipReason(scan); skip != "" {
+		log.Debugf("skipping DEAD marking for %s: %s", domain, skip)
+		return 0, nil
+	}
+
+	// a host a failed source reported before may only be missing because of
+	// that failure; it is neither counted as missed nor marked DEAD
+	covered, coveredArgs := coveredByFailedSources(scan.FailedSources, 2)
+	shielded := []string{}
+	if covered != "" {
+		rows, err := tx.Query(s.dialect.rebind(`
+			SELECT subdomain FROM subdomains
+			WHERE domain = $1 AND status != 'DEAD'
+				AND NOT EXISTS (SELECT 1 FROM track_batch b WHERE b.subdomain = subdomains.subdomain)
+				AND `+covered), append([]interface{}{domain}, coveredArgs...)...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to check failed sources: %w", err)
+		}
+		for rows.Next() {
+			var subdomain string
+			if err := rows.Scan(&subdomain); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			shielded = append(shielded, subdomain)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		if len(shielded) > 0 {
+			log.Debugf("not counting %d missing host(s) of %s reported before by failed sources %s",
+				len(shielded), domain, strings.Join(scan.FailedSources, ", "))
+		}
+	}
+
+	query := `
+		UPDATE subdomains
+		SET miss_count = miss_count + 1
+		WHERE domain = $1 AND status != 'DEAD'
+			AND NOT EXISTS (SELECT 1 FROM track_batch b WHERE b.subdomain = subdomains.subdomain)`
+	if covered != "" {
+		query += " AND NOT " + covered
+	}
+	_, err := tx.Exec(s.dialect.rebind(query), append([]interface{}{domain}, coveredArgs...)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count misses: %w", err)
+	}
+
+	minMisses := s.deadPolicy.MinMisses
+	if minMisses < 1 {
+		minMisses = 1
+	}
+
+	checkDNS := s.deadPolicy.DNSCheck && scan.Resolve != nil
+	alive := shielded
+	if checkDNS {
+		rows, err := tx.Query(s.dialect.rebind(`
+			SELECT s.subdomain FROM subdomains s
+			WHERE s.domain = $1 AND s.status != 'DEAD' AND s.miss_count >= $2
+				AND NOT EXISTS (SELECT 1 FROM track_batch b WHERE b.subdomain = s.subdomain)
+		`), domain, minMisses)
+		if err != nil {
+			return 0, err
+		}
+
+		var candidates []string
+		for rows.Next() {
+			var subdomain string
+			if err := rows.Scan(&subdomain); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			candidates = append(candidates, subdomain)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+
+		if len(candidates) > 0 {
+			for host := range scan.Resolve(candidates) {
+				alive = append(alive, host)
+			}
+			log.Debugf("dns re-check: %d/%d DEAD candidates still resolve", len(alive), len(candidates))
+		}
+	}
+
+	suffix := ""
+	if checkDNS {
+		suffix = " and did not resolve"
+	}
+
+	died, err := s.dialect.markDead(tx, domain, minMisses, alive, suffix, scanID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark dead subdomains: %w", err)
+	}
+
+	return died, nil
+}
+
+func (s *sqlStore) QuerySubdomains(domain string, status string) ([]SubdomainRecord, error) {
+	return s.FindSubdomains(SubdomainFilter{Domain: domain, Status: status})
+}
+
+func (s *sqlStore) QueryAllSubdomains(status string) ([]SubdomainRecord, error) {
+	return s.FindSubdomains(SubdomainFilter{Status: status})
+}
+
+func (s *sqlStore) FindSubdomains(filter SubdomainFilter) ([]SubdomainRecord, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Domain != "" {
+		conditions = append(conditions, "s.domain = "+arg(filter.Domain))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "s.status = "+arg(filter.Status))
+	}
+	if !filter.FirstSeenSince.IsZero() {
+		conditions = append(conditions, "s.first_seen >= "+arg(s.dialect.timestamp(filter.FirstSeenSince)))
+	}
+	if !filter.FirstSeenUntil.IsZero() {
+		conditions = append(conditions, "s.first_seen <= "+arg(s.dialect.timestamp(filter.FirstSeenUntil)))
+	}
+	if !filter.LastSeenSince.IsZero() {
+		conditions = append(conditions, "s.last_seen >= "+arg(s.dialect.timestamp(filter.LastSeenSince)))
+	}
+	if !filter.LastSeenUntil.IsZero() {
+		conditions = append(conditions, "s.last_seen <= "+arg(s.dialect.timestamp(filter.LastSeenUntil)))
+	}
+	if filter.Match != "" {
+		conditions = append(conditions, "s.subdomain LIKE "+arg("%"+escapeLike(filter.Match)+"%")+` ESCAPE '\'`)
+	}
+	if filter.TriageState != "" {
+		conditions = append(conditions, "s.triage_state = "+arg(filter.TriageState))
+	}
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM subdomain_tags t WHERE t.subdomain_id = s.id AND t.tag = "+arg(tag)+")")
+	}
+	if filter.NewSinceLastScan {
+		// NEW transitions recorded by the most recent scan of each domain;
+		// imports and CT log batches are not scans of the domain
+		conditions = append(conditions, `EXISTS (
+			SELECT 1 FROM status_changes c
+			WHERE c.subdomain_id = s.id AND c.new_status = 'NEW'
+				AND c.scan_id = (SELECT MAX(sc.id) FROM scans sc WHERE sc.domain = s.domain AND NOT sc.imported)
+		)`)
+	}
+
+	query := `
+		SELECT s.domain, s.subdomain, s.status, s.first_seen, s.last_seen, s.triage_state,
+			s.http_status, s.http_title,
+			COALESCE((SELECT ` + s.dialect.stringAgg("t.tag") + ` FROM subdomain_tags t WHERE t.subdomain_id = s.id), '')
+		FROM subdomains s
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY s.domain, s.first_seen DESC"
+
+	records, err := s.querySubdomainRecords(query, args...)
+	if err != nil || filter.Regex == nil {
+		return records, err
+	}
+
+	var matched []SubdomainRecord
+	for _, r := range records {
+		if filter.Regex.MatchString(r.Subdomain) {
+			matched = append(matched, r)
+		}
+	}
+
+	return matched, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (s *sqlStore) querySubdomainRecords(query string, args ...interface{}) ([]SubdomainRecord, error) {
+	rows, err := s.conn.Query(s.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []SubdomainRecord
+	for rows.Next() {
+		var r SubdomainRecord
+		var tags string
+		if err := rows.Scan(&r.Domain, &r.Subdomain, &r.Status, &r.FirstSeen, &r.LastSeen, &r.TriageState,
+			&r.HTTPStatus, &r.HTTPTitle, &tags); err != nil {
+			return nil, err
+		}
+		r.Tags = splitSources(tags)
+		sort.Strings(r.Tags)
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
+
+func (s *sqlStore) QueryScans(domain string) ([]ScanRecord, error) {
+	query := `
+		SELECT id, domain, started_at, ended_at, options, sources, tool,
+			total_subdomains, new_subdomains, reactivated_subdomains, dead_subdomains
+		FROM scans
+	`
+	var args []interface{}
+
+	if domain != "" {
+		query += " WHERE domain = $1"
+		args = append(args, domain)
+	}
+
+	query += " ORDER BY started_at DESC"
+
+	rows, err := s.conn.Query(s.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []ScanRecord
+	for rows.Next() {
+		var r ScanRecord
+		var sources string
+		if err := rows.Scan(&r.ID, &r.Domain, &r.StartedAt, &r.EndedAt, &r.Options, &sources, &r.Tool,
+			&r.TotalSubdomains, &r.NewSubdomains, &r.Reactivated, &r.DeadSubdomains); err != nil {
+			return nil, err
+		}
+		r.Sources = splitSources(sources)
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
+
+// DiffScans compares the hosts observed by two scans of domain. A zero to picks
+// the latest scan and a zero from picks the scan before to. Imports and CT
+// log batches only hold a few hosts, so they are never picked by default.
+func (s *sqlStore) DiffScans(domain string, from, to int64) (*ScanDiff, error) {
+	if to == 0 {
+		var latest sql.NullInt64
+		err := s.conn.QueryRow(s.dialect.rebind("SELECT MAX(id) FROM scans WHERE domain = $1 AND NOT imported"), domain).Scan(&latest)
+		if err != nil {
+			return nil, err
+		}
+		if !latest.Valid {
+			return nil, fmt.Errorf("no scans recorded for %s", domain)
+		}
+		to = latest.Int64
+	}
+
+	if from == 0 {
+		var previous sql.NullInt64
+		err := s.conn.QueryRow(s.dialect.rebind("SELECT MAX(id) FROM scans WHERE domain = $1 AND id < $2 AND NOT imported"), domain, to).Scan(&previous)
+		if err != nil {
+			return nil, err
+		}
+		if !previous.Valid {
+			return nil, fmt.Errorf("no scan of %s before scan %d", domain, to)
+		}
+		from = previous.Int64
+	}
+
+	for _, id := range []int64{from, to} {
+		var scanDomain string
+		err := s.conn.QueryRow(s.dialect.rebind("SELECT domain FROM scans WHERE id = $1"), id).Scan(&scanDomain)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("scan %d not found", id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if scanDomain != domain {
+			return nil, fmt.Errorf("scan %d belongs to %s, not %s", id, scanDomain, domain)
+		}
+	}
+
+	diff := &ScanDiff{Domain: domain, From: from, To: to}
+
+	var err error
+	if diff.Added, err = s.observedOnlyIn(to, from); err != nil {
+		return nil, err
+	}
+	if diff.Removed, err = s.observedOnlyIn(from, to); err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+func (s *sqlStore) observedOnlyIn(scanID, otherID int64) ([]string, error) {
+	rows, err := s.conn.Query(s.dialect.rebind(`
+		SELECT s.subdomain
+		FROM scan_observations o
+		JOIN subdomains s ON s.id = o.subdomain_id
+		WHERE o.scan_id = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM scan_observations p
+				WHERE p.scan_id = $2 AND p.subdomain_id = o.subdomain_id
+			)
+		ORDER BY s.subdomain
+	`), scanID, otherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hosts []string
+	for rows.Next() {
+		var host string
+		if err := rows.Scan(&host); err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+
+	return hosts, rows.Err()
+}
+
+func (s *sqlStore) QueryScanObservations(scanID int64) ([]ObservationRecord, error) {
+	rows, err := s.conn.Query(s.dialect.rebind(`
+		SELECT o.scan_id, sc.started_at, s.domain, s.subdomain, o.sources
+		FROM scan_observations o
+		JOIN scans sc ON sc.id = o.scan_id
+		JOIN subdomains s ON s.id = o.subdomain_id
+		WHERE o.scan_id = $1
+		ORDER BY s.subdomain
+	`), scanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []ObservationRecord
+	for rows.Next() {
+		var r ObservationRecord
+		var sources string
+		if err := rows.Scan(&r.ScanID, &r.StartedAt, &r.Domain, &r.Subdomain, &sources); err != nil {
+			return nil, err
+		}
+		r.Seen = true
+		r.Sources = splitSources(sources)
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
+
+// QuerySubdomainTimeline returns one entry per scan of the subdomain's domain
+// since it was first seen, with Seen set when that scan observed it.
+func (s *sqlStore) QuerySubdomainTimeline(subdomain string) ([]ObservationRecord, error) {
+	rows, err := s.conn.Query(s.dialect.rebind(`
+		SELECT sc.id, sc.started_at, s.domain, s.subdomain, o.scan_id IS NOT NULL, COALESCE(o.sources, '')
+		FROM subdomains s
+		JOIN scans sc ON sc.domain = s.domain
+		LEFT JOIN scan_observations o ON o.scan_id = sc.id AND o.subdomain_id = s.id
+		WHERE s.subdomain = $1
+			AND (o.scan_id IS NOT NULL OR sc.started_at >= s.first_seen)
+		ORDER BY sc.started_at ASC
+	`), subdomain)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []ObservationRecord
+	for rows.Next() {
+		var r ObservationRecord
+		var sources string
+		if err := rows.Scan(&r.ScanID, &r.StartedAt, &r.Domain, &r.Subdomain, &r.Seen, &sources); err != nil {
+			return nil, err
+		}
+		r.Sources = splitSources(sources)
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
+
+func (s *sqlStore) QueryStatusChanges(subdomain string) ([]StatusChange, error) {
+	rows, err := s.conn.Query(s.dialect.rebind(`
+		SELECT COALESCE(c.scan_id, 0), s.subdomain, c.old_status, c.new_status, c.reason, c.changed_at
+		FROM status_changes c
+		JOIN subdomains s ON s.id = c.subdomain_id
+		WHERE s.subdomain = $1
+		ORDER BY c.changed_at ASC, c.id ASC
+	`), subdomain)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []StatusChange
+	for rows.Next() {
+		var c StatusChange
+		if err := rows.Scan(&c.ScanID, &c.Subdomain, &c.OldStatus, &c.NewStatus, &c.Reason, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, rows.Err()
+}
+
+func (s *sqlStore) AddTags(subdomain string, tags []string) (int, error) {
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var matched int
+	err = tx.QueryRow(s.dialect.rebind("SELECT COUNT(*) FROM subdomains WHERE subdomain = $1"), subdomain).Scan(&matched)
+	if err != nil {
+		return 0, err
+	}
+	if matched == 0 {
+		return 0, fmt.Errorf("subdomain %s is not tracked", subdomain)
+	}
+
+	for _, tag := range tags {
+		_, err := tx.Exec(s.dialect.rebind(`
+			INSERT INTO subdomain_tags (subdomain_id, tag)
+			SELECT id, $2 FROM subdomains WHERE subdomain = $1
+			ON CONFLICT DO NOTHING
+		`), subdomain, tag)
+		if err != nil {
+			return 0, fmt.Errorf("failed to tag %s: %w", subdomain, err)
+		}
+	}
+
+	return matched, tx.Commit()
+}
+
+func (s *sqlStore) RemoveTags(subdomain string, tags []string) (int, error) {
+	removed := 0
+	for _, tag := range tags {
+		res, err := s.conn.Exec(s.dialect.rebind(`
+			DELETE FROM subdomain_tags
+			WHERE tag = $2 AND subdomain_id IN (SELECT id FROM subdomains WHERE subdomain = $1)
+		`), subdomain, tag)
+		if err != nil {
+			return removed, fmt.Errorf("failed to untag %s: %w", subdomain, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return removed, err
+		}
+		removed += int(n)
+	}
+
+	return removed, nil
+}
+
+func (s *sqlStore) SetTriageState(subdomain, state string) (int, error) {
+	if !ValidTriageState(state) {
+		return 0, fmt.Errorf("invalid triage state %q (valid: %s)", state, strings.Join(TriageStates, ", "))
+	}
+
+	res, err := s.conn.Exec(s.dialect.rebind("UPDATE subdomains SET triage_state = $2 WHERE subdomain = $1"), subdomain, state)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("subdomain %s is not tracked", subdomain)
+	}
+
+	return int(n), nil
+}
+
+func (s *sqlStore) AddNote(subdomain, note string) (int, error) {
+	res, err := s.conn.Exec(s.dialect.rebind(`
+		INSERT INTO subdomain_notes (subdomain_id, note)
+		SELECT id, $2 FROM subdomains WHERE subdomain = $1
+	`), subdomain, note)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("subdomain %s is not tracked", subdomain)
+	}
+
+	return int(n), nil
+}
+
+func (s *sqlStore) QueryNotes(subdomain string) ([]Note, error) {
+	rows, err := s.conn.Query(s.dialect.rebind(`
+		SELECT s.domain, s.subdomain, n.note, n.created_at
+		FROM subdomain_notes n
+		JOIN subdomains s ON s.id = n.subdomain_id
+		WHERE s.subdomain = $1
+		ORDER BY n.created_at ASC, n.id ASC
+	`), subdomain)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []Note
+	for rows.Next() {
+		var n Note
+		if err := rows.Scan(&n.Domain, &n.Subdomain, &n.Note, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notes = append(notes, n)
+	}
+
+	return notes, rows.Err()
+}
+
+// ApplyVerification sets status from live evidence instead of source presence:
+// resolving hosts become ACTIVE and hosts that do not resolve become DEAD.
+// Changes are recorded in status_changes without a scan id.
+func (s *sqlStore) ApplyVerification(domain string, results []VerifyResult) (*VerifySummary, error) {
+	summary := &VerifySummary{Domain: domain}
+
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = s.dialect.createTempTable(tx, "verify_batch", `(
+		subdomain TEXT PRIMARY KEY,
+		resolves INTEGER NOT NULL,
+		probed INTEGER NOT NULL,
+		http_status INTEGER NOT NULL,
+		http_title TEXT NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage verification: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var rows [][]interface{}
+	for _, r := range results {
+		if seen[r.Subdomain] {
+			continue
+		}
+		seen[r.Subdomain] = true
+		rows = append(rows, []interface{}{r.Subdomain, boolInt(r.Resolves), boolInt(r.Probed), r.HTTPStatus, r.HTTPTitle})
+	}
+	if err := s.dialect.copyRows(tx, "verify_batch", []string{"subdomain", "resolves", "probed", "http_status", "http_title"}, rows); err != nil {
+		return nil, fmt.Errorf("failed to stage verification: %w", err)
+	}
+
+	err = tx.QueryRow(s.dialect.rebind(`
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE v.resolves = 1),
+			COUNT(*) FILTER (WHERE v.resolves = 1 AND s.status = 'DEAD'),
+			COUNT(*) FILTER (WHERE v.resolves = 0 AND s.status != 'DEAD'),
+			COUNT(*) FILTER (WHERE v.probed = 1 AND v.http_status > 0)
+		FROM verify_batch v
+		JOIN subdomains s ON s.domain = $1 AND s.subdomain = v.subdomain
+	`), domain).Scan(&summary.Checked, &summary.Alive, &summary.Revived, &summary.Died, &summary.Responding)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(s.dialect.rebind(`
+		INSERT INTO status_changes (subdomain_id, scan_id, old_status, new_status, reason, changed_at)
+		SELECT s.id, NULL, s.status,
+			CASE WHEN v.resolves = 1 THEN 'ACTIVE' ELSE 'DEAD' END,
+			CASE
+				WHEN v.resolves = 0 THEN 'verify: does not resolve'
+				WHEN s.status = 'DEAD' THEN 'revived: resolves again (verify)'
+				ELSE 'verify: resolves'
+			END,
+			NOW()
+		FROM verify_batch v
+		JOIN subdomains s ON s.domain = $1 AND s.subdomain = v.subdomain
+		WHERE s.status != CASE WHEN v.resolves = 1 THEN 'ACTIVE' ELSE 'DEAD' END
+	`), domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record status changes: %w", err)
+	}
+
+	_, err = tx.Exec(s.dialect.rebind(`
+		UPDATE subdomains
+		SET last_seen = NOW(),
+			miss_count = 0,
+			verified_at = NOW(),
+			status_reason = CASE
+				WHEN status = 'ACTIVE' THEN status_reason
+				WHEN status = 'DEAD' THEN 'revived: resolves again (verify)'
+				ELSE 'verify: resolves'
+			END,
+			status = 'ACTIVE'
+		WHERE domain = $1
+			AND subdomain IN (SELECT subdomain FROM verify_batch WHERE resolves = 1)
+	`), domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update resolving hosts: %w", err)
+	}
+
+	_, err = tx.Exec(s.dialect.rebind(`
+		UPDATE subdomains
+		SET verified_at = NOW(),
+			status_reason = CASE WHEN status = 'DEAD' THEN status_reason ELSE 'verify: does not resolve' END,
+			status = 'DEAD'
+		WHERE domain = $1
+			AND subdomain IN (SELECT subdomain FROM verify_batch WHERE resolves = 0)
+	`), domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update dead hosts: %w", err)
+	}
+
+	_, err = tx.Exec(s.dialect.rebind(`
+		UPDATE subdomains
+		SET http_status = (SELECT v.http_status FROM verify_batch v WHERE v.subdomain = subdomains.subdomain),
+			http_title = (SELECT v.http_title FROM verify_batch v WHERE v.subdomain = subdomains.subdomain)
+		WHERE domain = $1
+			AND subdomain IN (SELECT subdomain FROM verify_batch WHERE probed = 1)
+	`), domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store probe results: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
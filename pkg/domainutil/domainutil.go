@@ -0,0 +1,184 @@
+package domainutil
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/weppos/publicsuffix-go/publicsuffix"
+)
+
+// ListURL is where Download fetches the current Public Suffix List.
+const ListURL = "https://publicsuffix.org/list/public_suffix_list.dat"
+
+var (
+	mu     sync.RWMutex
+	list   = publicsuffix.DefaultList
+	source = "embedded (" + publicsuffix.ListVersion + ")"
+)
+
+// Only the ICANN section of the list is used: a host under a private suffix
+// such as github.io or herokuapp.com belongs to the operator's domain. TLDs
+// missing from the list are treated as one-label suffixes.
+var findOptions = &publicsuffix.FindOptions{IgnorePrivate: true, DefaultRule: publicsuffix.DefaultRule}
+
+// LoadList replaces the embedded list with the one in path.
+func LoadList(path string) error {
+	l, err := publicsuffix.NewListFromFile(path, &publicsuffix.ParserOption{PrivateDomains: true})
+	if err != nil {
+		return fmt.Errorf("failed to load public suffix list %s: %w", path, err)
+	}
+	if l.Size() == 0 {
+		return fmt.Errorf("public suffix list %s has no rules", path)
+	}
+
+	mu.Lock()
+	list = l
+	source = path
+	mu.Unlock()
+	return nil
+}
+
+// Source describes the list in use: the embedded version or the loaded file.
+func Source() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return source
+}
+
+// Download fetches the current list from ListURL into path and returns its
+// number of rules. The file is only replaced once the download parsed.
+func Download(path string) (int, error) {
+	client := &http.Client{Timeout: 60 * time.Second}
+
+	resp, err := client.Get(ListURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download public suffix list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to download public suffix list: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read public suffix list: %w", err)
+	}
+
+	l, err := publicsuffix.NewListFromString(string(data), &publicsuffix.ParserOption{PrivateDomains: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse public suffix list: %w", err)
+	}
+	if l.Size() == 0 {
+		return 0, fmt.Errorf("downloaded public suffix list has no rules")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write public suffix list: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("failed to write public suffix list: %w", err)
+	}
+
+	return l.Size(), nil
+}
+
+func normalize(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(host, "*.")
+	return strings.Trim(host, ".")
+}
+
+// split returns the labels of host and how many of the last ones make its
+// public suffix.
+func split(host string) ([]string, int) {
+	host = normalize(host)
+	if host == "" {
+		return nil, 0
+	}
+
+	mu.RLock()
+	rule := list.Find(host, findOptions)
+	mu.RUnlock()
+
+	labels := strings.Split(host, ".")
+	return labels, min(suffixLabels(rule), len(labels))
+}
+
+// suffixLabels returns how many labels the public suffix of a name matched by
+// rule has. A wildcard rule's Length already counts the wildcard label, and
+// an exception rule stands for its value minus the leftmost label.
+func suffixLabels(rule *publicsuffix.Rule) int {
+	switch {
+	case rule == publicsuffix.DefaultRule:
+		return 1
+	case rule.Type == publicsuffix.ExceptionType:
+		return rule.Length - 1
+	default:
+		return rule.Length
+	}
+}
+
+// PublicSuffix returns the public suffix of host: "co.uk" for
+// "www.example.co.uk".
+func PublicSuffix(host string) string {
+	labels, n := split(host)
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
+// RegistrableDomain returns the public suffix of host plus one label:
+// "example.co.uk" for "www.example.co.uk". It is empty when host is itself a
+// public suffix.
+func RegistrableDomain(host string) string {
+	labels, n := split(host)
+	if len(labels) <= n {
+		return ""
+	}
+	return strings.Join(labels[len(labels)-n-1:], ".")
+}
+
+// Level returns the depth of host with its public suffix counted as a single
+// label, so that "example.com" and "example.co.uk" are both level 2 and
+// "www.example.co.uk" is level 3.
+func Level(host string) int {
+	labels, n := split(host)
+	if len(labels) == 0 {
+		return 0
+	}
+	return len(labels) - n + 1
+}
+
+// Subdomain returns the part of host left of apex: "dev.api" for
+// "dev.api.example.co.uk" under "example.co.uk". An empty apex stands for the
+// registrable domain of host. ok is false when host is not apex or one of its
+// subdomains.
+func Subdomain(host, apex string) (string, bool) {
+	host = normalize(host)
+	if apex == "" {
+		apex = RegistrableDomain(host)
+	} else {
+		apex = normalize(apex)
+	}
+
+	if host == "" || apex == "" {
+		return "", false
+	}
+	if host == apex {
+		return "", true
+	}
+	if !strings.HasSuffix(host, "."+apex) {
+		return "", false
+	}
+	return strings.TrimSuffix(host, "."+apex), true
+}
@@ -0,0 +1,77 @@
+package domainutil
+
+import "testing"
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		host        string
+		suffix      string
+		registrable string
+		level       int
+	}{
+		{"example.com", "com", "example.com", 2},
+		{"www.example.com", "com", "example.com", 3},
+		{"a.b.c.example.com", "com", "example.com", 5},
+		{"example.co.uk", "co.uk", "example.co.uk", 2},
+		{"www.example.co.uk", "co.uk", "example.co.uk", 3},
+		{"api.example.com.br", "com.br", "example.com.br", 3},
+		{"co.uk", "co.uk", "", 1},
+		{"com", "com", "", 1},
+
+		// wildcard and exception rules: *.ck, !www.ck, *.kawasaki.jp, !city.kawasaki.jp
+		{"a.example.ck", "example.ck", "a.example.ck", 2},
+		{"example.ck", "example.ck", "", 1},
+		{"www.ck", "ck", "www.ck", 2},
+		{"a.www.ck", "ck", "www.ck", 3},
+		{"x.shop.kawasaki.jp", "shop.kawasaki.jp", "x.shop.kawasaki.jp", 2},
+		{"city.kawasaki.jp", "kawasaki.jp", "city.kawasaki.jp", 2},
+		{"www.city.kawasaki.jp", "kawasaki.jp", "city.kawasaki.jp", 3},
+
+		// private suffixes belong to their operator's domain
+		{"user.github.io", "io", "github.io", 3},
+
+		// TLDs missing from the list are one label
+		{"www.example.zzz", "zzz", "example.zzz", 3},
+
+		{"*.WWW.Example.COM.", "com", "example.com", 3},
+		{"", "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := PublicSuffix(tt.host); got != tt.suffix {
+				t.Errorf("PublicSuffix(%q) = %q, want %q", tt.host, got, tt.suffix)
+			}
+			if got := RegistrableDomain(tt.host); got != tt.registrable {
+				t.Errorf("RegistrableDomain(%q) = %q, want %q", tt.host, got, tt.registrable)
+			}
+			if got := Level(tt.host); got != tt.level {
+				t.Errorf("Level(%q) = %d, want %d", tt.host, got, tt.level)
+			}
+		})
+	}
+}
+
+func TestSubdomain(t *testing.T) {
+	tests := []struct {
+		host string
+		apex string
+		want string
+		ok   bool
+	}{
+		{"dev.api.example.co.uk", "example.co.uk", "dev.api", true},
+		{"dev.api.example.co.uk", "", "dev.api", true},
+		{"example.com", "example.com", "", true},
+		{"WWW.Example.com.", "example.com", "www", true},
+		{"www.notexample.com", "example.com", "", false},
+		{"www.example.org", "example.com", "", false},
+		{"co.uk", "", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Subdomain(tt.host, tt.apex)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Subdomain(%q, %q) = %q, %v, want %q, %v", tt.host, tt.apex, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package domainutil
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// hostnameProfile is idna.Lookup without the STD3 rules, which reject the
+// underscores of names such as _dmarc.example.com; validateHostname checks
+// the ASCII form instead.
+var hostnameProfile = idna.New(idna.MapForLookup(), idna.StrictDomainName(false), idna.BidiRule())
+
+// NormalizeHostname turns a raw value reported by a source into the form
+// every host is stored and compared in: lowercase, in punycode, without
+// scheme, path, port, trailing dots or leading wildcard labels. Only the
+// punycode form is kept; internationalized names are stored and printed as
+// xn-- labels. Values that are not a hostname of at least two RFC 1035
+// labels are rejected, except that labels may contain underscores.
+func NormalizeHostname(raw string) (string, error) {
+	host := strings.TrimSpace(raw)
+
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/?#"); i >= 0 {
+		host = host[:i]
+	}
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	host = stripPort(host)
+
+	host = strings.TrimRight(host, ".")
+	for strings.HasPrefix(host, "*.") {
+		host = host[2:]
+	}
+	if host == "" {
+		return "", fmt.Errorf("empty hostname in %q", raw)
+	}
+	if net.ParseIP(host) != nil {
+		return "", fmt.Errorf("%q is an IP address", raw)
+	}
+
+	ascii, err := hostnameProfile.ToASCII(strings.ToLower(host))
+	if err != nil {
+		return "", fmt.Errorf("invalid hostname %q: %w", raw, err)
+	}
+	if err := validateHostname(ascii); err != nil {
+		return "", fmt.Errorf("invalid hostname %q: %w", raw, err)
+	}
+	return ascii, nil
+}
+
+func stripPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// validateHostname applies the RFC 1035 label rules, with the RFC 1123
+// relaxation that labels may start with a digit, and allows underscores as
+// DNS itself does (RFC 2181), for service labels such as _dmarc.
+func validateHostname(host string) error {
+	if len(host) > 253 {
+		return fmt.Errorf("longer than 253 characters")
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("single label")
+	}
+
+	for _, label := range labels {
+		if label == "" {
+			return fmt.Errorf("empty label")
+		}
+		if len(label) > 63 {
+			return fmt.Errorf("label longer than 63 characters")
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("label %q starts or ends with a hyphen", label)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return fmt.Errorf("label %q has invalid character %q", label, c)
+			}
+		}
+	}
+	return nil
+}
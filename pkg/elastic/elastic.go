@@ -2,26 +2,35 @@ package elastic
 
 import (
     "bufio"
+    "bytes"
     "context"
+    "encoding/json"
     "errors"
     "fmt"
     "os"
     "strings"
+    "sync"
+    "time"
 
     es8 "github.com/elastic/go-elasticsearch/v8"
     "github.com/elastic/go-elasticsearch/v8/esutil"
 )
 
 type Config struct {
-    URL      string
-    Username string
-    Password string
-    Index    string
+    URL            string
+    Username       string
+    Password       string
+    Index          string
+    SubdomainIndex string
 }
 
 type Client struct {
-    es    *es8.Client
-    index string
+    es             *es8.Client
+    index          string
+    subdomainIndex string
+
+    mu        sync.Mutex
+    templates map[string]bool
 }
 
 func New(cfg Config) (*Client, error) {
@@ -32,6 +41,10 @@ func New(cfg Config) (*Client, error) {
     if strings.TrimSpace(index) == "" {
         index = "samoscout_httpx"
     }
+    subdomainIndex := cfg.SubdomainIndex
+    if strings.TrimSpace(subdomainIndex) == "" {
+        subdomainIndex = "samoscout_subdomains"
+    }
 
     es, err := es8.NewClient(es8.Config{
         Addresses: []string{cfg.URL},
@@ -47,55 +60,100 @@ func New(cfg Config) (*Client, error) {
         return nil, fmt.Errorf("failed to connect to elasticsearch: %w", err)
     }
 
-    return &Client{es: es, index: index}, nil
+    return &Client{
+        es:             es,
+        index:          index,
+        subdomainIndex: subdomainIndex,
+        templates:      make(map[string]bool),
+    }, nil
 }
 
-func (c *Client) IndexJSONLinesFile(ctx context.Context, filename string) error {
+// IndexJSONLinesFile indexes every line of an httpx JSONL file, see
+// IndexHTTPXDocuments.
+func (c *Client) IndexJSONLinesFile(ctx context.Context, filename string, scanTime time.Time) (*IndexStats, error) {
     f, err := os.Open(filename)
     if err != nil {
-        return fmt.Errorf("failed to open jsonl file: %w", err)
+        return nil, fmt.Errorf("failed to open jsonl file: %w", err)
     }
     defer f.Close()
 
+    var docs []json.RawMessage
+
+    scanner := bufio.NewScanner(f)
+    buf := make([]byte, 0, 1024*1024)
+    scanner.Buffer(buf, 8*1024*1024)
+
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" {
+            continue
+        }
+        docs = append(docs, json.RawMessage(line))
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, fmt.Errorf("scanner error: %w", err)
+    }
+
+    return c.IndexHTTPXDocuments(ctx, docs, scanTime)
+}
+
+// IndexHTTPXDocuments indexes httpx JSON results. Document IDs are derived from
+// the URL and the scan date, so re-running a scan on the same day updates the
+// documents instead of duplicating them.
+func (c *Client) IndexHTTPXDocuments(ctx context.Context, docs []json.RawMessage, scanTime time.Time) (*IndexStats, error) {
+    if err := c.ensureTemplate(ctx, c.index, "httpx"); err != nil {
+        return nil, err
+    }
+
     bi, err := esutil.NewBulkIndexer(esutil.BulkIndexerConfig{
         Client:     c.es,
         Index:      c.index,
         NumWorkers: 4,
     })
     if err != nil {
-        return fmt.Errorf("failed to create bulk indexer: %w", err)
+        return nil, fmt.Errorf("failed to create bulk indexer: %w", err)
     }
 
-    scanner := bufio.NewScanner(f)
-    buf := make([]byte, 0, 1024*1024)
-    scanner.Buffer(buf, 8*1024*1024)
+    collector := &failureCollector{stats: &IndexStats{Index: c.index}}
+    scanDate := scanTime.UTC().Format("2006-01-02")
+    invalid := 0
 
-    for scanner.Scan() {
-        line := strings.TrimSpace(scanner.Text())
-        if line == "" {
+    for _, raw := range docs {
+        var doc map[string]interface{}
+        if err := json.Unmarshal(raw, &doc); err != nil {
+            invalid++
+            collector.sample(fmt.Sprintf("invalid JSON document: %v", err))
             continue
         }
 
+        key, _ := doc["url"].(string)
+        if key == "" {
+            key, _ = doc["input"].(string)
+        }
+        if key == "" {
+            key = string(raw)
+        }
+        doc["scan_date"] = scanDate
+
+        body, err := json.Marshal(doc)
+        if err != nil {
+            return nil, fmt.Errorf("failed to encode document: %w", err)
+        }
+
         item := esutil.BulkIndexerItem{
             Action:     "index",
-            DocumentID: "",
-            Body:       strings.NewReader(line),
-            OnFailure: func(ctx context.Context, item esutil.BulkIndexerItem, resp esutil.BulkIndexerResponseItem, err error) {
-            },
+            DocumentID: documentID(key, scanDate),
+            Body:       bytes.NewReader(body),
+            OnFailure:  collector.onFailure,
         }
         if err := bi.Add(ctx, item); err != nil {
-            return fmt.Errorf("bulk add failed: %w", err)
+            return nil, fmt.Errorf("bulk add failed: %w", err)
         }
     }
-    if err := scanner.Err(); err != nil {
-        return fmt.Errorf("scanner error: %w", err)
-    }
 
     if err := bi.Close(ctx); err != nil {
-        return fmt.Errorf("bulk indexer close failed: %w", err)
+        return nil, fmt.Errorf("bulk indexer close failed: %w", err)
     }
 
-    return nil
+    return collector.finish(bi, invalid), nil
 }
-
-
@@ -0,0 +1,70 @@
+package elastic
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/elastic/go-elasticsearch/v8/esutil"
+)
+
+// maxFailureSamples bounds how many bulk failure reasons are kept per call.
+const maxFailureSamples = 5
+
+// IndexStats reports the outcome of one bulk indexing call. Failures holds a
+// sample of the reasons Elasticsearch gave for rejected documents.
+type IndexStats struct {
+	Index    string
+	Indexed  int
+	Failed   int
+	Failures []string
+}
+
+type failureCollector struct {
+	mu    sync.Mutex
+	stats *IndexStats
+}
+
+func (f *failureCollector) onFailure(ctx context.Context, item esutil.BulkIndexerItem, resp esutil.BulkIndexerResponseItem, err error) {
+	reason := ""
+	switch {
+	case err != nil:
+		reason = err.Error()
+	case resp.Error.Cause.Reason != "":
+		reason = fmt.Sprintf("%s: %s (%s: %s)", resp.Error.Type, resp.Error.Reason, resp.Error.Cause.Type, resp.Error.Cause.Reason)
+	default:
+		reason = fmt.Sprintf("%s: %s", resp.Error.Type, resp.Error.Reason)
+	}
+
+	f.sample(fmt.Sprintf("[%s] %s", item.DocumentID, reason))
+}
+
+// sample keeps reason if fewer than maxFailureSamples have been kept. It is
+// called from the bulk indexer workers, hence the lock.
+func (f *failureCollector) sample(reason string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.stats.Failures) < maxFailureSamples {
+		f.stats.Failures = append(f.stats.Failures, reason)
+	}
+}
+
+// finish fills in the indexer's counters; skipped counts documents that were
+// rejected before reaching Elasticsearch.
+func (f *failureCollector) finish(bi esutil.BulkIndexer, skipped int) *IndexStats {
+	s := bi.Stats()
+	f.stats.Indexed = int(s.NumFlushed)
+	f.stats.Failed = int(s.NumFailed) + skipped
+	return f.stats
+}
+
+// documentID derives a stable ID from parts, so indexing the same document of
+// the same scan day twice overwrites it instead of creating a duplicate.
+func documentID(parts ...string) string {
+	sum := sha1.Sum([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
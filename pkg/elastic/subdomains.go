@@ -0,0 +1,76 @@
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esutil"
+)
+
+type DNSRecords struct {
+	A     []string `json:"a,omitempty"`
+	AAAA  []string `json:"aaaa,omitempty"`
+	CNAME string   `json:"cname,omitempty"`
+}
+
+// SubdomainDocument is one subdomain of one scan in the subdomain index.
+type SubdomainDocument struct {
+	Timestamp     time.Time  `json:"@timestamp"`
+	Domain        string     `json:"domain"`
+	Subdomain     string     `json:"subdomain"`
+	Sources       []string   `json:"sources"`
+	Phase         string     `json:"phase"`
+	Resolves      bool       `json:"resolves"`
+	DNS           DNSRecords `json:"dns"`
+	Status        string     `json:"status,omitempty"`
+	ScanID        int64      `json:"scan_id,omitempty"`
+	ScanStartedAt time.Time  `json:"scan_started_at"`
+	ScanEndedAt   time.Time  `json:"scan_ended_at"`
+	FirstSeen     *time.Time `json:"first_seen,omitempty"`
+	LastSeen      *time.Time `json:"last_seen,omitempty"`
+}
+
+// IndexSubdomains bulk-indexes docs into the subdomain index. Each subdomain
+// gets one document per scan day, so repeated scans on the same day update it.
+func (c *Client) IndexSubdomains(ctx context.Context, docs []SubdomainDocument) (*IndexStats, error) {
+	if err := c.ensureTemplate(ctx, c.subdomainIndex, "subdomains"); err != nil {
+		return nil, err
+	}
+
+	bi, err := esutil.NewBulkIndexer(esutil.BulkIndexerConfig{
+		Client:     c.es,
+		Index:      c.subdomainIndex,
+		NumWorkers: 4,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bulk indexer: %w", err)
+	}
+
+	collector := &failureCollector{stats: &IndexStats{Index: c.subdomainIndex}}
+
+	for _, doc := range docs {
+		body, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode document for %s: %w", doc.Subdomain, err)
+		}
+
+		item := esutil.BulkIndexerItem{
+			Action:     "index",
+			DocumentID: documentID(doc.Domain, doc.Subdomain, doc.ScanStartedAt.UTC().Format("2006-01-02")),
+			Body:       bytes.NewReader(body),
+			OnFailure:  collector.onFailure,
+		}
+		if err := bi.Add(ctx, item); err != nil {
+			return nil, fmt.Errorf("bulk add failed: %w", err)
+		}
+	}
+
+	if err := bi.Close(ctx); err != nil {
+		return nil, fmt.Errorf("bulk indexer close failed: %w", err)
+	}
+
+	return collector.finish(bi, 0), nil
+}
@@ -0,0 +1,105 @@
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// templateVersion is stored on every installed index template; bump it when a
+// mapping under templates/ changes so existing clusters get the new one.
+const templateVersion = 1
+
+//go:embed templates/*.json
+var templateFS embed.FS
+
+// ensureTemplate installs the composable index template for index the first
+// time this client writes to it. Mappings only apply to indices created after
+// the template, so an existing dynamically mapped index keeps its mapping.
+func (c *Client) ensureTemplate(ctx context.Context, index, mapping string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.templates[index] {
+		return nil
+	}
+
+	version, err := c.installedTemplateVersion(ctx, index)
+	if err != nil {
+		return err
+	}
+
+	if version < templateVersion {
+		template, err := templateFS.ReadFile("templates/" + mapping + ".json")
+		if err != nil {
+			return fmt.Errorf("failed to read %s template: %w", mapping, err)
+		}
+
+		body, err := json.Marshal(map[string]interface{}{
+			"index_patterns": []string{index},
+			"priority":       200,
+			"version":        templateVersion,
+			"template":       json.RawMessage(template),
+			"_meta":          map[string]string{"managed_by": "samoscout"},
+		})
+		if err != nil {
+			return err
+		}
+
+		res, err := c.es.Indices.PutIndexTemplate(index, bytes.NewReader(body), c.es.Indices.PutIndexTemplate.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to install index template %s: %w", index, err)
+		}
+		defer res.Body.Close()
+
+		if res.IsError() {
+			return fmt.Errorf("failed to install index template %s: %s", index, responseError(res.Body))
+		}
+	}
+
+	c.templates[index] = true
+	return nil
+}
+
+func (c *Client) installedTemplateVersion(ctx context.Context, name string) (int, error) {
+	res, err := c.es.Indices.GetIndexTemplate(
+		c.es.Indices.GetIndexTemplate.WithName(name),
+		c.es.Indices.GetIndexTemplate.WithContext(ctx),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up index template %s: %w", name, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if res.IsError() {
+		return 0, fmt.Errorf("failed to look up index template %s: %s", name, responseError(res.Body))
+	}
+
+	var body struct {
+		IndexTemplates []struct {
+			IndexTemplate struct {
+				Version int `json:"version"`
+			} `json:"index_template"`
+		} `json:"index_templates"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("failed to decode index template %s: %w", name, err)
+	}
+	if len(body.IndexTemplates) == 0 {
+		return 0, nil
+	}
+
+	return body.IndexTemplates[0].IndexTemplate.Version, nil
+}
+
+func responseError(body io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(body, 4096))
+	return string(bytes.TrimSpace(data))
+}
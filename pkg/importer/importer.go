@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/samogod/samoscout/pkg/domainutil"
+)
+
+const (
+	FormatAuto      = "auto"
+	FormatSubfinder = "subfinder"
+	FormatAmass     = "amass"
+	FormatSamoscout = "samoscout"
+	FormatList      = "list"
+)
+
+var Formats = []string{FormatAuto, FormatSubfinder, FormatAmass, FormatSamoscout, FormatList}
+
+// Record is one host read from another tool's output. Domain is the target
+// the tool was run against when the format records it.
+type Record struct {
+	Host    string
+	Domain  string
+	Sources []string
+}
+
+type Result struct {
+	Format  string
+	Records []Record
+	Skipped int
+}
+
+// subfinder -oJ and samoscout -json share this layout; subfinder -cs adds sources.
+// Only the IP, URL and related domain lines of samoscout -json have a type.
+type hostRecord struct {
+	Type    string   `json:"type"`
+	Host    string   `json:"host"`
+	Input   string   `json:"input"`
+	Source  string   `json:"source"`
+	Sources []string `json:"sources"`
+}
+
+type amassRecord struct {
+	Name    string   `json:"name"`
+	Domain  string   `json:"domain"`
+	Sources []string `json:"sources"`
+}
+
+func ParseFile(path, format string) (*Result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Parse(file, format)
+}
+
+// Parse reads every record of r. Lines that cannot be parsed are counted in
+// Result.Skipped rather than failing the whole import. Artifact lines are
+// ignored; the host they were found on has a line of its own.
+func Parse(r io.Reader, format string) (*Result, error) {
+	if format == "" {
+		format = FormatAuto
+	}
+	if !validFormat(format) {
+		return nil, fmt.Errorf("unknown format %q (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+
+	result := &Result{Format: format}
+
+	scanner := bufio.NewScanner(r)
+	buf := make([]byte, 0, 1024*1024)
+	scanner.Buffer(buf, 16*1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if result.Format == FormatAuto {
+			result.Format = detectFormat(line)
+		}
+
+		record, ok := parseLine(line, result.Format)
+		if !ok {
+			result.Skipped++
+			continue
+		}
+		if record.Host == "" {
+			continue
+		}
+		result.Records = append(result.Records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if result.Format == FormatAuto {
+		result.Format = FormatList
+	}
+
+	return result, nil
+}
+
+// detectFormat looks at the first record. Host-keyed JSON lines are reported as
+// subfinder; samoscout output has the same shape and needs an explicit format.
+func detectFormat(line string) string {
+	if !strings.HasPrefix(line, "{") {
+		return FormatList
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(line), &keys); err != nil {
+		return FormatList
+	}
+
+	if _, ok := keys["name"]; ok {
+		return FormatAmass
+	}
+	return FormatSubfinder
+}
+
+func parseLine(line, format string) (Record, bool) {
+	var record Record
+
+	switch format {
+	case FormatSubfinder, FormatSamoscout:
+		var r hostRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			return record, false
+		}
+		if r.Type != "" {
+			return record, true
+		}
+		record = Record{Host: r.Host, Domain: r.Input, Sources: r.Sources}
+		if r.Source != "" && r.Source != "unknown" {
+			record.Sources = append(record.Sources, r.Source)
+		}
+
+	case FormatAmass:
+		var r amassRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			return record, false
+		}
+		record = Record{Host: r.Name, Domain: r.Domain, Sources: r.Sources}
+
+	case FormatList:
+		// tolerate "host,extra" and "host extra" lines from other tools
+		host := strings.FieldsFunc(line, func(c rune) bool {
+			return c == ',' || c == ' ' || c == '\t'
+		})[0]
+		record = Record{Host: host}
+	}
+
+	record.Host = NormalizeHost(record.Host)
+	record.Domain = NormalizeHost(record.Domain)
+	for i, source := range record.Sources {
+		record.Sources[i] = strings.ToLower(strings.TrimSpace(source))
+	}
+
+	return record, record.Host != ""
+}
+
+// NormalizeHost returns host the way scans store it, or "" when it is not a
+// valid hostname.
+func NormalizeHost(host string) string {
+	normalized, err := domainutil.NormalizeHostname(host)
+	if err != nil {
+		return ""
+	}
+	return normalized
+}
+
+func validFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		format  string
+		want    string
+		records []Record
+		skipped int
+	}{
+		{
+			name:   "plain list",
+			input:  "a.example.com\n\n# comment\nB.Example.com.\n",
+			format: FormatAuto,
+			want:   FormatList,
+			records: []Record{
+				{Host: "a.example.com"},
+				{Host: "b.example.com"},
+			},
+		},
+		{
+			name:   "list with extra columns",
+			input:  "a.example.com,1.2.3.4\nb.example.com 5.6.7.8\nc.example.com\tx\n",
+			format: FormatAuto,
+			want:   FormatList,
+			records: []Record{
+				{Host: "a.example.com"},
+				{Host: "b.example.com"},
+				{Host: "c.example.com"},
+			},
+		},
+		{
+			name:    "list drops invalid hosts",
+			input:   "a.example.com\n1.2.3.4\nlocalhost\n",
+			format:  FormatList,
+			want:    FormatList,
+			records: []Record{{Host: "a.example.com"}},
+			skipped: 2,
+		},
+		{
+			name: "subfinder json",
+			input: `{"host":"WWW.example.com","input":"example.com","source":"crtsh"}
+{"host":"api.example.com","input":"example.com","sources":["AlienVault","crtsh"]}
+{"host":"x.example.com","input":"example.com","source":"unknown"}`,
+			format: FormatAuto,
+			want:   FormatSubfinder,
+			records: []Record{
+				{Host: "www.example.com", Domain: "example.com", Sources: []string{"crtsh"}},
+				{Host: "api.example.com", Domain: "example.com", Sources: []string{"alienvault", "crtsh"}},
+				{Host: "x.example.com", Domain: "example.com"},
+			},
+		},
+		{
+			name:   "amass json",
+			input:  `{"name":"mail.example.com","domain":"example.com","sources":["DNS"]}`,
+			format: FormatAuto,
+			want:   FormatAmass,
+			records: []Record{
+				{Host: "mail.example.com", Domain: "example.com", Sources: []string{"dns"}},
+			},
+		},
+		{
+			name:    "broken json lines are skipped",
+			input:   "{\"host\":\"a.example.com\"}\n{not json\n",
+			format:  FormatSamoscout,
+			want:    FormatSamoscout,
+			records: []Record{{Host: "a.example.com"}},
+			skipped: 1,
+		},
+		{
+			name: "samoscout artifact lines are ignored",
+			input: `{"host":"a.example.com","input":"example.com","source":"crtsh"}
+{"type":"url","value":"https://a.example.com/login","host":"a.example.com","input":"example.com","source":"wayback"}
+{"type":"ip","value":"192.0.2.1","host":"a.example.com","input":"example.com","source":"shodan"}
+{"type":"related_domain","value":"example.org","host":"example.org","input":"example.com","source":"whois"}`,
+			format: FormatSamoscout,
+			want:   FormatSamoscout,
+			records: []Record{
+				{Host: "a.example.com", Domain: "example.com", Sources: []string{"crtsh"}},
+			},
+		},
+		{
+			name:   "urls and wildcards are normalized",
+			input:  "https://a.example.com:8443/login\n*.b.example.com\n",
+			format: FormatList,
+			want:   FormatList,
+			records: []Record{
+				{Host: "a.example.com"},
+				{Host: "b.example.com"},
+			},
+		},
+		{
+			name:   "empty input",
+			input:  "",
+			format: FormatAuto,
+			want:   FormatList,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if result.Format != tt.want {
+				t.Errorf("Format = %q, want %q", result.Format, tt.want)
+			}
+			if !reflect.DeepEqual(result.Records, tt.records) {
+				t.Errorf("Records = %+v, want %+v", result.Records, tt.records)
+			}
+			if result.Skipped != tt.skipped {
+				t.Errorf("Skipped = %d, want %d", result.Skipped, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	if _, err := Parse(strings.NewReader("a.example.com"), "csv"); err == nil {
+		t.Fatal("Parse() with an unknown format succeeded")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"a.example.com", FormatList},
+		{`{"host":"a.example.com"}`, FormatSubfinder},
+		{`{"name":"a.example.com"}`, FormatAmass},
+		{"{broken", FormatList},
+	}
+
+	for _, tt := range tests {
+		if got := detectFormat(tt.line); got != tt.want {
+			t.Errorf("detectFormat(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
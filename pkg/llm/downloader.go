@@ -1,12 +1,15 @@
 package llm
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/samogod/samoscout/pkg/config"
 )
@@ -18,39 +21,71 @@ const (
 	ConfigFile      = "config.json"
 )
 
+// ModelFiles are the files that make up the model, all of them needed for
+// inference.
+var ModelFiles = []string{ModelFile, TokenizerFile, ConfigFile}
+
+// Checksums pins the SHA-256 of each model file by file name. Files without a
+// digest are not verified.
+type Checksums map[string]string
+
 type Downloader struct {
-	cacheDir string
-	client   *http.Client
+	cacheDir  string
+	localPath string
+	checksums Checksums
+	client    *http.Client
 }
 
-func NewDownloader() *Downloader {
+// localPath points to a directory that already holds the model files; when set
+// nothing is fetched from the network.
+func NewDownloader(localPath string, checksums Checksums) *Downloader {
 	cacheDir := config.GetLLMCacheDir()
-	
+
+	pinned := make(Checksums, len(checksums))
+	for name, sum := range checksums {
+		if sum = strings.ToLower(strings.TrimSpace(sum)); sum != "" {
+			pinned[name] = sum
+		}
+	}
+
 	return &Downloader{
-		cacheDir: cacheDir,
-		client:   &http.Client{},
+		cacheDir:  cacheDir,
+		localPath: strings.TrimSpace(localPath),
+		checksums: pinned,
+		client:    &http.Client{},
 	}
 }
 
+func (d *Downloader) CacheDir() string {
+	return d.cacheDir
+}
+
 func (d *Downloader) DownloadModel(forceDownload bool) (string, string, error) {
+	if d.localPath != "" && !forceDownload {
+		return d.useLocalModel()
+	}
+
 	if err := os.MkdirAll(d.cacheDir, 0755); err != nil {
 		return "", "", fmt.Errorf("failed to create cache directory: %w", err)
 	}
-	
+
 	modelPath := filepath.Join(d.cacheDir, ModelFile)
 	tokenizerPath := filepath.Join(d.cacheDir, TokenizerFile)
 	configPath := filepath.Join(d.cacheDir, ConfigFile)
-	
+
 	if !forceDownload {
 		if fileExists(modelPath) && fileExists(tokenizerPath) && fileExists(configPath) {
+			if err := d.VerifyModel(d.cacheDir); err != nil {
+				return "", "", fmt.Errorf("%w (run 'samoscout llm pull --force' to re-download)", err)
+			}
 			return modelPath, tokenizerPath, nil
 		}
 	}
-	
+
 	baseURL := fmt.Sprintf("https://huggingface.co/%s/resolve/main", HuggingFaceRepo)
-	
-	fmt.Println("[LLM] Downloading AI model files (first run, ~100MB)...")
-	
+
+	log.Infof("Downloading AI model files (first run, ~100MB)...")
+
 	files := []struct {
 		name string
 		path string
@@ -60,58 +95,139 @@ func (d *Downloader) DownloadModel(forceDownload bool) (string, string, error) {
 		{TokenizerFile, tokenizerPath, baseURL + "/" + TokenizerFile},
 		{ConfigFile, configPath, baseURL + "/" + ConfigFile},
 	}
-	
+
 	for _, file := range files {
 		if forceDownload || !fileExists(file.path) {
-			fmt.Printf("[LLM]   downloading %s...\n", file.name)
+			log.Infof("  downloading %s...", file.name)
 			if err := d.downloadFile(file.url, file.path); err != nil {
 				return "", "", fmt.Errorf("failed to download %s: %w", file.name, err)
 			}
 		}
 	}
-	
-	fmt.Printf("[LLM] Model cached at %s\n", d.cacheDir)
-	
+
+	for _, file := range files {
+		if err := d.verifyFile(file.name, file.path); err != nil {
+			os.Remove(file.path)
+			return "", "", err
+		}
+	}
+
+	log.Infof("Model cached at %s", d.cacheDir)
+
+	return modelPath, tokenizerPath, nil
+}
+
+func (d *Downloader) useLocalModel() (string, string, error) {
+	modelPath := filepath.Join(d.localPath, ModelFile)
+	tokenizerPath := filepath.Join(d.localPath, TokenizerFile)
+	configPath := filepath.Join(d.localPath, ConfigFile)
+
+	for _, path := range []string{modelPath, tokenizerPath, configPath} {
+		if !fileExists(path) {
+			return "", "", fmt.Errorf("local model file not found: %s", path)
+		}
+	}
+
+	if err := d.VerifyModel(d.localPath); err != nil {
+		return "", "", err
+	}
+
 	return modelPath, tokenizerPath, nil
 }
 
+// VerifyModel checks every model file in dir that has a pinned digest.
+func (d *Downloader) VerifyModel(dir string) error {
+	for _, name := range ModelFiles {
+		if err := d.verifyFile(name, filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Downloader) verifyFile(name, path string) error {
+	expected := d.checksums[name]
+	if expected == "" {
+		return nil
+	}
+
+	sum, err := FileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+
+	if sum != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", path, expected, sum)
+	}
+
+	return nil
+}
+
 func (d *Downloader) downloadFile(url, dest string) error {
 	resp, err := d.client.Get(url)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 	}
-	
-	out, err := os.Create(dest)
+
+	tmp := dest + ".part"
+	out, err := os.Create(tmp)
 	if err != nil {
 		return err
 	}
-	defer out.Close()
-	
-	_, err = io.Copy(out, resp.Body)
-	return err
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, dest)
 }
 
 func (d *Downloader) LoadConfig(configPath string) (*ModelConfig, error) {
+	return LoadModelConfig(configPath)
+}
+
+func LoadModelConfig(configPath string) (*ModelConfig, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	var config ModelConfig
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, err
 	}
-	
+
 	return &config, nil
 }
 
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
-
@@ -8,8 +8,11 @@ import (
 	"strings"
 
 	"github.com/samogod/samoscout/pkg/active"
+	"github.com/samogod/samoscout/pkg/logging"
 )
 
+var log = logging.Component("llm")
+
 type LLM struct {
 	model     *Model
 	validator *Validator
@@ -22,7 +25,11 @@ func New(cfg *Config) (*LLM, error) {
 		return nil, fmt.Errorf("puredns setup failed: %w", err)
 	}
 
-	downloader := NewDownloader()
+	downloader := NewDownloader(cfg.ModelPath, Checksums{
+		ModelFile:     cfg.ModelSHA256,
+		TokenizerFile: cfg.TokenizerSHA256,
+		ConfigFile:    cfg.ConfigSHA256,
+	})
 	modelPath, tokenizerPath, err := downloader.DownloadModel(false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download model: %w", err)
@@ -74,18 +81,12 @@ func (l *LLM) Enumerate(
 	allPredictions := make(map[string]bool)
 	currentDomains := inputDomains
 
-	fmt.Printf("[LLM] Starting predictions with %d seed domains\n", len(currentDomains))
-	if l.config.Verbose {
-		fmt.Printf("[DBG] [LLM] Max recursion: %d, Predictions per iteration: %d\n",
-			l.config.MaxRecursion, l.config.NumPredictions)
-	}
+	log.Infof("Starting predictions with %d seed domains", len(currentDomains))
+	log.Debugf("Max recursion: %d, Predictions per iteration: %d",
+		l.config.MaxRecursion, l.config.NumPredictions)
 
 	for i := 0; i < l.config.MaxRecursion; i++ {
-		if !l.config.Verbose {
-			fmt.Printf("[LLM] Iteration %d/%d ", i+1, l.config.MaxRecursion)
-		} else {
-			fmt.Printf("[DBG] [LLM] === Iteration %d/%d ===\n", i+1, l.config.MaxRecursion)
-		}
+		log.Infof("Iteration %d/%d", i+1, l.config.MaxRecursion)
 
 		select {
 		case <-ctx.Done():
@@ -100,16 +101,22 @@ func (l *LLM) Enumerate(
 			return nil, err
 		}
 
-		if l.config.Verbose {
-			fmt.Printf("[DBG] [LLM] Generated %d predictions\n", len(predictions))
+		log.Debugf("Generated %d predictions", len(predictions))
+
+		if l.config.InScope != nil {
+			var inScope []string
+			for _, p := range predictions {
+				if l.config.InScope(p) {
+					inScope = append(inScope, p)
+				} else {
+					blockedDomains[strings.ToLower(p)] = true
+				}
+			}
+			predictions = inScope
 		}
 
 		if len(predictions) == 0 {
-			if !l.config.Verbose {
-				fmt.Println("- no new predictions")
-			} else {
-				fmt.Println("[DBG] [LLM] No new predictions, stopping")
-			}
+			log.Infof("No new predictions, stopping")
 			break
 		}
 
@@ -119,11 +126,7 @@ func (l *LLM) Enumerate(
 		}
 
 		if len(resolved) == 0 {
-			if !l.config.Verbose {
-				fmt.Println()
-			} else {
-				fmt.Println("[DBG] [LLM] No resolved domains, stopping")
-			}
+			log.Infof("No resolved domains, stopping")
 			break
 		}
 
@@ -142,10 +145,8 @@ func (l *LLM) Enumerate(
 		result = append(result, d)
 	}
 
-	fmt.Printf("[LLM] Prediction complete: %d total new subdomains discovered\n", len(result))
-	if l.config.Verbose {
-		fmt.Printf("[DBG] [LLM] Across %d iterations\n", l.config.MaxRecursion)
-	}
+	log.Infof("Prediction complete: %d total new subdomains discovered", len(result))
+	log.Debugf("Across %d iterations", l.config.MaxRecursion)
 
 	return result, nil
 }
@@ -200,10 +201,8 @@ func (l *LLM) runInference(
 		}
 	}
 
-	if l.config.Verbose {
-		fmt.Printf("[DBG] [LLM] Processing %d unique subdomains, %d blocked\n",
-			len(subs), len(blockedList))
-	}
+	log.Debugf("Processing %d unique subdomains, %d blocked",
+		len(subs), len(blockedList))
 
 	predictions, err := l.model.GenerateDomains(
 		ctx,
@@ -12,9 +12,12 @@ sys.path.insert(0, os.path.dirname(os.path.abspath(__file__)))
 from gpt_model import GPT
 
 class LLMInference:
-    def __init__(self):
-        model_path = hf_hub_download(repo_id=MODEL_REPO, filename='model.pt')
-        tokenizer_path = hf_hub_download(repo_id=MODEL_REPO, filename='tokenizer.json')
+    def __init__(self, model_path=None, tokenizer_path=None):
+        # samoscout passes the verified files it manages; the hub is only a fallback
+        if not model_path:
+            model_path = hf_hub_download(repo_id=MODEL_REPO, filename='model.pt')
+        if not tokenizer_path:
+            tokenizer_path = hf_hub_download(repo_id=MODEL_REPO, filename='tokenizer.json')
         
         self.model = GPT.from_checkpoint(model_path, device='cpu', tokenizer_path=tokenizer_path)
         self.model.eval()
@@ -57,7 +60,10 @@ if __name__ == "__main__":
         input_json = sys.stdin.read()
         input_data = json.loads(input_json)
         
-        llm = LLMInference()
+        llm = LLMInference(
+            model_path=input_data.get("model_path"),
+            tokenizer_path=input_data.get("tokenizer_path"),
+        )
         predictions = llm.predict(
             subdomains=input_data.get("subdomains", []),
             apex=input_data.get("apex", ""),
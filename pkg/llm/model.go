@@ -18,8 +18,10 @@ import (
 var pythonScripts embed.FS
 
 type Model struct {
-	scriptPath string
-	config     *ModelConfig
+	scriptPath    string
+	modelPath     string
+	tokenizerPath string
+	config        *ModelConfig
 }
 
 func extractPythonScripts() (string, error) {
@@ -49,8 +51,7 @@ func extractPythonScripts() (string, error) {
 
 func LoadModel(modelPath, tokenizerPath string, device string) (*Model, error) {
 	configPath := filepath.Join(filepath.Dir(modelPath), ConfigFile)
-	downloader := NewDownloader()
-	config, err := downloader.LoadConfig(configPath)
+	config, err := LoadModelConfig(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load model config: %w", err)
 	}
@@ -61,8 +62,10 @@ func LoadModel(modelPath, tokenizerPath string, device string) (*Model, error) {
 	}
 
 	return &Model{
-		scriptPath: scriptPath,
-		config:     config,
+		scriptPath:    scriptPath,
+		modelPath:     modelPath,
+		tokenizerPath: tokenizerPath,
+		config:        config,
 	}, nil
 }
 
@@ -71,6 +74,8 @@ func (m *Model) Close() error {
 }
 
 type InferenceRequest struct {
+	ModelPath      string   `json:"model_path"`
+	TokenizerPath  string   `json:"tokenizer_path"`
 	Subdomains     []string `json:"subdomains"`
 	Apex           string   `json:"apex"`
 	NumPredictions int      `json:"num_predictions"`
@@ -102,6 +107,8 @@ func (m *Model) GenerateDomains(
 ) ([]string, error) {
 
 	req := InferenceRequest{
+		ModelPath:      m.modelPath,
+		TokenizerPath:  m.tokenizerPath,
 		Subdomains:     subdomains,
 		Apex:           apex,
 		NumPredictions: numPredictions,
@@ -118,6 +125,8 @@ func (m *Model) GenerateDomains(
 	pythonCmd := getPythonCommand()
 	cmd := exec.CommandContext(ctx, pythonCmd, m.scriptPath)
 	cmd.Stdin = strings.NewReader(string(reqJSON))
+	// model files are already on disk, never let huggingface_hub reach the network
+	cmd.Env = append(os.Environ(), "HF_HUB_OFFLINE=1", "TRANSFORMERS_OFFLINE=1")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, fmt.Errorf("failed to run inference with '%s': %w\nOutput: %s\nHint: Ensure Python 3.7+ is installed and in PATH", pythonCmd, err, string(output))
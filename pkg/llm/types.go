@@ -7,8 +7,13 @@ type Config struct {
 	Temperature       float32
 	ResolutionThreads int
 	Device            string
+	ModelPath         string
+	ModelSHA256       string
+	TokenizerSHA256   string
+	ConfigSHA256      string
 	OutputDir         string
 	Verbose           bool
+	InScope           func(host string) bool
 }
 
 type ModelConfig struct {
@@ -3,6 +3,8 @@ package llm
 import (
 	"regexp"
 	"strings"
+
+	"github.com/samogod/samoscout/pkg/domainutil"
 )
 
 var (
@@ -48,26 +50,14 @@ func (v *Validator) IsValidSubdomainStart(s string) bool {
 	return validSubdomainStartRe.MatchString(s)
 }
 
+// ExtractSubdomain returns the labels of fullDomain left of apex. An empty
+// apex stands for the registrable domain of fullDomain.
 func (v *Validator) ExtractSubdomain(fullDomain, apex string) (string, bool) {
-	fullDomain = strings.ToLower(strings.TrimSpace(fullDomain))
-	apex = strings.ToLower(strings.TrimSpace(apex))
-	
-	if fullDomain == "" || apex == "" {
-		return "", false
-	}
-	
-	fullDomain = strings.TrimPrefix(fullDomain, "*.")
-	
-	if fullDomain == apex {
-		return "", true
+	subdomain, ok := domainutil.Subdomain(fullDomain, apex)
+	if !ok || subdomain == "" {
+		return "", ok
 	}
 	
-	if !strings.HasSuffix(fullDomain, "."+apex) {
-		return "", false
-	}
-	
-	subdomain := strings.TrimSuffix(fullDomain, "."+apex)
-	
 	if !v.IsValidSubdomain(subdomain) {
 		return "", false
 	}
@@ -87,7 +77,7 @@ func (v *Validator) NormalizeSubdomains(domains []string, apex string) []string
 		
 		normalized = strings.TrimPrefix(normalized, "*.")
 		
-		if !seen[normalized] && strings.HasSuffix(normalized, apex) {
+		if _, ok := domainutil.Subdomain(normalized, apex); ok && !seen[normalized] {
 			seen[normalized] = true
 			result = append(result, normalized)
 		}
@@ -0,0 +1,172 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Options selects where and how every component logs. Level is debug, info,
+// warn or error; Format is text or json; an empty File means stderr.
+type Options struct {
+	Level  string
+	Format string
+	File   string
+}
+
+var (
+	base = newBase()
+
+	mu   sync.Mutex
+	file *os.File
+)
+
+func newBase() *logrus.Logger {
+	l := logrus.New()
+	l.SetOutput(os.Stderr)
+	l.SetLevel(logrus.InfoLevel)
+	l.SetFormatter(&textFormatter{})
+	return l
+}
+
+// Configure applies options to every logger, including the ones components
+// created at init time.
+func Configure(options Options) error {
+	level := logrus.InfoLevel
+	if options.Level != "" {
+		parsed, err := logrus.ParseLevel(options.Level)
+		if err != nil {
+			return fmt.Errorf("invalid log level %q: expected debug, info, warn or error", options.Level)
+		}
+		level = parsed
+	}
+
+	var formatter logrus.Formatter
+	switch strings.ToLower(options.Format) {
+	case "", "text":
+		formatter = &textFormatter{}
+	case "json":
+		formatter = &logrus.JSONFormatter{}
+	default:
+		return fmt.Errorf("invalid log format %q: expected text or json", options.Format)
+	}
+
+	var out io.Writer = os.Stderr
+	var opened *os.File
+	if options.File != "" {
+		f, err := os.OpenFile(options.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		out, opened = f, f
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if file != nil {
+		file.Close()
+	}
+	file = opened
+
+	base.SetLevel(level)
+	base.SetFormatter(formatter)
+	base.SetOutput(out)
+	return nil
+}
+
+// Logger writes messages of one component. Loggers are cheap to derive with
+// With, for example to tag a scan's messages with its domain.
+type Logger struct {
+	entry *logrus.Entry
+}
+
+func Component(name string) *Logger {
+	return &Logger{entry: base.WithField("component", name)}
+}
+
+func (l *Logger) With(key string, value interface{}) *Logger {
+	return &Logger{entry: l.entry.WithField(key, value)}
+}
+
+// DebugEnabled reports whether debug messages are written, for callers that
+// need to know beyond a single message, such as passing -v to a subprocess.
+func (l *Logger) DebugEnabled() bool {
+	return l.entry.Logger.IsLevelEnabled(logrus.DebugLevel)
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.entry.Debugf(format, args...)
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.entry.Infof(format, args...)
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.entry.Warnf(format, args...)
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.entry.Errorf(format, args...)
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying l.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or fallback.
+func FromContext(ctx context.Context, fallback *Logger) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+	return fallback
+}
+
+// textFormatter keeps the CLI's "[INF] message" lines. Debug lines also name
+// their component and fields.
+type textFormatter struct{}
+
+func (f *textFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	var b bytes.Buffer
+
+	switch entry.Level {
+	case logrus.DebugLevel, logrus.TraceLevel:
+		b.WriteString("[DBG] ")
+		if component, ok := entry.Data["component"]; ok {
+			fmt.Fprintf(&b, "[%v] ", component)
+		}
+	case logrus.InfoLevel:
+		b.WriteString("[INF] ")
+	case logrus.WarnLevel:
+		b.WriteString("[WARN] ")
+	default:
+		b.WriteString("[ERR] ")
+	}
+	b.WriteString(entry.Message)
+
+	if entry.Level >= logrus.DebugLevel {
+		keys := make([]string, 0, len(entry.Data))
+		for k := range entry.Data {
+			if k != "component" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, " %s=%v", k, entry.Data[k])
+		}
+	}
+
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
@@ -0,0 +1,300 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/logging"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var log = logging.Component("metrics")
+
+const namespace = "samoscout"
+
+// durationBuckets span a quick API call to a multi-hour active phase.
+var durationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800, 3600, 10800}
+
+var (
+	registry = prometheus.NewRegistry()
+
+	sourceRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_requests_total",
+		Help:      "HTTP requests made by passive sources, by status code (\"error\" when no response was received).",
+	}, []string{"source", "code"})
+
+	sourceRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "source_request_duration_seconds",
+		Help:      "Latency of HTTP requests made by passive sources.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"source"})
+
+	sourceRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_request_errors_total",
+		Help:      "Failed HTTP requests of passive sources, by error class.",
+	}, []string{"source", "class"})
+
+	sourceRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_runs_total",
+		Help:      "Passive source runs.",
+	}, []string{"source"})
+
+	sourceRunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "source_run_duration_seconds",
+		Help:      "Time a passive source took to return all its results.",
+		Buckets:   durationBuckets,
+	}, []string{"source"})
+
+	sourceResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_results_total",
+		Help:      "Results returned by passive sources, before deduplication.",
+	}, []string{"source"})
+
+	sourceErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_errors_total",
+		Help:      "Errors reported by passive sources, by error class.",
+	}, []string{"source", "class"})
+
+	phaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "phase_duration_seconds",
+		Help:      "Duration of scan phases.",
+		Buckets:   durationBuckets,
+	}, []string{"phase"})
+
+	scans = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scans_total",
+		Help:      "Finished scans, by outcome.",
+	}, []string{"status"})
+
+	resolverQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "resolver_queries_total",
+		Help:      "Resolved hosts by resolver and outcome (resolved, not_found, error).",
+	}, []string{"resolver", "outcome"})
+
+	resolverDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "resolver_query_duration_seconds",
+		Help:      "Latency of lookups made with the system resolver.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"resolver"})
+
+	dbWriteDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_write_duration_seconds",
+		Help:      "Latency of tracking database writes, by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	dbWriteErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_write_errors_total",
+		Help:      "Failed tracking database writes, by operation.",
+	}, []string{"operation"})
+
+	esDocuments = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "elasticsearch_documents_total",
+		Help:      "Documents sent to Elasticsearch bulk calls, by kind and result (indexed, failed).",
+	}, []string{"kind", "result"})
+
+	esBulkErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "elasticsearch_bulk_errors_total",
+		Help:      "Elasticsearch bulk calls that failed as a whole, by kind.",
+	}, []string{"kind"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		sourceRequests, sourceRequestDuration, sourceRequestErrors,
+		sourceRuns, sourceRunDuration, sourceResults, sourceErrors,
+		phaseDuration, scans,
+		resolverQueries, resolverDuration,
+		dbWriteDuration, dbWriteErrors,
+		esDocuments, esBulkErrors,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Serve exposes Handler on /metrics of addr until ctx is cancelled. It is
+// used by the long-running commands that have no API of their own.
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Debugf("metrics server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// statusPattern finds the status code in the errors sources build from
+// non-200 responses, e.g. "HTTP error: 429" or "unexpected status code: 503".
+var statusPattern = regexp.MustCompile(`(?i)(?:http error|status)[^0-9]{0,12}([1-5][0-9]{2})\b`)
+
+// ErrorClass sorts an error into a small set of classes so that error
+// counters keep a bounded number of series.
+func ErrorClass(err error) string {
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &netErr):
+		return "network"
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return "decode"
+	}
+
+	if m := statusPattern.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		if class := statusClass(code); class != "" {
+			return class
+		}
+	}
+	return "other"
+}
+
+// statusClass is the error class of an HTTP response, or "" for success.
+func statusClass(code int) string {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return "rate_limited"
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return "unauthorized"
+	case code >= 500:
+		return "server_error"
+	case code >= 400:
+		return "client_error"
+	default:
+		return ""
+	}
+}
+
+type sourceTransport struct {
+	source string
+	next   http.RoundTripper
+}
+
+// InstrumentTransport counts the requests made through next as requests of
+// source.
+func InstrumentTransport(source string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &sourceTransport{source: source, next: next}
+}
+
+func (t *sourceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	sourceRequestDuration.WithLabelValues(t.source).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		sourceRequests.WithLabelValues(t.source, "error").Inc()
+		sourceRequestErrors.WithLabelValues(t.source, ErrorClass(err)).Inc()
+		return resp, err
+	}
+
+	sourceRequests.WithLabelValues(t.source, strconv.Itoa(resp.StatusCode)).Inc()
+	if class := statusClass(resp.StatusCode); class != "" {
+		sourceRequestErrors.WithLabelValues(t.source, class).Inc()
+	}
+	return resp, nil
+}
+
+// SourceError counts one error result of a source.
+func SourceError(source string, err error) {
+	sourceErrors.WithLabelValues(source, ErrorClass(err)).Inc()
+}
+
+// SourceRun records a finished source run, from the numbers of its SourceStat.
+func SourceRun(source string, duration time.Duration, results int) {
+	sourceRuns.WithLabelValues(source).Inc()
+	sourceRunDuration.WithLabelValues(source).Observe(duration.Seconds())
+	sourceResults.WithLabelValues(source).Add(float64(results))
+}
+
+// Phase records how long a scan phase took since start.
+func Phase(phase string, start time.Time) {
+	phaseDuration.WithLabelValues(phase).Observe(time.Since(start).Seconds())
+}
+
+func Scan(success bool) {
+	status := "success"
+	if !success {
+		status = "failure"
+	}
+	scans.WithLabelValues(status).Inc()
+}
+
+// Resolution counts n hosts resolved by resolver with the given outcome.
+func Resolution(resolver, outcome string, n int) {
+	if n > 0 {
+		resolverQueries.WithLabelValues(resolver, outcome).Add(float64(n))
+	}
+}
+
+// Lookup records the latency of one lookup made since start.
+func Lookup(resolver string, start time.Time) {
+	resolverDuration.WithLabelValues(resolver).Observe(time.Since(start).Seconds())
+}
+
+// DBWrite records a tracking database write made since start.
+func DBWrite(operation string, start time.Time, err error) {
+	dbWriteDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		dbWriteErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// ElasticBulk records the documents of a completed bulk call.
+func ElasticBulk(kind string, indexed, failed int) {
+	esDocuments.WithLabelValues(kind, "indexed").Add(float64(indexed))
+	esDocuments.WithLabelValues(kind, "failed").Add(float64(failed))
+}
+
+// ElasticBulkError counts a bulk call that failed as a whole.
+func ElasticBulkError(kind string) {
+	esBulkErrors.WithLabelValues(kind).Inc()
+}
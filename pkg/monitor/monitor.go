@@ -0,0 +1,265 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/logging"
+	"github.com/samogod/samoscout/pkg/orchestrator"
+)
+
+var log = logging.Component("monitor")
+
+// maxSleep bounds how long the scheduler sleeps, so clock jumps and
+// suspended hosts are noticed.
+const maxSleep = 5 * time.Minute
+
+// Entry is one schedule of the watchlist with its run history.
+type Entry struct {
+	Domain   string
+	Schedule Schedule
+	Last     database.MonitorRun
+	Next     time.Time
+}
+
+func (e Entry) key() string {
+	return e.Domain + "\x00" + e.Schedule.Name
+}
+
+// Report describes a finished scheduled scan.
+type Report struct {
+	Domain    string
+	Schedule  string
+	StartedAt time.Time
+	Duration  time.Duration
+	Result    *orchestrator.ScanResult
+	Err       error
+}
+
+// Monitor runs the schedules of a watchlist through the orchestrator. Each
+// schedule is due one interval after its last run as recorded in the
+// database; schedules missed while the monitor was down are due at once and
+// run once, not once per missed interval. Two scans of the same domain never
+// overlap.
+type Monitor struct {
+	orch        *orchestrator.Orchestrator
+	db          *database.DB
+	watchlist   *Watchlist
+	concurrency int
+
+	OnStart func(entry Entry)
+	OnRun   func(report Report)
+
+	mu      sync.Mutex
+	busy    map[string]bool
+	running int
+	lastRun map[string]time.Time
+	wg      sync.WaitGroup
+	done    chan struct{}
+}
+
+func New(orch *orchestrator.Orchestrator, watchlist *Watchlist, concurrency int) *Monitor {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &Monitor{
+		orch:        orch,
+		db:          orch.GetDB(),
+		watchlist:   watchlist,
+		concurrency: concurrency,
+		busy:        make(map[string]bool),
+		lastRun:     make(map[string]time.Time),
+		done:        make(chan struct{}, concurrency),
+	}
+}
+
+// Entries lists every schedule with its last run and next due time, the
+// most overdue first.
+func (m *Monitor) Entries() ([]Entry, error) {
+	runs, err := m.db.QueryMonitorRuns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load monitor runs: %w", err)
+	}
+
+	last := make(map[string]database.MonitorRun)
+	for _, r := range runs {
+		last[r.Domain+"\x00"+r.Schedule] = r
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var entries []Entry
+	for _, d := range m.watchlist.Domains {
+		for _, s := range d.Schedules {
+			e := Entry{Domain: d.Domain, Schedule: s}
+			e.Last = last[e.key()]
+
+			// a run whose history could not be stored still counts
+			if t, ok := m.lastRun[e.key()]; ok && t.After(e.Last.LastRunAt) {
+				e.Last.LastRunAt = t
+			}
+			if !e.Last.LastRunAt.IsZero() {
+				e.Next = e.Last.LastRunAt.Add(s.Interval())
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Next.Before(entries[j].Next)
+	})
+
+	return entries, nil
+}
+
+// Run schedules scans until ctx is cancelled, then waits for the running
+// scans to finish.
+func (m *Monitor) Run(ctx context.Context) error {
+	defer m.wg.Wait()
+
+	for {
+		wait, err := m.startDue(ctx)
+		if err != nil {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-m.done:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// RunOnce runs every schedule that is due now and waits for them.
+func (m *Monitor) RunOnce(ctx context.Context) error {
+	for {
+		if _, err := m.startDue(ctx); err != nil {
+			m.wg.Wait()
+			return err
+		}
+
+		m.mu.Lock()
+		idle := m.running == 0
+		m.mu.Unlock()
+		if idle {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			m.wg.Wait()
+			return nil
+		case <-m.done:
+		}
+	}
+}
+
+// startDue starts as many due schedules as the concurrency allows and returns
+// how long to sleep until the next one is due.
+func (m *Monitor) startDue(ctx context.Context) (time.Duration, error) {
+	if ctx.Err() != nil {
+		return 0, nil
+	}
+
+	entries, err := m.Entries()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	wait := maxSleep
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range entries {
+		if m.busy[e.Domain] {
+			continue
+		}
+		if e.Next.After(now) {
+			if d := e.Next.Sub(now); d < wait {
+				wait = d
+			}
+			continue
+		}
+		if m.running >= m.concurrency {
+			break
+		}
+
+		m.busy[e.Domain] = true
+		m.running++
+		m.wg.Add(1)
+		go m.run(e)
+	}
+
+	return wait, nil
+}
+
+func (m *Monitor) run(e Entry) {
+	defer m.wg.Done()
+
+	if m.OnStart != nil {
+		m.OnStart(e)
+	}
+	log.Debugf("running schedule %s of %s", e.Schedule.Name, e.Domain)
+
+	started := time.Now()
+	result, err := m.orch.RunScan(e.Schedule.ScanOptions(e.Domain))
+	if err == nil && !result.Success {
+		err = fmt.Errorf("%s", joinErrors(result.Errors))
+	}
+
+	run := database.MonitorRun{Domain: e.Domain, Schedule: e.Schedule.Name, LastRunAt: started}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	if result != nil && result.Track != nil {
+		run.ScanID = result.Track.ScanID
+	}
+
+	recordErr := m.db.RecordMonitorRun(run)
+	if recordErr != nil {
+		log.Debugf("failed to record run of %s/%s: %v", e.Domain, e.Schedule.Name, recordErr)
+	}
+
+	m.mu.Lock()
+	m.lastRun[e.key()] = started
+	delete(m.busy, e.Domain)
+	m.running--
+	m.mu.Unlock()
+
+	if m.OnRun != nil {
+		m.OnRun(Report{
+			Domain:    e.Domain,
+			Schedule:  e.Schedule.Name,
+			StartedAt: started,
+			Duration:  time.Since(started),
+			Result:    result,
+			Err:       err,
+		})
+	}
+
+	select {
+	case m.done <- struct{}{}:
+	default:
+	}
+}
+
+func joinErrors(errs []error) string {
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
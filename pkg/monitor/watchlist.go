@@ -0,0 +1,176 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/orchestrator"
+	"github.com/samogod/samoscout/pkg/scope"
+
+	"gopkg.in/yaml.v3"
+)
+
+// minInterval keeps a typo such as "6m" for "6h" from hammering the sources.
+const minInterval = 10 * time.Minute
+
+// Watchlist is the file read by "samoscout monitor":
+//
+//	domains:
+//	  - domain: example.com
+//	    scope: scopes/example.yaml
+//	    schedules:
+//	      - name: passive
+//	        every: 6h
+//	      - name: active
+//	        every: 7d
+//	        active: true
+//	        httpx: true
+type Watchlist struct {
+	Domains []WatchedDomain `yaml:"domains"`
+}
+
+// WatchedDomain.Scope is the scope file of every schedule that does not set
+// its own.
+type WatchedDomain struct {
+	Domain    string     `yaml:"domain"`
+	Scope     string     `yaml:"scope"`
+	Schedules []Schedule `yaml:"schedules"`
+}
+
+// Schedule is one recurring scan of a domain. Name identifies it in the run
+// history, so renaming a schedule makes it due at once.
+type Schedule struct {
+	Name           string `yaml:"name"`
+	Every          string `yaml:"every"`
+	Sources        string `yaml:"sources"`
+	ExcludeSources string `yaml:"exclude_sources"`
+	Active         bool   `yaml:"active"`
+	DeepEnum       bool   `yaml:"deep_enum"`
+	LLM            bool   `yaml:"llm"`
+	Httpx          bool   `yaml:"httpx"`
+	Wordlist       string `yaml:"wordlist"`
+	Scope          string `yaml:"scope"`
+
+	interval time.Duration
+}
+
+func (s Schedule) Interval() time.Duration {
+	return s.interval
+}
+
+func (s Schedule) ScanOptions(domain string) orchestrator.ScanOptions {
+	return orchestrator.ScanOptions{
+		Domain:         domain,
+		Sources:        s.Sources,
+		ExcludeSources: s.ExcludeSources,
+		ActiveEnum:     s.Active,
+		DeepEnum:       s.DeepEnum,
+		LLMEnum:        s.LLM,
+		HttpxProbe:     s.Httpx,
+		WordlistPath:   s.Wordlist,
+		ScopeFile:      s.Scope,
+	}
+}
+
+func LoadWatchlist(path string) (*Watchlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watchlist: %w", err)
+	}
+
+	var w Watchlist
+	if err := yaml.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("failed to parse watchlist: %w", err)
+	}
+
+	if err := w.validate(); err != nil {
+		return nil, fmt.Errorf("invalid watchlist %s: %w", path, err)
+	}
+
+	return &w, nil
+}
+
+func (w *Watchlist) validate() error {
+	if len(w.Domains) == 0 {
+		return fmt.Errorf("no domains")
+	}
+
+	seen := make(map[string]bool)
+	for i := range w.Domains {
+		d := &w.Domains[i]
+		d.Domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(d.Domain)), ".")
+		if d.Domain == "" {
+			return fmt.Errorf("domain #%d has no domain", i+1)
+		}
+		if seen[d.Domain] {
+			return fmt.Errorf("%s is listed twice", d.Domain)
+		}
+		seen[d.Domain] = true
+
+		if len(d.Schedules) == 0 {
+			return fmt.Errorf("%s has no schedules", d.Domain)
+		}
+
+		names := make(map[string]bool)
+		for j := range d.Schedules {
+			s := &d.Schedules[j]
+
+			interval, err := ParseInterval(s.Every)
+			if err != nil {
+				return fmt.Errorf("%s: %w", d.Domain, err)
+			}
+			if interval < minInterval {
+				return fmt.Errorf("%s: interval %s is shorter than %s", d.Domain, s.Every, minInterval)
+			}
+			s.interval = interval
+
+			if s.Name == "" {
+				s.Name = s.Every
+			}
+			if s.Scope == "" {
+				s.Scope = d.Scope
+			}
+			if s.Scope != "" {
+				if _, err := scope.Load(s.Scope); err != nil {
+					return fmt.Errorf("%s: %w", d.Domain, err)
+				}
+			}
+			if names[s.Name] {
+				return fmt.Errorf("%s: schedule %q is defined twice", d.Domain, s.Name)
+			}
+			names[s.Name] = true
+		}
+	}
+
+	return nil
+}
+
+// ParseInterval accepts time.ParseDuration units plus "d" and "w".
+func ParseInterval(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("schedule has no interval (every)")
+	}
+
+	unit := value[len(value)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid interval %q", value)
+		}
+		days := n
+		if unit == 'w' {
+			days = n * 7
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid interval %q", value)
+	}
+	return d, nil
+}
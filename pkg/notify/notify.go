@@ -0,0 +1,273 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/logging"
+	"github.com/samogod/samoscout/pkg/sink"
+)
+
+var log = logging.Component("notify")
+
+// DefaultEvents are sent when a webhook does not list its own.
+var DefaultEvents = []string{database.ChangeNew, database.ChangeRevived, database.ChangeHTTP}
+
+// Message is what a webhook template renders: the changes of one domain in
+// one scan, filtered by the webhook's events and capped at max_hosts.
+type Message struct {
+	Domain    string                `json:"domain"`
+	ScanID    int64                 `json:"scan_id"`
+	Time      time.Time             `json:"time"`
+	Counts    map[string]int        `json:"counts"`
+	Changes   []database.HostChange `json:"changes"`
+	Truncated int                   `json:"truncated,omitempty"`
+}
+
+type target struct {
+	name     string
+	cfg      config.Webhook
+	events   map[string]bool
+	tmpl     *template.Template
+	client   *http.Client
+	interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// Notifier posts the changes found by tracking to the configured webhooks. It
+// is registered as a sink after the database sink and reacts to
+// scan_finished events that carry a tracking summary.
+type Notifier struct {
+	maxHosts int
+	targets  []*target
+}
+
+func New(cfg config.Notifications) (*Notifier, error) {
+	n := &Notifier{maxHosts: cfg.MaxHosts}
+	if n.maxHosts <= 0 {
+		n.maxHosts = 50
+	}
+
+	for i, w := range cfg.Webhooks {
+		name := w.Name
+		if name == "" {
+			name = fmt.Sprintf("webhook-%d", i+1)
+		}
+		if w.URL == "" {
+			return nil, fmt.Errorf("notification webhook %s has no url", name)
+		}
+
+		source := w.Template
+		if source == "" {
+			format := strings.ToLower(w.Format)
+			if format == "" {
+				format = "generic"
+			}
+			var ok bool
+			if source, ok = formats[format]; !ok {
+				return nil, fmt.Errorf("notification webhook %s: unknown format %q (valid: slack, discord, teams, generic)", name, w.Format)
+			}
+		}
+
+		tmpl, err := template.New(name).Funcs(templateFuncs).Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("notification webhook %s: invalid template: %w", name, err)
+		}
+
+		events := w.Events
+		if len(events) == 0 {
+			events = DefaultEvents
+		}
+		eventSet := make(map[string]bool)
+		for _, e := range events {
+			eventSet[strings.ToLower(e)] = true
+		}
+
+		rate := w.RateLimit
+		if rate <= 0 {
+			rate = 1
+		}
+		timeout := time.Duration(w.Timeout) * time.Second
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+
+		n.targets = append(n.targets, &target{
+			name:     name,
+			cfg:      w,
+			events:   eventSet,
+			tmpl:     tmpl,
+			client:   &http.Client{Timeout: timeout},
+			interval: time.Duration(float64(time.Second) / rate),
+		})
+	}
+
+	return n, nil
+}
+
+func (n *Notifier) Name() string {
+	return "notify"
+}
+
+func (n *Notifier) Handle(ctx context.Context, event *sink.Event) error {
+	if event.Type != sink.EventScanFinished || event.Scan.Track == nil {
+		return nil
+	}
+	return n.Notify(ctx, event.Domain, event.Scan.Track)
+}
+
+func (n *Notifier) Close() error {
+	return nil
+}
+
+// Notify sends one message per matching webhook. A failing webhook is retried
+// on its own and does not keep the others from being notified.
+func (n *Notifier) Notify(ctx context.Context, domain string, summary *database.TrackSummary) error {
+	if len(summary.Changes) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, t := range n.targets {
+		if !matchDomain(t.cfg.Domains, domain) {
+			continue
+		}
+
+		msg := n.message(t, domain, summary)
+		if len(msg.Changes) == 0 && msg.Truncated == 0 {
+			continue
+		}
+
+		if err := t.send(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", t.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) message(t *target, domain string, summary *database.TrackSummary) Message {
+	msg := Message{
+		Domain: domain,
+		ScanID: summary.ScanID,
+		Time:   time.Now(),
+		Counts: make(map[string]int),
+	}
+
+	for _, c := range summary.Changes {
+		if !t.events[c.Kind] {
+			continue
+		}
+		msg.Counts[c.Kind]++
+		if len(msg.Changes) < n.maxHosts {
+			msg.Changes = append(msg.Changes, c)
+		} else {
+			msg.Truncated++
+		}
+	}
+
+	return msg
+}
+
+func (t *target) send(ctx context.Context, msg Message) error {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, msg); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return fmt.Errorf("template did not produce valid JSON")
+	}
+
+	retries := t.cfg.Retries
+	if retries < 0 {
+		retries = 0
+	}
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			log.Debugf("retrying notification to %s (attempt %d): %v", t.name, attempt+1, err)
+			select {
+			case <-time.After(time.Duration(attempt) * 2 * time.Second):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err = t.post(ctx, buf.Bytes()); err == nil {
+			log.Debugf("sent %d change(s) for %s to %s", len(msg.Changes), msg.Domain, t.name)
+			return nil
+		}
+	}
+	return err
+}
+
+// post sends one payload, waiting first if the webhook's rate limit requires it.
+func (t *target) post(ctx context.Context, payload []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if wait := t.interval - time.Since(t.last); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	t.last = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.cfg.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// matchDomain reports whether domain is routed to a webhook with the given
+// patterns: no patterns or "*" match everything, "*.example.com" matches
+// subdomains of example.com and anything else must match exactly.
+func matchDomain(patterns []string, domain string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	domain = strings.ToLower(domain)
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		switch {
+		case p == "*" || p == domain:
+			return true
+		case strings.HasPrefix(p, "*.") && strings.HasSuffix(domain, p[1:]):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/samogod/samoscout/pkg/database"
+)
+
+// formats are the built-in payload templates. Custom templates get the same
+// Message and functions.
+var formats = map[string]string{
+	"generic": `{{json .}}`,
+	"slack":   `{"text": {{json (text .)}}}`,
+	"discord": `{"content": {{json (truncate (text .) 2000)}}}`,
+	"teams":   `{"@type": "MessageCard", "@context": "https://schema.org/extensions", "summary": {{json (title .)}}, "title": {{json (title .)}}, "text": {{json (text .)}}}`,
+}
+
+var templateFuncs = template.FuncMap{
+	"json":     toJSON,
+	"text":     messageText,
+	"title":    messageTitle,
+	"truncate": truncate,
+	"join":     strings.Join,
+}
+
+func toJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// truncate cuts s to max characters; Discord counts characters, not bytes.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+func messageTitle(m Message) string {
+	var parts []string
+	for _, kind := range []string{database.ChangeNew, database.ChangeRevived, database.ChangeDied, database.ChangeHTTP} {
+		if m.Counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", m.Counts[kind], strings.ReplaceAll(kind, "_", " ")))
+		}
+	}
+	return fmt.Sprintf("samoscout: %s (scan #%d): %s", m.Domain, m.ScanID, strings.Join(parts, ", "))
+}
+
+// messageText renders a plain text body, one line per change.
+func messageText(m Message) string {
+	var b strings.Builder
+	b.WriteString(messageTitle(m))
+
+	for _, c := range m.Changes {
+		b.WriteString("\n")
+		switch c.Kind {
+		case database.ChangeNew:
+			fmt.Fprintf(&b, "NEW %s", c.Subdomain)
+		case database.ChangeRevived:
+			fmt.Fprintf(&b, "REVIVED %s", c.Subdomain)
+		case database.ChangeDied:
+			fmt.Fprintf(&b, "DEAD %s", c.Subdomain)
+		case database.ChangeHTTP:
+			fmt.Fprintf(&b, "HTTP %s %d -> %d", c.Subdomain, c.OldHTTPStatus, c.NewHTTPStatus)
+			if c.OldHTTPTitle != c.NewHTTPTitle {
+				fmt.Fprintf(&b, " (%q -> %q)", c.OldHTTPTitle, c.NewHTTPTitle)
+			}
+		}
+		if len(c.Sources) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(c.Sources, ","))
+		}
+	}
+
+	if m.Truncated > 0 {
+		fmt.Fprintf(&b, "\n...and %d more", m.Truncated)
+	}
+
+	return b.String()
+}
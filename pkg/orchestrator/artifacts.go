@@ -0,0 +1,89 @@
+package orchestrator
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/samogod/samoscout/pkg/domainutil"
+	"github.com/samogod/samoscout/pkg/sink"
+	"github.com/samogod/samoscout/pkg/sources"
+)
+
+// normalizeResult brings a source result into the form it is stored and
+// compared in. Hostnames are normalized in Value; IPs, URLs and related
+// domains are checked, and the hostname they belong to is normalized in Host.
+func normalizeResult(result *sources.Result) error {
+	switch result.Type {
+	case "ip":
+		ip := net.ParseIP(strings.TrimSpace(result.Value))
+		if ip == nil {
+			return fmt.Errorf("invalid IP address %q", result.Value)
+		}
+		result.Value = ip.String()
+
+	case "url":
+		u, err := url.Parse(strings.TrimSpace(result.Value))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid URL %q", result.Value)
+		}
+		u.Host = strings.ToLower(u.Host)
+		result.Value = u.String()
+		if result.Host == "" {
+			result.Host = u.Host
+		}
+
+	case "related_domain":
+		domain, err := domainutil.NormalizeHostname(result.Value)
+		if err != nil {
+			return err
+		}
+		result.Value = domain
+
+	default:
+		host, err := domainutil.NormalizeHostname(result.Value)
+		if err != nil {
+			return err
+		}
+		result.Value = host
+		return nil
+	}
+
+	host, err := domainutil.NormalizeHostname(result.Host)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", result.Type, result.Value, err)
+	}
+	result.Host = host
+	return nil
+}
+
+// isArtifact reports whether a result carries a value besides a hostname.
+func isArtifact(result sources.Result) bool {
+	return result.Type == "ip" || result.Type == "url" || result.Type == "related_domain"
+}
+
+// artifactSet collects the artifacts of a scan, each once with every source
+// that reported it.
+type artifactSet struct {
+	index map[string]int
+	list  []sink.Artifact
+}
+
+func newArtifactSet() *artifactSet {
+	return &artifactSet{index: make(map[string]int)}
+}
+
+// add records an artifact and reports whether it is new.
+func (s *artifactSet) add(result sources.Result) (sink.Artifact, bool) {
+	key := result.Type + " " + result.Host + " " + result.Value
+	if i, ok := s.index[key]; ok {
+		s.list[i].Sources = appendSource(s.list[i].Sources, result.Source)
+		return s.list[i], false
+	}
+
+	artifact := sink.Artifact{Type: result.Type, Value: result.Value, Host: result.Host, Sources: []string{result.Source}}
+	s.index[key] = len(s.list)
+	s.list = append(s.list, artifact)
+	return artifact, true
+}
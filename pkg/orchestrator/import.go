@@ -0,0 +1,141 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/importer"
+)
+
+type ImportOptions struct {
+	File   string `json:"import"`
+	Format string `json:"format"`
+	Tool   string `json:"tool"`
+	Domain string `json:"domain,omitempty"`
+	Scope  string `json:"scope,omitempty"`
+}
+
+type ImportResult struct {
+	Domain     string
+	Hosts      int
+	OutOfScope int
+	Track      *database.TrackSummary
+}
+
+// ImportRecords merges hosts found by another tool into the tracking database.
+// Records are grouped by the target domain (options.Domain, or the domain the
+// tool recorded), scope-checked and tracked through the normal scan logic;
+// hosts missing from an import are never marked dead.
+func (o *Orchestrator) ImportRecords(records []importer.Record, options ImportOptions) ([]ImportResult, int, error) {
+	if o.db == nil || !o.db.IsEnabled() {
+		return nil, 0, fmt.Errorf("database is not enabled")
+	}
+
+	scope, err := o.loadScope(options.Scope)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	type batch struct {
+		hosts      []string
+		sources    map[string][]string
+		used       []string
+		outOfScope int
+	}
+
+	batches := make(map[string]*batch)
+	unassigned := 0
+
+	for _, record := range records {
+		domain := options.Domain
+		if domain == "" {
+			domain = record.Domain
+		}
+		if domain == "" {
+			unassigned++
+			continue
+		}
+
+		b, ok := batches[domain]
+		if !ok {
+			b = &batch{sources: make(map[string][]string)}
+			batches[domain] = b
+		}
+
+		if ok, reason := scope.Host(record.Host, domain); !ok {
+			b.outOfScope++
+			log.Debugf("skipping out-of-scope host %s for %s: %s", record.Host, domain, reason)
+			continue
+		}
+
+		if _, seen := b.sources[record.Host]; !seen {
+			b.hosts = append(b.hosts, record.Host)
+			b.sources[record.Host] = nil
+		}
+
+		for _, source := range importSources(options.Tool, record.Sources) {
+			b.sources[record.Host] = appendSource(b.sources[record.Host], source)
+			b.used = appendSource(b.used, source)
+		}
+	}
+
+	domains := make([]string, 0, len(batches))
+	for domain := range batches {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	var results []ImportResult
+	for _, domain := range domains {
+		b := batches[domain]
+		result := ImportResult{Domain: domain, Hosts: len(b.hosts), OutOfScope: b.outOfScope}
+
+		if len(b.hosts) > 0 {
+			optionsJSON, _ := json.Marshal(options)
+			now := time.Now()
+			scanInfo := &database.ScanInfo{
+				StartedAt:        now,
+				EndedAt:          now,
+				Options:          string(optionsJSON),
+				Sources:          b.used,
+				SubdomainSources: b.sources,
+				Imported:         true,
+				Tool:             options.Tool,
+			}
+
+			summary, err := o.db.TrackSubdomains(domain, b.hosts, scanInfo)
+			if err != nil {
+				return results, unassigned, fmt.Errorf("failed to import subdomains for %s: %w", domain, err)
+			}
+			result.Track = summary
+		}
+
+		results = append(results, result)
+	}
+
+	return results, unassigned, nil
+}
+
+// importSources labels each source with the tool that reported it, so
+// "crtsh" from subfinder is kept apart from samoscout's own crtsh source.
+func importSources(tool string, sources []string) []string {
+	if tool == "" || tool == "samoscout" {
+		if len(sources) == 0 {
+			return []string{"import"}
+		}
+		return sources
+	}
+
+	if len(sources) == 0 {
+		return []string{tool}
+	}
+
+	labeled := make([]string, 0, len(sources))
+	for _, source := range sources {
+		labeled = append(labeled, tool+":"+source)
+	}
+	return labeled
+}
@@ -13,38 +13,37 @@ import (
 	"github.com/samogod/samoscout/pkg/active"
 	"github.com/samogod/samoscout/pkg/config"
 	"github.com/samogod/samoscout/pkg/database"
-    "github.com/samogod/samoscout/pkg/elastic"
+	"github.com/samogod/samoscout/pkg/domainutil"
 	"github.com/samogod/samoscout/pkg/llm"
+	"github.com/samogod/samoscout/pkg/logging"
+	"github.com/samogod/samoscout/pkg/metrics"
+	"github.com/samogod/samoscout/pkg/scope"
 	"github.com/samogod/samoscout/pkg/session"
+	"github.com/samogod/samoscout/pkg/sink"
 	"github.com/samogod/samoscout/pkg/sources"
+	"github.com/samogod/samoscout/pkg/tracing"
 
-	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
-var DebugLog func(string, ...interface{})
-
-func belongsToTargetDomain(hostname, targetDomain string) bool {
-	hostname = strings.TrimSpace(strings.ToLower(hostname))
-	targetDomain = strings.TrimSpace(strings.ToLower(targetDomain))
-
-	if hostname == targetDomain {
-		return true
-	}
-
-	return strings.HasSuffix(hostname, "."+targetDomain)
-}
+var log = logging.Component("orchestrator")
 
 type Orchestrator struct {
 	config        *config.Config
 	configManager *config.Manager
-	logger        *logrus.Logger
+	logger        *logging.Logger
 	db            *database.DB
+	sinks         *sink.Dispatcher
+	scope         *scope.Scope
+
+	shutdownTracing func(context.Context) error
 }
 
 type Engine struct {
 	Sources []sources.Source
 	Session *session.Session
-	Logger  *logrus.Logger
+	Logger  *logging.Logger
 }
 
 type ScanOptions struct {
@@ -58,6 +57,7 @@ type ScanOptions struct {
 	LLMEnum        bool
 	HttpxProbe     bool
 	WordlistPath   string
+	ScopeFile      string
 }
 
 type SourceStat struct {
@@ -78,33 +78,19 @@ type ScanResult struct {
 	Errors            []error
 	Subdomains        []string
 	SubdomainSources  map[string]string
+	AllSources        map[string][]string
+	SourcesUsed       []string
 	SourceStats       []SourceStat
 	ActiveWebServices []string
-}
-
-type customFormatter struct{}
-
-func (f *customFormatter) Format(entry *logrus.Entry) ([]byte, error) {
-	var levelText string
-	switch entry.Level {
-	case logrus.InfoLevel:
-		levelText = "[INF]"
-	case logrus.WarnLevel:
-		levelText = "[WARN]"
-	case logrus.ErrorLevel:
-		levelText = "[ERR]"
-	case logrus.DebugLevel:
-		levelText = "[DBG]"
-	default:
-		levelText = "[???]"
-	}
-	return []byte(fmt.Sprintf("%s %s\n", levelText, entry.Message)), nil
+	WildcardZones     []string
+	Artifacts         []sink.Artifact
+	SinkErrors        []error
+	OutOfScope        []scope.Drop
+	Track             *database.TrackSummary
 }
 
 func NewOrchestrator(configPath string) (*Orchestrator, error) {
-	logger := logrus.New()
-	logger.SetLevel(logrus.InfoLevel)
-	logger.SetFormatter(&customFormatter{})
+	logger := log
 
 	configManager := config.NewManager(configPath)
 	if err := configManager.LoadConfig(); err != nil {
@@ -118,15 +104,54 @@ func NewOrchestrator(configPath string) (*Orchestrator, error) {
 		logger.Warnf("Database initialization failed: %v", err)
 	}
 
-	return &Orchestrator{
-		config:        cfg,
-		configManager: configManager,
-		logger:        logger,
-		db:            db,
-	}, nil
+	if err := loadPublicSuffixList(cfg.DefaultSettings.PSL); err != nil {
+		return nil, err
+	}
+
+	var defaultScope *scope.Scope
+	if cfg.DefaultSettings.Scope != "" {
+		defaultScope, err = scope.Load(cfg.DefaultSettings.Scope)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	shutdownTracing, err := tracing.Setup(cfg.Tracing)
+	if err != nil {
+		logger.Warnf("Tracing disabled: %v", err)
+	}
+
+	o := &Orchestrator{
+		config:          cfg,
+		configManager:   configManager,
+		logger:          logger,
+		db:              db,
+		scope:           defaultScope,
+		shutdownTracing: shutdownTracing,
+	}
+	o.setupSinks()
+
+	return o, nil
+}
+
+// loadPublicSuffixList replaces the embedded list with default_settings.psl,
+// or else with the copy downloaded by "samoscout psl update".
+func loadPublicSuffixList(path string) error {
+	if path == "" {
+		path = config.GetPSLCachePath()
+		if _, err := os.Stat(path); err != nil {
+			return nil
+		}
+	}
+
+	if err := domainutil.LoadList(path); err != nil {
+		return err
+	}
+	log.Debugf("public suffix list: %s", path)
+	return nil
 }
 
-func NewEngine(s *session.Session, logger *logrus.Logger, selectedSources string, excludedSources string) *Engine {
+func NewEngine(s *session.Session, logger *logging.Logger, selectedSources string, excludedSources string) *Engine {
 	allSources := []string{
 		"crtsh", "alienvault", "anubis", "abuseipdb", "cebaidu", "commoncrawl", "digicert",
 		"digitorus", "dnsgrep", "hackertarget", "hudsonrock", "myssl", "netcraft",
@@ -141,7 +166,7 @@ func NewEngine(s *session.Session, logger *logrus.Logger, selectedSources string
 	}
 
 	if selectedSources != "" && excludedSources != "" {
-		logger.Warn("Both -s (sources) and --exclude-sources flags specified. Using -s (sources) and ignoring exclusions.")
+		logger.Warnf("Both -s (sources) and --exclude-sources flags specified. Using -s (sources) and ignoring exclusions.")
 		excludedSources = ""
 	}
 
@@ -173,7 +198,7 @@ func NewEngine(s *session.Session, logger *logrus.Logger, selectedSources string
 			}
 		}
 		if len(enabledSources) == 0 {
-			logger.Warn("No valid sources specified, using all sources")
+			logger.Warnf("No valid sources specified, using all sources")
 			for _, source := range allSources {
 				enabledSources[source] = true
 			}
@@ -185,7 +210,7 @@ func NewEngine(s *session.Session, logger *logrus.Logger, selectedSources string
 			}
 		}
 		if len(enabledSources) == 0 {
-			logger.Warn("All sources excluded, using all sources instead")
+			logger.Warnf("All sources excluded, using all sources instead")
 			for _, source := range allSources {
 				enabledSources[source] = true
 			}
@@ -478,11 +503,31 @@ func (e *Engine) RunPassiveEnumeration(ctx context.Context, domain string, colle
 			resultCount := 0
 			errorCount := 0
 
-			agentResults := s.Run(ctx, domain, e.Session)
+			sourceCtx, span := tracing.Start(ctx, "source "+sourceName, attribute.String("samoscout.source", sourceName))
+			defer func() {
+				span.SetAttributes(
+					attribute.Int("samoscout.results", resultCount),
+					attribute.Int("samoscout.errors", errorCount),
+				)
+				span.End()
+			}()
+
+			sourceLog := logging.FromContext(ctx, e.Logger).With("source", sourceName)
+			agentResults := s.Run(sourceCtx, domain, e.Session.ForSource(sourceName))
 
 			for result := range agentResults {
 				if result.Error != nil {
 					errorCount++
+					metrics.SourceError(sourceName, result.Error)
+					span.RecordError(result.Error)
+					sourceLog.Debugf("source error: %v", result.Error)
+					continue
+				}
+
+				// sources report raw values, every host is normalized here so
+				// that the same name from two sources dedupes
+				if err := normalizeResult(&result); err != nil {
+					sourceLog.Debugf("dropped result: %v", err)
 					continue
 				}
 				resultCount++
@@ -494,8 +539,11 @@ func (e *Engine) RunPassiveEnumeration(ctx context.Context, domain string, colle
 				}
 			}
 
+			duration := time.Since(startTime)
+			metrics.SourceRun(sourceName, duration, resultCount)
+			sourceLog.Debugf("source finished in %s: %d results, %d errors", duration.Round(time.Millisecond), resultCount, errorCount)
+
 			if collectStats {
-				duration := time.Since(startTime)
 				statsMutex.Lock()
 				stats[sourceName] = &SourceStat{
 					Name:     sourceName,
@@ -530,60 +578,155 @@ func (o *Orchestrator) RunScan(options ScanOptions) (*ScanResult, error) {
 		Errors:    []error{},
 	}
 
-	if err := o.runPassiveReconWithEngine(options.Domain, result, options.JSONFormat, options.Stats, options.Sources, options.ExcludeSources); err != nil {
+	scanScope, err := o.loadScope(options.ScopeFile)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(context.Background(), "scan",
+		attribute.String("samoscout.domain", options.Domain),
+		attribute.Bool("samoscout.active", options.ActiveEnum || o.config.ActiveEnumeration.Enabled),
+		attribute.Bool("samoscout.llm", options.LLMEnum || o.config.LLMEnumeration.Enabled),
+		attribute.Bool("samoscout.httpx", options.HttpxProbe),
+	)
+	defer span.End()
+	logger := o.logger.With("domain", options.Domain)
+	ctx = logging.NewContext(ctx, logger)
+	sc := o.newScanScope(scanScope, options.Domain, result, logger)
+
+	// stats are always collected, the DEAD marking policy needs per-source error counts
+	phaseCtx, endPhase := startPhase(ctx, "passive", result)
+	err = o.runPassiveReconWithEngine(phaseCtx, sc, true, options.Sources, options.ExcludeSources)
+	if err != nil {
 		result.Errors = append(result.Errors, fmt.Errorf("passive reconnaissance failed: %w", err))
 	}
+	endPhase(err)
+
+	// hosts outside the CIDR rules must not seed the active and LLM phases
+	if scanScope.HasNetworks() {
+		_, endPhase = startPhase(ctx, "resolve", result)
+		sc.resolve()
+		endPhase(nil)
+	}
 
 	if (options.LLMEnum || o.config.LLMEnumeration.Enabled) && o.config.LLMEnumeration.RunAfterPassive {
-		if err := o.runLLMEnumeration(options.Domain, result, options.JSONFormat); err != nil {
+		_, endPhase = startPhase(ctx, "llm", result)
+		err := o.runLLMEnumeration(sc)
+		if err != nil {
 			result.Errors = append(result.Errors, fmt.Errorf("LLM enumeration failed: %w", err))
-			o.logger.Errorf("LLM enumeration error: %v", err)
+			logger.Errorf("LLM enumeration error: %v", err)
 		}
+		endPhase(err)
 	}
 
 	if options.ActiveEnum || o.config.ActiveEnumeration.Enabled {
-		if err := o.runActiveEnumeration(options.Domain, result, options.JSONFormat, options.DeepEnum, options.WordlistPath); err != nil {
+		_, endPhase = startPhase(ctx, "active", result)
+		err := o.runActiveEnumeration(sc, options.DeepEnum, options.WordlistPath)
+		if err != nil {
 			result.Errors = append(result.Errors, fmt.Errorf("active enumeration failed: %w", err))
-			o.logger.Errorf("Active enumeration error: %v", err)
+			logger.Errorf("Active enumeration error: %v", err)
 		}
+		endPhase(err)
 	}
 
 	if (options.LLMEnum || o.config.LLMEnumeration.Enabled) && o.config.LLMEnumeration.RunAfterActive {
-		if err := o.runLLMEnumeration(options.Domain, result, options.JSONFormat); err != nil {
+		_, endPhase = startPhase(ctx, "llm", result)
+		err := o.runLLMEnumeration(sc)
+		if err != nil {
 			result.Errors = append(result.Errors, fmt.Errorf("LLM enumeration failed: %w", err))
-			o.logger.Errorf("LLM enumeration error: %v", err)
+			logger.Errorf("LLM enumeration error: %v", err)
 		}
+		endPhase(err)
 	}
 
+	if scanScope.HasNetworks() {
+		_, endPhase = startPhase(ctx, "resolve", result)
+		sc.resolve()
+		endPhase(nil)
+	}
+	sc.report()
+
 	endTime := time.Now()
 	result.EndTime = endTime
 	result.Duration = endTime.Sub(startTime)
 	result.Success = len(result.Errors) == 0 || result.TotalSubdomains > 0
 
 	if options.HttpxProbe && len(result.Subdomains) > 0 {
-		if err := o.runHTTPProbing(options.Domain, result); err != nil {
-			if DebugLog != nil {
-				DebugLog("HTTP probing failed: %v", err)
-			}
+		_, endPhase = startPhase(ctx, "httpx", result)
+		err := o.runHTTPProbing(options.Domain, result)
+		if err != nil {
+			log.Debugf("HTTP probing failed: %v", err)
 		}
+		endPhase(err)
 	}
 
-	if o.db != nil && o.db.IsEnabled() {
-		if err := o.db.TrackSubdomains(options.Domain, result.Subdomains); err != nil {
-			o.logger.Warnf("Failed to track subdomains in database: %v", err)
+	optionsJSON, _ := json.Marshal(options)
+	sourceErrors := 0
+	var failedSources []string
+	for _, stat := range result.SourceStats {
+		sourceErrors += stat.Errors
+		if stat.Errors > 0 {
+			failedSources = append(failedSources, stat.Name)
 		}
 	}
+	scanEvent := &sink.ScanEvent{
+		StartedAt:        result.StartTime,
+		EndedAt:          result.EndTime,
+		Subdomains:       result.Subdomains,
+		SubdomainSources: result.SubdomainSources,
+		AllSources:       result.AllSources,
+		SourcesUsed:      result.SourcesUsed,
+		WildcardZones:    result.WildcardZones,
+		Artifacts:        result.Artifacts,
+		Options:          string(optionsJSON),
+		Partial:          options.Sources != "" || options.ExcludeSources != "",
+		SourceErrors:     sourceErrors,
+		FailedSources:    failedSources,
+		Resolve:          resolvingHosts,
+	}
+	phaseCtx, endPhase = startPhase(ctx, "track", result)
+	o.sinks.Emit(phaseCtx, &sink.Event{
+		Type:   sink.EventScanFinished,
+		Domain: options.Domain,
+		Scan:   scanEvent,
+	})
+	endPhase(nil)
+	result.Track = scanEvent.Track
+
+	// a scan whose results did not reach the index is not a success, even
+	// though the enumeration itself went fine
+	if sinkErrors := scanEvent.Errors(); len(sinkErrors) > 0 {
+		result.SinkErrors = sinkErrors
+		result.Errors = append(result.Errors, sinkErrors...)
+		result.Success = false
+	}
+
+	metrics.Scan(result.Success)
+	span.SetAttributes(
+		attribute.Int("samoscout.subdomains", result.TotalSubdomains),
+		attribute.Bool("samoscout.success", result.Success),
+	)
+	if !result.Success {
+		span.SetStatus(codes.Error, "scan failed")
+	}
 
 	return result, nil
 }
 
-type SubdomainResult struct {
-	Host   string `json:"host"`
-	Input  string `json:"input"`
-	Source string `json:"source"`
+// startPhase opens the span of a scan phase. The returned function ends it,
+// recording the phase duration and the subdomain count reached by then.
+func startPhase(ctx context.Context, phase string, result *ScanResult) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, phase)
+	return ctx, func(err error) {
+		metrics.Phase(phase, start)
+		span.SetAttributes(attribute.Int("samoscout.subdomains", result.TotalSubdomains))
+		tracing.End(span, err)
+	}
 }
 
-func (o *Orchestrator) runPassiveReconWithEngine(domain string, result *ScanResult, jsonFormat bool, collectStats bool, sources string, excludeSources string) error {
+func (o *Orchestrator) runPassiveReconWithEngine(ctx context.Context, sc *scanScope, collectStats bool, sources string, excludeSources string) error {
+	domain, result := sc.domain, sc.result
 
 	sess, err := session.New(o.config)
 	if err != nil {
@@ -592,7 +735,7 @@ func (o *Orchestrator) runPassiveReconWithEngine(domain string, result *ScanResu
 
 	engine := NewEngine(sess, o.logger, sources, excludeSources)
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(o.config.DefaultSettings.Timeout)*time.Minute)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(o.config.DefaultSettings.Timeout)*time.Minute)
 	defer cancel()
 
 	passiveResults := engine.RunPassiveEnumeration(ctx, domain, collectStats)
@@ -600,6 +743,10 @@ func (o *Orchestrator) runPassiveReconWithEngine(domain string, result *ScanResu
 	found := make(map[string]struct{})
 	var allSubdomains []string
 	subdomainSources := make(map[string]string)
+	allSources := make(map[string][]string)
+	zones := make(map[string]bool)
+	var wildcardZones []string
+	artifacts := newArtifactSet()
 	var sourceStats []SourceStat
 
 	for enumResult := range passiveResults {
@@ -610,43 +757,83 @@ func (o *Orchestrator) runPassiveReconWithEngine(domain string, result *ScanResu
 			continue
 		}
 
+		// a related domain is outside the scanned one by definition, it is
+		// kept as data rather than checked against the scope
+		if enumResult.Result.Type == "related_domain" {
+			if artifact, ok := artifacts.add(enumResult.Result); ok {
+				sc.emitArtifact(artifact)
+			}
+			continue
+		}
+
 		subdomain := enumResult.Result.Value
+		if isArtifact(enumResult.Result) {
+			subdomain = enumResult.Result.Host
+		}
 
-		if !belongsToTargetDomain(subdomain, domain) {
+		if !sc.allow(subdomain, "passive") {
 			continue
 		}
 
+		if isArtifact(enumResult.Result) {
+			if artifact, ok := artifacts.add(enumResult.Result); ok {
+				sc.emitArtifact(artifact)
+			}
+		}
+
+		allSources[subdomain] = appendSource(allSources[subdomain], enumResult.Result.Source)
+
+		// a wildcard name was normalized to its zone, which is kept both as a
+		// host and as a wildcard zone
+		if enumResult.Result.Type == "wildcard" && !zones[subdomain] {
+			zones[subdomain] = true
+			wildcardZones = append(wildcardZones, subdomain)
+			log.Debugf("found wildcard zone: *.%s [%s]", subdomain, enumResult.Result.Source)
+		}
+
 		if _, ok := found[subdomain]; !ok {
 			found[subdomain] = struct{}{}
 			allSubdomains = append(allSubdomains, subdomain)
 			subdomainSources[subdomain] = enumResult.Result.Source
 
-			if DebugLog != nil {
-				DebugLog("found subdomain: %s [%s]", subdomain, enumResult.Result.Source)
-			}
+			log.Debugf("found subdomain: %s [%s]", subdomain, enumResult.Result.Source)
 
-			if jsonFormat {
-				jsonResult := SubdomainResult{
-					Host:   subdomain,
-					Input:  domain,
-					Source: enumResult.Result.Source,
-				}
-				jsonBytes, _ := json.Marshal(jsonResult)
-				fmt.Println(string(jsonBytes))
-			} else {
-				fmt.Println(subdomain)
-			}
+			sc.emit(subdomain, enumResult.Result.Source, "passive")
 		}
 	}
 
 	result.TotalSubdomains = len(allSubdomains)
 	result.Subdomains = allSubdomains
 	result.SubdomainSources = subdomainSources
+	result.AllSources = allSources
 	result.SourceStats = sourceStats
+	result.WildcardZones = wildcardZones
+	result.Artifacts = artifacts.list
+
+	for _, source := range engine.Sources {
+		result.SourcesUsed = append(result.SourcesUsed, source.Name())
+	}
 
 	return nil
 }
 
+func resolvingHosts(hosts []string) map[string]bool {
+	alive := make(map[string]bool)
+	for host := range active.LookupHosts(hosts, 50, 5*time.Second) {
+		alive[host] = true
+	}
+	return alive
+}
+
+func appendSource(sources []string, source string) []string {
+	for _, s := range sources {
+		if s == source {
+			return sources
+		}
+	}
+	return append(sources, source)
+}
+
 func (o *Orchestrator) GetConfig() *config.Config {
 	return o.config
 }
@@ -655,17 +842,14 @@ func (o *Orchestrator) GetDB() *database.DB {
 	return o.db
 }
 
-func (o *Orchestrator) runActiveEnumeration(domain string, result *ScanResult, jsonFormat bool, deepEnum bool, wordlistPath string) error {
+func (o *Orchestrator) runActiveEnumeration(sc *scanScope, deepEnum bool, wordlistPath string) error {
+	domain, result := sc.domain, sc.result
 	if len(result.Subdomains) == 0 {
-		if DebugLog != nil {
-			DebugLog("no passive subdomains found, skipping active enumeration")
-		}
+		log.Debugf("no passive subdomains found, skipping active enumeration")
 		return nil
 	}
 
-	if DebugLog != nil {
-		DebugLog("starting active enumeration with %d passive subdomains", len(result.Subdomains))
-	}
+	log.Debugf("starting active enumeration with %d passive subdomains", len(result.Subdomains))
 
 	pipelineConfig := active.PipelineConfig{
 		Domain:             domain,
@@ -673,9 +857,11 @@ func (o *Orchestrator) runActiveEnumeration(domain string, result *ScanResult, j
 		OutputDir:          o.config.ActiveEnumeration.OutputDir,
 		DsieveTop:          o.config.ActiveEnumeration.DsieveTop,
 		DsieveFactor:       o.config.ActiveEnumeration.DsieveFactor,
-		Verbose:            DebugLog != nil,
+		Verbose:            log.DebugEnabled(),
 		DeepEnum:           deepEnum,
 		CustomWordlistPath: wordlistPath,
+		InScope:            sc.candidate,
+		WildcardZones:      result.WildcardZones,
 	}
 
 	pipelineResult, err := active.RunActivePipeline(pipelineConfig)
@@ -683,7 +869,12 @@ func (o *Orchestrator) runActiveEnumeration(domain string, result *ScanResult, j
 		return fmt.Errorf("active pipeline failed: %w", err)
 	}
 
-	activeSubdomains := pipelineResult.ActiveSubdomains
+	var activeSubdomains []string
+	for _, subdomain := range pipelineResult.ActiveSubdomains {
+		if sc.allow(subdomain, "active") {
+			activeSubdomains = append(activeSubdomains, subdomain)
+		}
+	}
 
 	allSubdomains := active.MergeAndDeduplicate(
 		result.Subdomains,
@@ -700,25 +891,14 @@ func (o *Orchestrator) runActiveEnumeration(domain string, result *ScanResult, j
 	}
 
 	for _, subdomain := range activeSubdomains {
+		result.AllSources[subdomain] = appendSource(result.AllSources[subdomain], "active")
 		if !passiveSet[strings.ToLower(subdomain)] {
-			if jsonFormat {
-				jsonResult := SubdomainResult{
-					Host:   subdomain,
-					Input:  domain,
-					Source: "active",
-				}
-				jsonBytes, _ := json.Marshal(jsonResult)
-				fmt.Println(string(jsonBytes))
-			} else {
-				fmt.Println(subdomain)
-			}
+			sc.emit(subdomain, "active", "active")
 		}
 	}
 
-	if DebugLog != nil {
-		DebugLog("active enumeration completed: found %d new subdomains in %v",
-			pipelineResult.TotalNewSubdomains, pipelineResult.Duration)
-	}
+	log.Debugf("active enumeration completed: found %d new subdomains in %v",
+		pipelineResult.TotalNewSubdomains, pipelineResult.Duration)
 
 	return nil
 }
@@ -731,22 +911,15 @@ func (o *Orchestrator) writeSubdomainsToFile(filePath string, subdomains []strin
 	return os.WriteFile(filePath, []byte(content), 0644)
 }
 
-func (o *Orchestrator) runLLMEnumeration(
-	domain string,
-	result *ScanResult,
-	jsonFormat bool,
-) error {
+func (o *Orchestrator) runLLMEnumeration(sc *scanScope) error {
+	domain, result := sc.domain, sc.result
 
 	if len(result.Subdomains) == 0 {
-		if DebugLog != nil {
-			DebugLog("no subdomains to seed LLM, skipping")
-		}
+		log.Debugf("no subdomains to seed LLM, skipping")
 		return nil
 	}
 
-	if DebugLog != nil {
-		DebugLog("starting LLM enumeration with %d seed domains", len(result.Subdomains))
-	}
+	log.Debugf("starting LLM enumeration with %d seed domains", len(result.Subdomains))
 
 	domainDir := filepath.Join(o.config.ActiveEnumeration.OutputDir, domain)
 	if err := os.MkdirAll(domainDir, 0755); err != nil {
@@ -760,8 +933,13 @@ func (o *Orchestrator) runLLMEnumeration(
 		Temperature:       o.config.LLMEnumeration.Temperature,
 		ResolutionThreads: 150,
 		Device:            o.config.LLMEnumeration.Device,
+		ModelPath:         o.config.LLMEnumeration.ModelPath,
+		ModelSHA256:       o.config.LLMEnumeration.ModelSHA256,
+		TokenizerSHA256:   o.config.LLMEnumeration.TokenizerSHA256,
+		ConfigSHA256:      o.config.LLMEnumeration.ConfigSHA256,
 		OutputDir:         domainDir,
-		Verbose:           DebugLog != nil,
+		Verbose:           log.DebugEnabled(),
+		InScope:           sc.candidate,
 	}
 
 	llmEngine, err := llm.New(llmConfig)
@@ -788,22 +966,13 @@ func (o *Orchestrator) runLLMEnumeration(
 
 	newCount := 0
 	for _, pred := range predictions {
-		if !passiveSet[strings.ToLower(pred)] {
+		if !passiveSet[strings.ToLower(pred)] && sc.allow(pred, "llm") {
 			result.Subdomains = append(result.Subdomains, pred)
 			result.SubdomainSources[pred] = "llm"
+			result.AllSources[pred] = appendSource(result.AllSources[pred], "llm")
 			newCount++
 
-			if jsonFormat {
-				jsonResult := SubdomainResult{
-					Host:   pred,
-					Input:  domain,
-					Source: "llm",
-				}
-				jsonBytes, _ := json.Marshal(jsonResult)
-				fmt.Println(string(jsonBytes))
-			} else {
-				fmt.Println(pred)
-			}
+			sc.emit(pred, "llm", "llm")
 		}
 	}
 
@@ -825,9 +994,7 @@ func (o *Orchestrator) runHTTPProbing(domain string, result *ScanResult) error {
 	subdomainsToProbe := result.Subdomains
 
 	if len(result.ActiveWebServices) > 0 {
-		if DebugLog != nil {
-			DebugLog("active enumeration already probed some subdomains, checking for new ones")
-		}
+		log.Debugf("active enumeration already probed some subdomains, checking for new ones")
 
 		alreadyProbed := make(map[string]bool)
 		for _, url := range result.ActiveWebServices {
@@ -845,23 +1012,17 @@ func (o *Orchestrator) runHTTPProbing(domain string, result *ScanResult) error {
 		}
 
 		if len(newSubdomains) == 0 {
-			if DebugLog != nil {
-				DebugLog("all subdomains already probed, skipping HTTP probing")
-			}
+			log.Debugf("all subdomains already probed, skipping HTTP probing")
 			return nil
 		}
 
-		if DebugLog != nil {
-			DebugLog("found %d new subdomains to probe", len(newSubdomains))
-		}
+		log.Debugf("found %d new subdomains to probe", len(newSubdomains))
 		subdomainsToProbe = newSubdomains
 	}
 
-	if DebugLog != nil {
-		DebugLog("running HTTP probing on %d subdomains", len(subdomainsToProbe))
-	}
+	log.Debugf("running HTTP probing on %d subdomains", len(subdomainsToProbe))
 
-    _, activeURLs, err := active.ProbeHTTP(subdomainsToProbe, domainDir, "", DebugLog != nil)
+    probes, activeURLs, err := active.ProbeHTTP(subdomainsToProbe, domainDir, "", log.DebugEnabled())
     if err != nil {
         return fmt.Errorf("HTTP probing failed: %w", err)
     }
@@ -872,43 +1033,13 @@ func (o *Orchestrator) runHTTPProbing(domain string, result *ScanResult) error {
 		result.ActiveWebServices = activeURLs
 	}
 
-    if o.config.Elasticsearch.Enabled {
-        esCfg := o.config.Elasticsearch
-        idx := esCfg.Index
-        if strings.TrimSpace(idx) == "" {
-            idx = "samoscout_httpx"
-        }
-        client, err := elastic.New(elastic.Config{
-            URL:      esCfg.URL,
-            Username: esCfg.Username,
-            Password: esCfg.Password,
-            Index:    idx,
-        })
-        if err == nil {
-            jsonlPath := filepath.Join(domainDir, "httpx_results.json")
-            if _, statErr := os.Stat(jsonlPath); statErr != nil {
-                jsonlPath = ""
-            }
-            if jsonlPath == "" && DebugLog != nil {
-                DebugLog("no httpx JSONL file found for elasticsearch indexing")
-            }
-            if jsonlPath != "" {
-                if err := client.IndexJSONLinesFile(context.Background(), jsonlPath); err != nil {
-                    fmt.Printf("[ES] Indexing failed: %v\n", err)
-                    if DebugLog != nil {
-                        DebugLog("elasticsearch indexing failed: %v", err)
-                    }
-                } else {
-                    fmt.Printf("[ES] Indexed %s into index '%s'\n", filepath.Base(jsonlPath), idx)
-                    if DebugLog != nil {
-                        DebugLog("indexed httpx JSONL to elasticsearch index '%s'", idx)
-                    }
-                }
-            }
-        } else if DebugLog != nil {
-            DebugLog("elasticsearch client init failed: %v", err)
-        }
-    }
+	for _, probe := range probes {
+		o.sinks.Emit(context.Background(), &sink.Event{
+			Type:   sink.EventProbe,
+			Domain: domain,
+			Probe:  &sink.ProbeEvent{HttpxResult: probe},
+		})
+	}
 
 	return nil
 }
@@ -0,0 +1,202 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/active"
+	"github.com/samogod/samoscout/pkg/logging"
+	"github.com/samogod/samoscout/pkg/scope"
+	"github.com/samogod/samoscout/pkg/sink"
+)
+
+// loadScope returns the scope of a scan: its own scope file, or the one of
+// default_settings.scope.
+func (o *Orchestrator) loadScope(path string) (*scope.Scope, error) {
+	if path == "" {
+		return o.scope, nil
+	}
+	return scope.Load(path)
+}
+
+// scanScope applies the scope of one scan. Every host it drops is recorded on
+// the result once, with the phase that dropped it; hosts that are not related
+// to the target at all are only counted. With CIDR rules, found hosts are only
+// emitted to the sinks once their addresses were checked, and each host is
+// resolved and checked once.
+type scanScope struct {
+	o      *Orchestrator
+	scope  *scope.Scope
+	domain string
+	result *ScanResult
+	logger *logging.Logger
+
+	mu         sync.Mutex
+	dropped    map[string]bool
+	checked    map[string]bool
+	unrelated  map[string]bool
+	candidates int
+	pending    []pendingSubdomain
+	artifacts  []sink.Artifact
+}
+
+type pendingSubdomain struct {
+	host, source, phase string
+}
+
+func (o *Orchestrator) newScanScope(s *scope.Scope, domain string, result *ScanResult, logger *logging.Logger) *scanScope {
+	return &scanScope{
+		o:         o,
+		scope:     s,
+		domain:    domain,
+		result:    result,
+		logger:    logger,
+		dropped:   make(map[string]bool),
+		checked:   make(map[string]bool),
+		unrelated: make(map[string]bool),
+	}
+}
+
+// allow reports whether a found host is in scope by name.
+func (sc *scanScope) allow(host, phase string) bool {
+	ok, reason := sc.scope.Host(host, sc.domain)
+	if ok {
+		return true
+	}
+
+	if !sc.scope.Related(host, sc.domain) {
+		sc.mu.Lock()
+		sc.unrelated[host] = true
+		sc.mu.Unlock()
+		return false
+	}
+	sc.drop(host, phase, reason)
+	return false
+}
+
+// candidate reports whether a generated name may be resolved. Rejected
+// candidates are only counted: they were never found to exist.
+func (sc *scanScope) candidate(host string) bool {
+	ok, _ := sc.scope.Host(host, sc.domain)
+	if !ok {
+		sc.mu.Lock()
+		sc.candidates++
+		sc.mu.Unlock()
+	}
+	return ok
+}
+
+func (sc *scanScope) drop(host, phase, reason string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.dropped[host] {
+		return
+	}
+	sc.dropped[host] = true
+	sc.result.OutOfScope = append(sc.result.OutOfScope, scope.Drop{Host: host, Phase: phase, Reason: reason})
+	sc.logger.Debugf("out of scope: %s (%s): %s", host, phase, reason)
+}
+
+func (sc *scanScope) emit(host, source, phase string) {
+	if sc.scope.HasNetworks() {
+		sc.mu.Lock()
+		sc.pending = append(sc.pending, pendingSubdomain{host: host, source: source, phase: phase})
+		sc.mu.Unlock()
+		return
+	}
+	sc.o.emitSubdomain(sc.domain, host, source, phase)
+}
+
+func (sc *scanScope) emitArtifact(artifact sink.Artifact) {
+	if sc.scope.HasNetworks() {
+		sc.mu.Lock()
+		sc.artifacts = append(sc.artifacts, artifact)
+		sc.mu.Unlock()
+		return
+	}
+	sc.o.emitArtifact(sc.domain, artifact)
+}
+
+// resolve drops the hosts found since the last call whose addresses are
+// outside the CIDR rules, then emits the hosts held back until now. It runs
+// after the passive phase, so out-of-scope hosts never seed the active and
+// LLM phases, and again for the hosts those phases found.
+func (sc *scanScope) resolve() {
+	if !sc.scope.HasNetworks() {
+		return
+	}
+
+	result := sc.result
+	var hosts []string
+	for _, host := range result.Subdomains {
+		if !sc.checked[host] {
+			hosts = append(hosts, host)
+		}
+	}
+	addrs := active.LookupHosts(hosts, 50, 5*time.Second)
+
+	kept := result.Subdomains[:0]
+	for _, host := range result.Subdomains {
+		if !sc.checked[host] {
+			sc.checked[host] = true
+			if ok, reason := sc.scope.Addresses(addrs[host]); !ok {
+				sc.drop(host, "resolve", reason)
+				delete(result.SubdomainSources, host)
+				delete(result.AllSources, host)
+				continue
+			}
+		}
+		kept = append(kept, host)
+	}
+	result.Subdomains = kept
+	result.TotalSubdomains = len(kept)
+
+	zones := result.WildcardZones[:0]
+	for _, zone := range result.WildcardZones {
+		if !sc.dropped[zone] {
+			zones = append(zones, zone)
+		}
+	}
+	result.WildcardZones = zones
+
+	artifacts := result.Artifacts[:0]
+	for _, artifact := range result.Artifacts {
+		if !sc.dropped[artifact.Host] {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	result.Artifacts = artifacts
+
+	for _, p := range sc.pending {
+		if !sc.dropped[p.host] {
+			sc.o.emitSubdomain(sc.domain, p.host, p.source, p.phase)
+		}
+	}
+	sc.pending = nil
+
+	for _, artifact := range sc.artifacts {
+		if !sc.dropped[artifact.Host] {
+			sc.o.emitArtifact(sc.domain, artifact)
+		}
+	}
+	sc.artifacts = nil
+}
+
+// report logs how much the scope left out.
+func (sc *scanScope) report() {
+	if len(sc.unrelated) > 0 {
+		sc.logger.Debugf("ignored %d hosts outside %s", len(sc.unrelated), sc.domain)
+	}
+
+	if len(sc.result.OutOfScope) == 0 && sc.candidates == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Dropped %d out-of-scope hosts", len(sc.result.OutOfScope))
+	if sc.candidates > 0 {
+		message += fmt.Sprintf(" and skipped %d out-of-scope candidates", sc.candidates)
+	}
+	sc.logger.Infof("%s", message)
+}
@@ -0,0 +1,154 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/notify"
+	"github.com/samogod/samoscout/pkg/sink"
+	"github.com/samogod/samoscout/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// setupSinks registers the result sinks. The database and elasticsearch
+// sections keep working without a sinks list; listing one of those types in
+// sinks replaces the implicit sink, for example to change its retries.
+func (o *Orchestrator) setupSinks() {
+	o.sinks = sink.NewDispatcher()
+	o.sinks.OnError = func(name string, event *sink.Event, err error) {
+		o.logger.Warnf("Sink %s failed to handle %s event for %s: %v", name, event.Type, event.Domain, err)
+	}
+
+	configured := make(map[string]bool)
+	for _, cfg := range o.config.Sinks {
+		configured[strings.ToLower(cfg.Type)] = true
+	}
+
+	if !configured["database"] && o.db != nil && o.db.IsEnabled() {
+		o.sinks.Add(sink.NewDatabase(o.db), sink.Options{Retries: 2, Backoff: time.Second})
+	}
+	if !configured["elasticsearch"] && o.config.Elasticsearch.Enabled {
+		o.sinks.Add(sink.NewElasticsearch(o.config.Elasticsearch, o.db), sink.Options{})
+	}
+
+	env := sink.Env{Config: o.config, DB: o.db}
+	for _, cfg := range o.config.Sinks {
+		s, err := sink.New(cfg, env)
+		if err != nil {
+			o.logger.Warnf("Sink %s disabled: %v", cfg.Type, err)
+			continue
+		}
+		o.sinks.Add(s, sink.OptionsFromConfig(cfg))
+	}
+
+	// notifications read the tracking summary, so they go after the database
+	// sink; webhooks are retried by the notifier itself
+	if o.config.Notifications.Enabled {
+		notifier, err := notify.New(o.config.Notifications)
+		if o.db == nil || !o.db.IsEnabled() {
+			o.logger.Warnf("Notifications disabled: they require database.enabled")
+		} else if err != nil {
+			o.logger.Warnf("Notifications disabled: %v", err)
+		} else {
+			o.sinks.Add(notifier, sink.Options{Events: []sink.EventType{sink.EventScanFinished}})
+		}
+	}
+}
+
+// AddSink registers an additional sink, such as the CLI's stdout and -o file
+// output. Sinks receive events in the order they were added.
+func (o *Orchestrator) AddSink(s sink.Sink, options sink.Options) {
+	o.sinks.Add(s, options)
+}
+
+func (o *Orchestrator) emitSubdomain(domain, host, source, phase string) {
+	o.sinks.Emit(context.Background(), &sink.Event{
+		Type:      sink.EventSubdomain,
+		Domain:    domain,
+		Subdomain: &sink.SubdomainEvent{Host: host, Source: source, Phase: phase},
+	})
+}
+
+func (o *Orchestrator) emitArtifact(domain string, artifact sink.Artifact) {
+	o.sinks.Emit(context.Background(), &sink.Event{
+		Type:     sink.EventArtifact,
+		Domain:   domain,
+		Artifact: &artifact,
+	})
+}
+
+// SinkFailures returns how many events each sink failed to handle so far.
+func (o *Orchestrator) SinkFailures() map[string]int {
+	return o.sinks.Failures()
+}
+
+// Close flushes and closes every sink, then the pending trace spans.
+func (o *Orchestrator) Close() error {
+	err := o.sinks.Close()
+	if o.shutdownTracing != nil {
+		if tracingErr := o.shutdownTracing(context.Background()); tracingErr != nil {
+			o.logger.Warnf("Failed to export traces: %v", tracingErr)
+		}
+	}
+	return err
+}
+
+// TrackDiscoveries records hosts found outside a scan, such as by the CT log
+// follower, through the sinks: they are tracked, indexed and notified like
+// scan results, but never mark other hosts DEAD. sources maps each host to
+// the sources that reported it.
+func (o *Orchestrator) TrackDiscoveries(domain, tool string, sources map[string][]string) *database.TrackSummary {
+	ctx, span := tracing.Start(context.Background(), "track",
+		attribute.String("samoscout.domain", domain),
+		attribute.String("samoscout.tool", tool),
+		attribute.Int("samoscout.subdomains", len(sources)),
+	)
+	defer span.End()
+
+	now := time.Now()
+	scanEvent := &sink.ScanEvent{
+		StartedAt:        now,
+		AllSources:       make(map[string][]string),
+		SubdomainSources: make(map[string]string),
+		Imported:         true,
+		Tool:             tool,
+	}
+
+	for host, hostSources := range sources {
+		if len(hostSources) == 0 {
+			continue
+		}
+		if ok, reason := o.scope.Host(host, domain); !ok {
+			log.Debugf("skipping out-of-scope host %s for %s: %s", host, domain, reason)
+			continue
+		}
+		scanEvent.Subdomains = append(scanEvent.Subdomains, host)
+		scanEvent.AllSources[host] = hostSources
+		scanEvent.SubdomainSources[host] = hostSources[0]
+		for _, s := range hostSources {
+			scanEvent.SourcesUsed = appendSource(scanEvent.SourcesUsed, s)
+		}
+	}
+	sort.Strings(scanEvent.Subdomains)
+
+	for _, host := range scanEvent.Subdomains {
+		o.emitSubdomain(domain, host, scanEvent.SubdomainSources[host], "passive")
+	}
+
+	optionsJSON, _ := json.Marshal(map[string]string{"tool": tool})
+	scanEvent.Options = string(optionsJSON)
+	scanEvent.EndedAt = time.Now()
+
+	o.sinks.Emit(ctx, &sink.Event{
+		Type:   sink.EventScanFinished,
+		Domain: domain,
+		Scan:   scanEvent,
+	})
+
+	return scanEvent.Track
+}
@@ -0,0 +1,133 @@
+package orchestrator
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/active"
+	"github.com/samogod/samoscout/pkg/database"
+)
+
+type VerifyOptions struct {
+	Domain  string
+	Probe   bool
+	Threads int
+	Timeout time.Duration
+}
+
+// VerifyTracked re-resolves every tracked host of a domain (and optionally
+// probes the resolving ones over HTTP) and updates their status from that
+// evidence. No passive sources are queried.
+func (o *Orchestrator) VerifyTracked(options VerifyOptions) (*database.VerifySummary, error) {
+	if o.db == nil || !o.db.IsEnabled() {
+		return nil, fmt.Errorf("database is not enabled")
+	}
+
+	records, err := o.db.QuerySubdomains(options.Domain, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tracked subdomains: %w", err)
+	}
+
+	hosts := make([]string, 0, len(records))
+	for _, r := range records {
+		hosts = append(hosts, r.Subdomain)
+	}
+
+	if len(hosts) == 0 {
+		return &database.VerifySummary{Domain: options.Domain}, nil
+	}
+
+	log.Debugf("verifying %d tracked subdomains for %s", len(hosts), options.Domain)
+
+	resolutions := active.ResolveHosts(hosts, options.Threads, options.Timeout)
+
+	// a single timeout or SERVFAIL must not decide anything, so transient
+	// failures get a second attempt before they are reported as unknown
+	var retry []string
+	for host, r := range resolutions {
+		if !r.Resolves() && !r.NotFound {
+			retry = append(retry, host)
+		}
+	}
+	if len(retry) > 0 {
+		log.Debugf("retrying %d lookups that failed without an answer", len(retry))
+		for host, r := range active.ResolveHosts(retry, options.Threads, options.Timeout) {
+			resolutions[host] = r
+		}
+	}
+
+	var results []database.VerifyResult
+	var alive []string
+	unknown := 0
+	for _, host := range hosts {
+		r := resolutions[host]
+		switch {
+		case r.Resolves():
+			results = append(results, database.VerifyResult{Subdomain: host, Resolves: true})
+			alive = append(alive, host)
+		case r.NotFound:
+			results = append(results, database.VerifyResult{Subdomain: host})
+		default:
+			unknown++
+			log.Debugf("could not verify %s: %v", host, r.Err)
+		}
+	}
+
+	if options.Probe && len(alive) > 0 {
+		probes, err := o.probeHosts(options.Domain, alive)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range results {
+			if !results[i].Resolves {
+				continue
+			}
+			results[i].Probed = true
+			if p, ok := probes[results[i].Subdomain]; ok {
+				results[i].HTTPStatus = p.StatusCode
+				results[i].HTTPTitle = p.Title
+			}
+		}
+	}
+
+	summary, err := o.db.ApplyVerification(options.Domain, results)
+	if err != nil {
+		return nil, err
+	}
+	summary.Unknown = unknown
+
+	return summary, nil
+}
+
+func (o *Orchestrator) probeHosts(domain string, hosts []string) (map[string]active.HttpxResult, error) {
+	domainDir := filepath.Join(o.config.ActiveEnumeration.OutputDir, domain)
+	if err := os.MkdirAll(domainDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	results, _, err := active.ProbeHTTP(hosts, domainDir, "verify_input.txt", log.DebugEnabled())
+	if err != nil {
+		return nil, fmt.Errorf("HTTP probing failed: %w", err)
+	}
+
+	// depending on the httpx version "host" holds the IP, so use the URL
+	probes := make(map[string]active.HttpxResult)
+	for _, r := range results {
+		u, err := url.Parse(r.URL)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		if existing, ok := probes[host]; ok && existing.StatusCode != 0 {
+			continue
+		}
+		probes[host] = r
+	}
+
+	return probes, nil
+}
@@ -0,0 +1,242 @@
+package scope
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the YAML scope definition:
+//
+//	include:
+//	  domains: ["*.example.com", "example.com", "*.example.org"]
+//	exclude:
+//	  domains: ["*.corp.example.com"]
+//	  regex: ['^dev-[0-9]+\.example\.com$']
+//	  cidrs: ["10.0.0.0/8"]
+//
+// A "*" in a domain matches any characters, dots included. Without include
+// domains or regexes, the scanned domain and its subdomains are in scope.
+// CIDR rules apply to the addresses a host resolves to.
+type File struct {
+	Include Rules `yaml:"include"`
+	Exclude Rules `yaml:"exclude"`
+}
+
+type Rules struct {
+	Domains []string `yaml:"domains"`
+	Regex   []string `yaml:"regex"`
+	CIDRs   []string `yaml:"cidrs"`
+}
+
+// Drop is a host left out of a scan because of its scope.
+type Drop struct {
+	Host   string `json:"host"`
+	Phase  string `json:"phase"`
+	Reason string `json:"reason"`
+}
+
+// Scope decides which hosts a scan keeps. A nil Scope keeps the scanned
+// domain and its subdomains.
+type Scope struct {
+	Path string
+
+	include ruleSet
+	exclude ruleSet
+}
+
+type hostRule struct {
+	pattern string
+	regex   bool
+	re      *regexp.Regexp
+}
+
+func (r hostRule) String() string {
+	if r.regex {
+		return "regex " + r.pattern
+	}
+	return r.pattern
+}
+
+type ruleSet struct {
+	hosts []hostRule
+	nets  []*net.IPNet
+}
+
+func Load(path string) (*Scope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scope file: %w", err)
+	}
+
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scope file %s: %w", path, err)
+	}
+	s.Path = path
+	return s, nil
+}
+
+func Parse(data []byte) (*Scope, error) {
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse scope: %w", err)
+	}
+
+	include, err := compile(f.Include)
+	if err != nil {
+		return nil, fmt.Errorf("include: %w", err)
+	}
+	exclude, err := compile(f.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("exclude: %w", err)
+	}
+
+	return &Scope{include: include, exclude: exclude}, nil
+}
+
+func compile(rules Rules) (ruleSet, error) {
+	var set ruleSet
+
+	for _, domain := range rules.Domains {
+		pattern := normalize(domain)
+		if pattern == "" {
+			continue
+		}
+		parts := strings.Split(pattern, "*")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		re := regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+		set.hosts = append(set.hosts, hostRule{pattern: pattern, re: re})
+	}
+
+	for _, expr := range rules.Regex {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return set, fmt.Errorf("invalid regex %q: %w", expr, err)
+		}
+		set.hosts = append(set.hosts, hostRule{pattern: expr, regex: true, re: re})
+	}
+
+	for _, cidr := range rules.CIDRs {
+		cidr = strings.TrimSpace(cidr)
+		if ip := net.ParseIP(cidr); ip != nil {
+			bits := 128
+			if ip.To4() != nil {
+				bits = 32
+			}
+			cidr = fmt.Sprintf("%s/%d", cidr, bits)
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return set, fmt.Errorf("invalid CIDR %q", cidr)
+		}
+		set.nets = append(set.nets, ipNet)
+	}
+
+	return set, nil
+}
+
+func normalize(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+func (set ruleSet) matchHost(host string) (hostRule, bool) {
+	for _, rule := range set.hosts {
+		if rule.re.MatchString(host) {
+			return rule, true
+		}
+	}
+	return hostRule{}, false
+}
+
+func (set ruleSet) matchAddr(ip net.IP) (*net.IPNet, bool) {
+	for _, ipNet := range set.nets {
+		if ipNet.Contains(ip) {
+			return ipNet, true
+		}
+	}
+	return nil, false
+}
+
+// Host reports whether host is in scope by name for a scan of target, and
+// if not, why.
+func (s *Scope) Host(host, target string) (bool, string) {
+	host = normalize(host)
+	target = normalize(target)
+
+	if s != nil {
+		if rule, ok := s.exclude.matchHost(host); ok {
+			return false, "excluded by " + rule.String()
+		}
+		if len(s.include.hosts) > 0 {
+			if _, ok := s.include.matchHost(host); ok {
+				return true, ""
+			}
+			return false, "not matched by any include rule"
+		}
+	}
+
+	if inTarget(host, target) {
+		return true, ""
+	}
+	return false, "outside " + target
+}
+
+// Related reports whether host is target, one of its subdomains or matched
+// by an include rule. Sources also report names that are none of these, such
+// as the other names of a certificate; Host drops them, but they say nothing
+// about the scope.
+func (s *Scope) Related(host, target string) bool {
+	host = normalize(host)
+	if inTarget(host, normalize(target)) {
+		return true
+	}
+	if s == nil {
+		return false
+	}
+	_, ok := s.include.matchHost(host)
+	return ok
+}
+
+func inTarget(host, target string) bool {
+	return host == target || strings.HasSuffix(host, "."+target)
+}
+
+// HasNetworks reports whether the scope has CIDR rules, which need the hosts
+// to be resolved.
+func (s *Scope) HasNetworks() bool {
+	return s != nil && (len(s.include.nets) > 0 || len(s.exclude.nets) > 0)
+}
+
+// Addresses reports whether a host resolving to addrs is in scope, and if
+// not, why. A host without addresses is judged by its name alone.
+func (s *Scope) Addresses(addrs []string) (bool, string) {
+	if !s.HasNetworks() || len(addrs) == 0 {
+		return true, ""
+	}
+
+	included := len(s.include.nets) == 0
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+		if ipNet, ok := s.exclude.matchAddr(ip); ok {
+			return false, fmt.Sprintf("resolves to %s in excluded %s", addr, ipNet)
+		}
+		if _, ok := s.include.matchAddr(ip); ok {
+			included = true
+		}
+	}
+
+	if !included {
+		return false, fmt.Sprintf("resolves to %s outside the included ranges", strings.Join(addrs, ", "))
+	}
+	return true, ""
+}
@@ -0,0 +1,188 @@
+package scope
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, data string) *Scope {
+	t.Helper()
+
+	s, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return s
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		err      string
+		networks bool
+	}{
+		{name: "empty", data: ""},
+		{name: "domains and regex", data: "include:\n  domains: ['*.example.com']\nexclude:\n  regex: ['^dev-']\n"},
+		{name: "cidrs", data: "include:\n  cidrs: ['10.0.0.0/8']\n", networks: true},
+		{name: "bare addresses", data: "exclude:\n  cidrs: ['203.0.113.7', '2001:db8::1']\n", networks: true},
+		{name: "invalid regex", data: "include:\n  regex: ['(']\n", err: "include: invalid regex"},
+		{name: "invalid cidr", data: "exclude:\n  cidrs: ['10.0.0.0/33']\n", err: "exclude: invalid CIDR"},
+		{name: "invalid yaml", data: "include: [", err: "failed to parse scope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse([]byte(tt.data))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := s.HasNetworks(); got != tt.networks {
+				t.Errorf("HasNetworks() = %v, want %v", got, tt.networks)
+			}
+		})
+	}
+}
+
+func TestHost(t *testing.T) {
+	wildcards := mustParse(t, `
+include:
+  domains: ["*.example.com"]
+exclude:
+  domains: ["*.corp.example.com"]
+  regex: ['^dev-[0-9]+\.example\.com$']
+`)
+	apexAndWildcard := mustParse(t, `
+include:
+  domains: ["example.com", "*.example.com", "*.example.org"]
+`)
+	excludeOnly := mustParse(t, `
+exclude:
+  domains: ["staging.example.com"]
+`)
+
+	tests := []struct {
+		name   string
+		scope  *Scope
+		host   string
+		target string
+		want   bool
+	}{
+		{"nil scope keeps the target", nil, "example.com", "example.com", true},
+		{"nil scope keeps subdomains", nil, "a.b.example.com", "example.com", true},
+		{"nil scope drops other domains", nil, "example.org", "example.com", false},
+		{"nil scope drops lookalikes", nil, "notexample.com", "example.com", false},
+		{"case and trailing dots", nil, "WWW.Example.COM.", "example.com.", true},
+
+		{"wildcard matches subdomains", wildcards, "www.example.com", "example.com", true},
+		{"wildcard matches deeper levels", wildcards, "a.b.example.com", "example.com", true},
+		{"wildcard does not match the apex", wildcards, "example.com", "example.com", false},
+		{"wildcard exclude wins", wildcards, "vpn.corp.example.com", "example.com", false},
+		{"regex exclude wins", wildcards, "dev-12.example.com", "example.com", false},
+		{"regex exclude is anchored", wildcards, "dev-12.www.example.com", "example.com", true},
+
+		{"apex listed with its wildcard", apexAndWildcard, "example.com", "example.com", true},
+		{"include overrides the target suffix", apexAndWildcard, "www.example.org", "example.com", true},
+		{"include still drops other domains", apexAndWildcard, "www.example.net", "example.com", false},
+
+		{"exclude only keeps the target check", excludeOnly, "www.example.com", "example.com", true},
+		{"exclude only drops listed hosts", excludeOnly, "staging.example.com", "example.com", false},
+		{"exclude only drops other domains", excludeOnly, "www.example.org", "example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := tt.scope.Host(tt.host, tt.target)
+			if got != tt.want {
+				t.Errorf("Host(%q, %q) = %v (%s), want %v", tt.host, tt.target, got, reason, tt.want)
+			}
+			if !got && reason == "" {
+				t.Errorf("Host(%q, %q) dropped the host without a reason", tt.host, tt.target)
+			}
+		})
+	}
+}
+
+func TestRelated(t *testing.T) {
+	s := mustParse(t, `
+include:
+  domains: ["*.example.org"]
+exclude:
+  domains: ["*.corp.example.com", "*.other.net"]
+`)
+
+	tests := []struct {
+		name  string
+		scope *Scope
+		host  string
+		want  bool
+	}{
+		{"nil scope target", nil, "example.com", true},
+		{"nil scope subdomain", nil, "WWW.Example.com.", true},
+		{"nil scope other domain", nil, "www.example.net", false},
+		{"excluded subdomain of the target", s, "vpn.corp.example.com", true},
+		{"included other domain", s, "www.example.org", true},
+		{"excluded other domain", s, "www.other.net", false},
+		{"unmatched other domain", s, "www.example.net", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scope.Related(tt.host, "example.com"); got != tt.want {
+				t.Errorf("Related(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	include := mustParse(t, `
+include:
+  cidrs: ["10.0.0.0/8", "203.0.113.7", "2001:db8::/32"]
+exclude:
+  cidrs: ["10.1.0.0/16"]
+`)
+	excludeOnly := mustParse(t, `
+exclude:
+  cidrs: ["198.51.100.1"]
+`)
+
+	tests := []struct {
+		name  string
+		scope *Scope
+		addrs []string
+		want  bool
+	}{
+		{"nil scope", nil, []string{"192.0.2.1"}, true},
+		{"in an included range", include, []string{"10.2.3.4"}, true},
+		{"bare address", include, []string{"203.0.113.7"}, true},
+		{"next to the bare address", include, []string{"203.0.113.8"}, false},
+		{"ipv6 range", include, []string{"2001:db8::53"}, true},
+		{"outside the included ranges", include, []string{"192.0.2.1"}, false},
+		{"one included address is enough", include, []string{"192.0.2.1", "10.2.3.4"}, true},
+		{"excluded range wins", include, []string{"10.1.2.3"}, false},
+		{"one excluded address drops the host", include, []string{"10.2.3.4", "10.1.2.3"}, false},
+		{"unresolved hosts are judged by name", include, nil, true},
+		{"unparsable addresses are skipped", include, []string{"bogus", "10.2.3.4"}, true},
+		{"exclude only keeps other addresses", excludeOnly, []string{"198.51.100.2"}, true},
+		{"exclude only drops the bare address", excludeOnly, []string{"198.51.100.1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := tt.scope.Addresses(tt.addrs)
+			if got != tt.want {
+				t.Errorf("Addresses(%v) = %v (%s), want %v", tt.addrs, got, reason, tt.want)
+			}
+			if !got && reason == "" {
+				t.Errorf("Addresses(%v) dropped the host without a reason", tt.addrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/orchestrator"
+	"github.com/samogod/samoscout/pkg/scope"
+)
+
+// ScanRequest is the body of POST /scans. It mirrors the scan flags of the
+// CLI; Wordlist and Scope name files in the server's files directory.
+type ScanRequest struct {
+	Domain         string `json:"domain"`
+	Sources        string `json:"sources,omitempty"`
+	ExcludeSources string `json:"exclude_sources,omitempty"`
+	Active         bool   `json:"active,omitempty"`
+	DeepEnum       bool   `json:"deep_enum,omitempty"`
+	LLM            bool   `json:"llm,omitempty"`
+	Httpx          bool   `json:"httpx,omitempty"`
+	Wordlist       string `json:"wordlist,omitempty"`
+	Scope          string `json:"scope,omitempty"`
+}
+
+func (r *ScanRequest) validate(filesDir string) error {
+	r.Domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(r.Domain)), ".")
+	if r.Domain == "" {
+		return fmt.Errorf("domain is required")
+	}
+	if strings.ContainsAny(r.Domain, "/:@ \t") || !strings.Contains(r.Domain, ".") {
+		return fmt.Errorf("invalid domain %q", r.Domain)
+	}
+
+	options, err := r.ScanOptions(filesDir)
+	if err != nil {
+		return err
+	}
+	if options.ScopeFile != "" {
+		if _, err := scope.Load(options.ScopeFile); err != nil {
+			return fmt.Errorf("invalid scope %q", r.Scope)
+		}
+	}
+	return nil
+}
+
+// ScanOptions resolves Wordlist and Scope in filesDir, so a request can only
+// read the files placed there.
+func (r ScanRequest) ScanOptions(filesDir string) (orchestrator.ScanOptions, error) {
+	wordlist, err := resolveFile(filesDir, "wordlist", r.Wordlist)
+	if err != nil {
+		return orchestrator.ScanOptions{}, err
+	}
+	scopeFile, err := resolveFile(filesDir, "scope", r.Scope)
+	if err != nil {
+		return orchestrator.ScanOptions{}, err
+	}
+
+	return orchestrator.ScanOptions{
+		Domain:         r.Domain,
+		Sources:        r.Sources,
+		ExcludeSources: r.ExcludeSources,
+		ActiveEnum:     r.Active,
+		DeepEnum:       r.DeepEnum,
+		LLMEnum:        r.LLM,
+		HttpxProbe:     r.Httpx,
+		WordlistPath:   wordlist,
+		ScopeFile:      scopeFile,
+	}, nil
+}
+
+// resolveFile returns the path of the file name in dir. Names leaving dir,
+// also through a symlink, are rejected.
+func resolveFile(dir, field, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	if dir == "" {
+		return "", fmt.Errorf("%s is not accepted: server.files_dir is not configured", field)
+	}
+	if filepath.IsAbs(name) || !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid %s %q: must be a file name in the server's files directory", field, name)
+	}
+
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open files directory: %w", err)
+	}
+	path, err := filepath.EvalSymlinks(filepath.Join(root, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s %q not found", field, name)
+		}
+		return "", fmt.Errorf("failed to open %s %q", field, name)
+	}
+	if rel, err := filepath.Rel(root, path); err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("invalid %s %q: must be a file name in the server's files directory", field, name)
+	}
+	return path, nil
+}
+
+type jobView struct {
+	ID         int64           `json:"id"`
+	Domain     string          `json:"domain"`
+	Status     string          `json:"status"`
+	Error      string          `json:"error,omitempty"`
+	Options    json.RawMessage `json:"options"`
+	ScanID     int64           `json:"scan_id,omitempty"`
+	Subdomains int             `json:"subdomains"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
+
+func newJobView(job database.Job) jobView {
+	v := jobView{
+		ID:         job.ID,
+		Domain:     job.Domain,
+		Status:     job.Status,
+		Error:      job.Error,
+		Options:    json.RawMessage(job.Options),
+		ScanID:     job.ScanID,
+		Subdomains: job.Subdomains,
+		CreatedAt:  job.CreatedAt,
+	}
+	if !json.Valid(v.Options) {
+		v.Options = json.RawMessage("{}")
+	}
+	if !job.StartedAt.IsZero() {
+		startedAt := job.StartedAt
+		v.StartedAt = &startedAt
+	}
+	if !job.FinishedAt.IsZero() {
+		finishedAt := job.FinishedAt
+		v.FinishedAt = &finishedAt
+	}
+	return v
+}
+
+type subdomainView struct {
+	Domain      string    `json:"domain"`
+	Subdomain   string    `json:"subdomain"`
+	Status      string    `json:"status"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	TriageState string    `json:"triage_state"`
+	Tags        []string  `json:"tags"`
+	HTTPStatus  int       `json:"http_status,omitempty"`
+	HTTPTitle   string    `json:"http_title,omitempty"`
+}
+
+type scanView struct {
+	ID              int64     `json:"id"`
+	Domain          string    `json:"domain"`
+	StartedAt       time.Time `json:"started_at"`
+	EndedAt         time.Time `json:"ended_at"`
+	Tool            string    `json:"tool"`
+	Sources         []string  `json:"sources"`
+	TotalSubdomains int       `json:"total"`
+	NewSubdomains   int       `json:"new"`
+	Reactivated     int       `json:"revived"`
+	DeadSubdomains  int       `json:"dead"`
+}
+
+type observationView struct {
+	ScanID    int64     `json:"scan_id"`
+	StartedAt time.Time `json:"started_at"`
+	Subdomain string    `json:"subdomain"`
+	Seen      bool      `json:"seen"`
+	Sources   []string  `json:"sources"`
+}
+
+type statusChangeView struct {
+	ScanID    int64     `json:"scan_id,omitempty"`
+	OldStatus string    `json:"old_status"`
+	NewStatus string    `json:"new_status"`
+	Reason    string    `json:"reason"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func newSubdomainViews(records []database.SubdomainRecord) []subdomainView {
+	views := make([]subdomainView, 0, len(records))
+	for _, r := range records {
+		views = append(views, subdomainView{
+			Domain:      r.Domain,
+			Subdomain:   r.Subdomain,
+			Status:      r.Status,
+			FirstSeen:   r.FirstSeen,
+			LastSeen:    r.LastSeen,
+			TriageState: r.TriageState,
+			Tags:        append([]string{}, r.Tags...),
+			HTTPStatus:  r.HTTPStatus,
+			HTTPTitle:   r.HTTPTitle,
+		})
+	}
+	return views
+}
+
+func newScanViews(records []database.ScanRecord) []scanView {
+	views := make([]scanView, 0, len(records))
+	for _, r := range records {
+		views = append(views, scanView{
+			ID:              r.ID,
+			Domain:          r.Domain,
+			StartedAt:       r.StartedAt,
+			EndedAt:         r.EndedAt,
+			Tool:            r.Tool,
+			Sources:         append([]string{}, r.Sources...),
+			TotalSubdomains: r.TotalSubdomains,
+			NewSubdomains:   r.NewSubdomains,
+			Reactivated:     r.Reactivated,
+			DeadSubdomains:  r.DeadSubdomains,
+		})
+	}
+	return views
+}
+
+func newObservationViews(records []database.ObservationRecord) []observationView {
+	views := make([]observationView, 0, len(records))
+	for _, r := range records {
+		views = append(views, observationView{
+			ScanID:    r.ScanID,
+			StartedAt: r.StartedAt,
+			Subdomain: r.Subdomain,
+			Seen:      r.Seen,
+			Sources:   append([]string{}, r.Sources...),
+		})
+	}
+	return views
+}
+
+func newStatusChangeViews(changes []database.StatusChange) []statusChangeView {
+	views := make([]statusChangeView, 0, len(changes))
+	for _, c := range changes {
+		views = append(views, statusChangeView{
+			ScanID:    c.ScanID,
+			OldStatus: c.OldStatus,
+			NewStatus: c.NewStatus,
+			Reason:    c.Reason,
+			ChangedAt: c.ChangedAt,
+		})
+	}
+	return views
+}
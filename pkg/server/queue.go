@@ -0,0 +1,399 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/orchestrator"
+	"github.com/samogod/samoscout/pkg/sink"
+)
+
+// maxReplay caps the events kept per job for clients that subscribe late.
+const maxReplay = 1000
+
+var (
+	errJobRunning  = errors.New("job is already running")
+	errJobFinished = errors.New("job has already finished")
+)
+
+// Event is one server-sent event of a job's progress stream.
+type Event struct {
+	ID   int
+	Name string
+	Data interface{}
+}
+
+// liveJob is a queued or running job of this process: its current state, the
+// recent events and the clients streaming them.
+type liveJob struct {
+	mu     sync.Mutex
+	job    database.Job
+	nextID int
+	events []Event
+	subs   map[chan Event]struct{}
+	done   bool
+}
+
+func (lj *liveJob) snapshot() database.Job {
+	lj.mu.Lock()
+	defer lj.mu.Unlock()
+	return lj.job
+}
+
+func (lj *liveJob) publish(name string, data interface{}) {
+	lj.mu.Lock()
+	defer lj.mu.Unlock()
+
+	lj.nextID++
+	event := Event{ID: lj.nextID, Name: name, Data: data}
+	lj.events = append(lj.events, event)
+	if len(lj.events) > maxReplay {
+		lj.events = lj.events[len(lj.events)-maxReplay:]
+	}
+
+	for ch := range lj.subs {
+		select {
+		case ch <- event:
+		default:
+			// a client that cannot keep up is dropped rather than stalling the scan
+			delete(lj.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe returns the events so far and a channel for the rest. The channel
+// is closed when the job ends.
+func (lj *liveJob) subscribe() ([]Event, chan Event) {
+	lj.mu.Lock()
+	defer lj.mu.Unlock()
+
+	replay := append([]Event(nil), lj.events...)
+	ch := make(chan Event, 256)
+	if lj.done {
+		close(ch)
+		return replay, ch
+	}
+	lj.subs[ch] = struct{}{}
+	return replay, ch
+}
+
+func (lj *liveJob) unsubscribe(ch chan Event) {
+	lj.mu.Lock()
+	defer lj.mu.Unlock()
+	if _, ok := lj.subs[ch]; ok {
+		delete(lj.subs, ch)
+		close(ch)
+	}
+}
+
+// update applies change to the job and publishes the new state as a
+// "status" event.
+func (lj *liveJob) update(change func(job *database.Job)) database.Job {
+	lj.mu.Lock()
+	change(&lj.job)
+	job := lj.job
+	lj.mu.Unlock()
+
+	lj.publish("status", newJobView(job))
+	return job
+}
+
+func (lj *liveJob) finish() {
+	lj.mu.Lock()
+	defer lj.mu.Unlock()
+	lj.done = true
+	for ch := range lj.subs {
+		close(ch)
+	}
+	lj.subs = nil
+}
+
+// Queue runs scan jobs with bounded concurrency. Jobs are stored in the
+// database before they are accepted, so queued jobs survive a restart, and
+// two jobs for the same domain never run at the same time.
+type Queue struct {
+	orch        *orchestrator.Orchestrator
+	db          *database.DB
+	concurrency int
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	pending  []*liveJob
+	running  map[string]*liveJob
+	live     map[int64]*liveJob
+	stopping bool
+	wg       sync.WaitGroup
+}
+
+func NewQueue(orch *orchestrator.Orchestrator, concurrency int) *Queue {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	q := &Queue{
+		orch:        orch,
+		db:          orch.GetDB(),
+		concurrency: concurrency,
+		running:     make(map[string]*liveJob),
+		live:        make(map[int64]*liveJob),
+	}
+	q.cond = sync.NewCond(&q.mu)
+
+	// scan progress reaches job streams through the orchestrator's sinks
+	orch.AddSink(&progressSink{queue: q}, sink.Options{})
+
+	return q
+}
+
+// Start requeues jobs interrupted by a previous shutdown, loads the queue and
+// starts the workers.
+func (q *Queue) Start() (int, error) {
+	if _, err := q.db.RequeueJobs(); err != nil {
+		return 0, fmt.Errorf("failed to requeue interrupted jobs: %w", err)
+	}
+
+	jobs, err := q.db.QueryJobs(database.JobFilter{Status: database.JobQueued})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load queued jobs: %w", err)
+	}
+
+	q.mu.Lock()
+	for _, job := range jobs {
+		q.enqueue(job)
+	}
+	q.mu.Unlock()
+
+	for i := 0; i < q.concurrency; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+
+	return len(jobs), nil
+}
+
+// Stop lets running scans finish but starts no new ones. Jobs still queued
+// stay queued in the database.
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	q.stopping = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
+
+func (q *Queue) Running() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.running)
+}
+
+func (q *Queue) Submit(request ScanRequest) (database.Job, error) {
+	options, err := json.Marshal(request)
+	if err != nil {
+		return database.Job{}, err
+	}
+
+	job, err := q.db.CreateJob(request.Domain, string(options))
+	if err != nil {
+		return database.Job{}, fmt.Errorf("failed to store job: %w", err)
+	}
+
+	q.mu.Lock()
+	lj := q.enqueue(*job)
+	q.mu.Unlock()
+
+	lj.publish("status", newJobView(*job))
+	return *job, nil
+}
+
+// enqueue must be called with q.mu held.
+func (q *Queue) enqueue(job database.Job) *liveJob {
+	lj := &liveJob{job: job, subs: make(map[chan Event]struct{})}
+	q.pending = append(q.pending, lj)
+	q.live[job.ID] = lj
+	q.cond.Signal()
+	return lj
+}
+
+// Cancel removes a queued job. Running scans cannot be interrupted.
+func (q *Queue) Cancel(id int64) (database.Job, error) {
+	q.mu.Lock()
+	var lj *liveJob
+	for i, p := range q.pending {
+		if p.job.ID == id {
+			lj = p
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			delete(q.live, id)
+			break
+		}
+	}
+	if lj == nil {
+		_, running := q.live[id]
+		q.mu.Unlock()
+		if running {
+			return database.Job{}, errJobRunning
+		}
+		return database.Job{}, errJobFinished
+	}
+	q.mu.Unlock()
+
+	job := lj.update(func(job *database.Job) {
+		job.Status = database.JobCancelled
+		job.FinishedAt = time.Now()
+	})
+	lj.finish()
+
+	if err := q.db.UpdateJob(&job); err != nil {
+		return job, fmt.Errorf("failed to store job: %w", err)
+	}
+	return job, nil
+}
+
+// Live returns the in-memory state of a queued or running job.
+func (q *Queue) Live(id int64) *liveJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.live[id]
+}
+
+func (q *Queue) worker() {
+	defer q.wg.Done()
+	for {
+		lj := q.next()
+		if lj == nil {
+			return
+		}
+		q.run(lj)
+	}
+}
+
+// next blocks until a job whose domain is not being scanned is queued. It
+// returns nil once the queue is stopping.
+func (q *Queue) next() *liveJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		if q.stopping {
+			return nil
+		}
+		for i, lj := range q.pending {
+			if _, busy := q.running[lj.job.Domain]; busy {
+				continue
+			}
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			q.running[lj.job.Domain] = lj
+			return lj
+		}
+		q.cond.Wait()
+	}
+}
+
+func (q *Queue) run(lj *liveJob) {
+	job := lj.update(func(job *database.Job) {
+		job.Status = database.JobRunning
+		job.StartedAt = time.Now()
+	})
+	if err := q.db.UpdateJob(&job); err != nil {
+		log.Debugf("failed to store state of job %d: %v", job.ID, err)
+	}
+
+	log.Debugf("running job %d for %s", job.ID, job.Domain)
+
+	var result *orchestrator.ScanResult
+	var request ScanRequest
+	err := json.Unmarshal([]byte(job.Options), &request)
+	if err == nil {
+		var options orchestrator.ScanOptions
+		if options, err = request.ScanOptions(q.orch.GetConfig().Server.FilesDir); err == nil {
+			result, err = q.orch.RunScan(options)
+		}
+	}
+
+	job = lj.update(func(job *database.Job) {
+		job.FinishedAt = time.Now()
+		job.Status = database.JobFinished
+		switch {
+		case err != nil:
+			job.Status = database.JobFailed
+			job.Error = err.Error()
+		case !result.Success:
+			job.Status = database.JobFailed
+			job.Error = joinErrors(result.Errors)
+		}
+		if result != nil {
+			job.Subdomains = result.TotalSubdomains
+			if result.Track != nil {
+				job.ScanID = result.Track.ScanID
+			}
+		}
+	})
+	if err := q.db.UpdateJob(&job); err != nil {
+		log.Debugf("failed to store state of job %d: %v", job.ID, err)
+	}
+
+	q.mu.Lock()
+	delete(q.running, job.Domain)
+	delete(q.live, job.ID)
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	lj.finish()
+}
+
+func (q *Queue) runningJob(domain string) *liveJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.running[domain]
+}
+
+func joinErrors(errs []error) string {
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// progressSink forwards scan events to the job running for their domain.
+type progressSink struct {
+	queue *Queue
+}
+
+func (p *progressSink) Name() string {
+	return "progress"
+}
+
+func (p *progressSink) Handle(ctx context.Context, event *sink.Event) error {
+	lj := p.queue.runningJob(event.Domain)
+	if lj == nil {
+		return nil
+	}
+
+	switch event.Type {
+	case sink.EventSubdomain:
+		lj.publish(string(event.Type), event.Subdomain)
+	case sink.EventProbe:
+		lj.publish(string(event.Type), event.Probe)
+	case sink.EventArtifact:
+		lj.publish(string(event.Type), event.Artifact)
+	case sink.EventScanFinished:
+		lj.publish(string(event.Type), map[string]interface{}{
+			"subdomains": len(event.Scan.Subdomains),
+			"partial":    event.Scan.Partial,
+			"track":      event.Scan.Track,
+		})
+	}
+	return nil
+}
+
+func (p *progressSink) Close() error {
+	return nil
+}
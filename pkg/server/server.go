@@ -0,0 +1,479 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/logging"
+	"github.com/samogod/samoscout/pkg/metrics"
+	"github.com/samogod/samoscout/pkg/orchestrator"
+)
+
+var log = logging.Component("server")
+
+// Server is the REST API of "samoscout serve". Scans go through the job
+// queue; everything else is read from the tracking database.
+type Server struct {
+	db       *database.DB
+	queue    *Queue
+	tokens   []string
+	filesDir string
+}
+
+// New creates the API for an orchestrator whose database is enabled. With no
+// tokens every request is accepted.
+func New(orch *orchestrator.Orchestrator, queue *Queue, tokens []string) *Server {
+	return &Server{
+		db:       orch.GetDB(),
+		queue:    queue,
+		tokens:   tokens,
+		filesDir: orch.GetConfig().Server.FilesDir,
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.Handle("/metrics", s.auth(metrics.Handler()))
+	mux.Handle("/scans", s.auth(http.HandlerFunc(s.handleScans)))
+	mux.Handle("/scans/", s.auth(http.HandlerFunc(s.handleScan)))
+	mux.Handle("/subdomains", s.auth(http.HandlerFunc(s.handleSubdomains)))
+	mux.Handle("/subdomains/", s.auth(http.HandlerFunc(s.handleTimeline)))
+	mux.Handle("/domains/", s.auth(http.HandlerFunc(s.handleDomain)))
+	return mux
+}
+
+// auth accepts the token as a bearer token, in X-API-Token, or as the token
+// query parameter for clients such as EventSource that cannot set headers.
+func (s *Server) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.tokens) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := r.Header.Get("X-API-Token")
+		if bearer := r.Header.Get("Authorization"); strings.HasPrefix(bearer, "Bearer ") {
+			token = strings.TrimPrefix(bearer, "Bearer ")
+		}
+		if token == "" {
+			token = r.URL.Query().Get("token")
+		}
+
+		for _, t := range s.tokens {
+			if token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		w.Header().Set("WWW-Authenticate", `Bearer realm="samoscout"`)
+		writeError(w, http.StatusUnauthorized, "missing or invalid API token")
+	})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"running": s.queue.Running(),
+	})
+}
+
+// handleScans serves POST /scans and GET /scans?domain=&status=&limit=.
+func (s *Server) handleScans(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var request ScanRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
+		if err := request.validate(s.filesDir); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		job, err := s.queue.Submit(request)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Location", fmt.Sprintf("/scans/%d", job.ID))
+		writeJSON(w, http.StatusAccepted, newJobView(job))
+
+	case http.MethodGet:
+		query := r.URL.Query()
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		if limit <= 0 {
+			limit = 100
+		}
+		jobs, err := s.db.QueryJobs(database.JobFilter{
+			Domain: strings.ToLower(query.Get("domain")),
+			Status: strings.ToLower(query.Get("status")),
+			Limit:  limit,
+		})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		views := make([]jobView, 0, len(jobs))
+		for _, job := range jobs {
+			views = append(views, s.currentJob(job))
+		}
+		writeJSON(w, http.StatusOK, views)
+
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+// handleScan serves /scans/{id}, /scans/{id}/events and /scans/{id}/results.
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/scans/"), "/"), "/")
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || len(parts) > 2 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	job, err := s.db.GetJob(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if job == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("job %d not found", id))
+		return
+	}
+
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.currentJob(*job))
+
+	case action == "" && r.Method == http.MethodDelete:
+		cancelled, err := s.queue.Cancel(id)
+		switch {
+		case errors.Is(err, errJobRunning), errors.Is(err, errJobFinished):
+			writeError(w, http.StatusConflict, fmt.Sprintf("job %d cannot be cancelled: %v", id, err))
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		default:
+			writeJSON(w, http.StatusOK, newJobView(cancelled))
+		}
+
+	case action == "events" && r.Method == http.MethodGet:
+		s.streamEvents(w, r, *job)
+
+	case action == "results" && r.Method == http.MethodGet:
+		s.writeResults(w, s.currentJob(*job))
+
+	case action == "" || action == "events" || action == "results":
+		methodNotAllowed(w, http.MethodGet)
+
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// currentJob prefers the in-memory state, which is ahead of the database
+// while a job's status is being written.
+func (s *Server) currentJob(job database.Job) jobView {
+	if lj := s.queue.Live(job.ID); lj != nil {
+		return newJobView(lj.snapshot())
+	}
+	return newJobView(job)
+}
+
+func (s *Server) writeResults(w http.ResponseWriter, job jobView) {
+	if job.ScanID == 0 {
+		writeError(w, http.StatusConflict, fmt.Sprintf("job %d has no results (status: %s)", job.ID, job.Status))
+		return
+	}
+
+	observations, err := s.db.QueryScanObservations(job.ScanID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	type result struct {
+		Subdomain string   `json:"subdomain"`
+		Sources   []string `json:"sources"`
+	}
+	results := make([]result, 0, len(observations))
+	for _, o := range observations {
+		results = append(results, result{Subdomain: o.Subdomain, Sources: append([]string{}, o.Sources...)})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"job":        job,
+		"subdomains": results,
+	})
+}
+
+// streamEvents sends the job's progress as server-sent events: the recent
+// history first, then live events until the job ends. Jobs that ended before
+// the request get a single status event.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request, job database.Job) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	lj := s.queue.Live(job.ID)
+	if lj == nil {
+		writeEvent(w, Event{Name: "status", Data: newJobView(job)})
+		flusher.Flush()
+		return
+	}
+
+	replay, events := lj.subscribe()
+	defer lj.unsubscribe(events)
+
+	for _, event := range replay {
+		writeEvent(w, event)
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(15 * time.Second)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			writeEvent(w, event)
+			flusher.Flush()
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event Event) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return
+	}
+	if event.ID > 0 {
+		fmt.Fprintf(w, "id: %d\n", event.ID)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Name, data)
+}
+
+// handleSubdomains serves GET /subdomains with the filters of "samoscout
+// track": domain, status, since, until, time_field, new_since_last_scan,
+// match, regex, tag (repeatable) and state.
+func (s *Server) handleSubdomains(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	filter, err := subdomainFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	records, err := s.db.FindSubdomains(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newSubdomainViews(records))
+}
+
+func subdomainFilter(r *http.Request) (database.SubdomainFilter, error) {
+	query := r.URL.Query()
+	filter := database.SubdomainFilter{
+		Domain:      strings.ToLower(query.Get("domain")),
+		Status:      strings.ToUpper(query.Get("status")),
+		Match:       strings.ToLower(query.Get("match")),
+		TriageState: strings.ToLower(query.Get("state")),
+	}
+
+	if v := query.Get("new_since_last_scan"); v != "" {
+		filter.NewSinceLastScan, _ = strconv.ParseBool(v)
+	}
+	if filter.TriageState != "" && !database.ValidTriageState(filter.TriageState) {
+		return filter, fmt.Errorf("invalid state %q (valid: %s)", filter.TriageState, strings.Join(database.TriageStates, ", "))
+	}
+	for _, tag := range query["tag"] {
+		filter.Tags = append(filter.Tags, strings.ToLower(strings.TrimSpace(tag)))
+	}
+
+	if v := query.Get("regex"); v != "" {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid regex: %w", err)
+		}
+		filter.Regex = re
+	}
+
+	since, err := parseTime(query.Get("since"))
+	if err != nil {
+		return filter, fmt.Errorf("invalid since: %w", err)
+	}
+	until, err := parseTime(query.Get("until"))
+	if err != nil {
+		return filter, fmt.Errorf("invalid until: %w", err)
+	}
+
+	switch query.Get("time_field") {
+	case "", "first_seen":
+		filter.FirstSeenSince, filter.FirstSeenUntil = since, until
+	case "last_seen":
+		filter.LastSeenSince, filter.LastSeenUntil = since, until
+	default:
+		return filter, fmt.Errorf("invalid time_field %q (use first_seen or last_seen)", query.Get("time_field"))
+	}
+
+	return filter, nil
+}
+
+// parseTime accepts RFC 3339, a date, or a duration counted back from now
+// (24h, 7d).
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil {
+			return time.Now().AddDate(0, 0, -days), nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+	}
+	return time.Now().Add(-d), nil
+}
+
+// handleTimeline serves GET /subdomains/{subdomain}/timeline.
+func (s *Server) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/subdomains/")
+	subdomain, action, _ := strings.Cut(rest, "/")
+	if action != "timeline" || subdomain == "" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	subdomain = strings.ToLower(subdomain)
+	observations, err := s.db.QuerySubdomainTimeline(subdomain)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	changes, err := s.db.QueryStatusChanges(subdomain)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(observations) == 0 && len(changes) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("subdomain %s not found", subdomain))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"subdomain":    subdomain,
+		"observations": newObservationViews(observations),
+		"changes":      newStatusChangeViews(changes),
+	})
+}
+
+// handleDomain serves GET /domains/{domain}/scans and
+// GET /domains/{domain}/diff?from=&to=.
+func (s *Server) handleDomain(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/domains/")
+	domain, action, _ := strings.Cut(rest, "/")
+	domain = strings.ToLower(domain)
+	if domain == "" || (action != "scans" && action != "diff") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	if action == "scans" {
+		scans, err := s.db.QueryScans(domain)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, newScanViews(scans))
+		return
+	}
+
+	from, _ := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+	to, _ := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+	diff, err := s.db.DiffScans(domain, from, to)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"domain":  diff.Domain,
+		"from":    diff.From,
+		"to":      diff.To,
+		"added":   append([]string{}, diff.Added...),
+		"removed": append([]string{}, diff.Removed...),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Debugf("failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func methodNotAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+}
@@ -5,11 +5,14 @@ import (
 	"io"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/logging"
+	"github.com/samogod/samoscout/pkg/metrics"
+	"github.com/samogod/samoscout/pkg/tracing"
 	"strings"
 	"time"
 )
 
-var DebugLog func(string, ...interface{})
+var log = logging.Component("session")
 
 type Session struct {
 	Client *http.Client
@@ -22,8 +25,8 @@ type LoggingTransport struct {
 }
 
 func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	if DebugLog != nil {
-		DebugLog("requesting url: %s", req.URL.String())
+	if log.DebugEnabled() {
+		log.Debugf("requesting url: %s", req.URL.String())
 
 		if len(req.Header) > 0 {
 			var headers []string
@@ -33,33 +36,33 @@ func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 				}
 			}
 			if len(headers) > 0 {
-				DebugLog("request headers: %s", strings.Join(headers, " | "))
+				log.Debugf("request headers: %s", strings.Join(headers, " | "))
 			}
 		}
 	}
 
 	resp, err := t.Transport.RoundTrip(req)
 
-	if DebugLog != nil {
+	if log.DebugEnabled() {
 		sourceName := extractSourceName(req.URL.String())
 
 		if err != nil {
-			DebugLog("encountered an error with source %s: %v", sourceName, err)
+			log.Debugf("encountered an error with source %s: %v", sourceName, err)
 		} else {
-			DebugLog("response for %s: status code %d", req.URL.String(), resp.StatusCode)
+			log.Debugf("response for %s: status code %d", req.URL.String(), resp.StatusCode)
 
 			if contentType := resp.Header.Get("Content-Type"); contentType != "" {
-				DebugLog("response content-type: %s", contentType)
+				log.Debugf("response content-type: %s", contentType)
 			}
 
 			if resp.StatusCode >= 400 {
-				DebugLog("encountered an error with source %s: unexpected status code %d received from %s",
+				log.Debugf("encountered an error with source %s: unexpected status code %d received from %s",
 					sourceName, resp.StatusCode, req.URL.String())
 
 				if resp.Body != nil {
 					bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 500))
 					if readErr == nil && len(bodyBytes) > 0 {
-						DebugLog("error response body: %s", string(bodyBytes))
+						log.Debugf("error response body: %s", string(bodyBytes))
 					}
 				}
 			}
@@ -91,7 +94,7 @@ func New(cfg *config.Config) (*Session, error) {
 	}
 
 	var transport http.RoundTripper = baseTransport
-	if DebugLog != nil {
+	if log.DebugEnabled() {
 		transport = &LoggingTransport{Transport: baseTransport}
 	}
 
@@ -106,3 +109,16 @@ func New(cfg *config.Config) (*Session, error) {
 		Keys:   cfg.APIKeys,
 	}, nil
 }
+
+// ForSource returns a copy of the session whose requests are counted in the
+// metrics of the named source and traced as its spans.
+func (s *Session) ForSource(name string) *Session {
+	client := *s.Client
+	client.Transport = tracing.Transport(name, metrics.InstrumentTransport(name, client.Transport))
+
+	return &Session{
+		Client: &client,
+		Config: s.Config,
+		Keys:   s.Keys,
+	}
+}
@@ -0,0 +1,105 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/database"
+)
+
+func init() {
+	Register("database", func(cfg config.Sink, env Env) (Sink, error) {
+		if env.DB == nil || !env.DB.IsEnabled() {
+			return nil, fmt.Errorf("database sink requires database.enabled")
+		}
+		return NewDatabase(env.DB), nil
+	})
+}
+
+// Database merges every finished scan into the tracking database and stores
+// the resulting summary on the event. Probe results are kept until then so
+// tracking can record HTTP status and title changes.
+type Database struct {
+	db *database.DB
+
+	mu     sync.Mutex
+	probes map[string]map[string]database.HTTPProbe
+}
+
+func NewDatabase(db *database.DB) *Database {
+	return &Database{
+		db:     db,
+		probes: make(map[string]map[string]database.HTTPProbe),
+	}
+}
+
+func (d *Database) Name() string {
+	return "database"
+}
+
+func (d *Database) Handle(ctx context.Context, event *Event) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch event.Type {
+	case EventProbe:
+		d.addProbe(event.Domain, event.Probe)
+		return nil
+	case EventScanFinished:
+	default:
+		return nil
+	}
+
+	scan := event.Scan
+	summary, err := d.db.TrackSubdomains(event.Domain, scan.Subdomains, &database.ScanInfo{
+		StartedAt:        scan.StartedAt,
+		EndedAt:          scan.EndedAt,
+		Options:          scan.Options,
+		Sources:          scan.SourcesUsed,
+		SubdomainSources: scan.AllSources,
+		Partial:          scan.Partial,
+		SourceErrors:     scan.SourceErrors,
+		FailedSources:    scan.FailedSources,
+		Resolve:          scan.Resolve,
+		Imported:         scan.Imported,
+		Tool:             scan.Tool,
+		HTTP:             d.probes[event.Domain],
+	})
+	if err != nil {
+		return fmt.Errorf("failed to track subdomains: %w", err)
+	}
+
+	delete(d.probes, event.Domain)
+	scan.Track = summary
+	return nil
+}
+
+// addProbe keys probe results by the URL's hostname, since depending on the
+// httpx version "host" holds the IP. A host probed on several ports keeps the
+// first response that has a status.
+func (d *Database) addProbe(domain string, probe *ProbeEvent) {
+	u, err := url.Parse(probe.URL)
+	if err != nil || u.Hostname() == "" {
+		return
+	}
+	host := strings.ToLower(u.Hostname())
+
+	probes := d.probes[domain]
+	if probes == nil {
+		probes = make(map[string]database.HTTPProbe)
+		d.probes[domain] = probes
+	}
+	if existing, ok := probes[host]; ok && existing.Status != 0 {
+		return
+	}
+	probes[host] = database.HTTPProbe{Status: probe.StatusCode, Title: probe.Title}
+}
+
+// Close leaves the connection open; it belongs to the orchestrator.
+func (d *Database) Close() error {
+	return nil
+}
@@ -0,0 +1,205 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Options controls how the dispatcher delivers events to one sink. An empty
+// Events list subscribes the sink to every event type.
+type Options struct {
+	Retries int
+	Backoff time.Duration
+	Events  []EventType
+}
+
+// queueSize is how many events a sink may fall behind before Emit waits
+// for it.
+const queueSize = 1024
+
+// delivery is one queued event. A nil event only marks a point in the queue;
+// done, when set, is closed once the worker got past it.
+type delivery struct {
+	ctx   context.Context
+	event *Event
+	done  chan struct{}
+}
+
+type entry struct {
+	sink    Sink
+	options Options
+	queue   chan delivery
+	stopped chan struct{}
+
+	mu       sync.Mutex
+	failures int
+}
+
+func (e *entry) wants(t EventType) bool {
+	if len(e.options.Events) == 0 {
+		return true
+	}
+	for _, want := range e.options.Events {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher fans events out to every registered sink. Each sink has its own
+// queue and worker, so a slow or failing sink is retried on its own and never
+// holds up the others; once its retries are exhausted the error goes to
+// OnError, which may be called from several workers at once.
+//
+// scan_finished events are delivered in registration order, each sink after
+// the previous one handled it, and only once every sink caught up with the
+// events before it. Sinks registered after the database sink can therefore
+// read the tracking summary, and everything a scan emitted has been delivered
+// when Emit returns.
+type Dispatcher struct {
+	mu      sync.RWMutex
+	entries []*entry
+	closed  bool
+
+	OnError func(sink string, event *Event, err error)
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{}
+}
+
+func (d *Dispatcher) Add(s Sink, options Options) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	e := &entry{
+		sink:    s,
+		options: options,
+		queue:   make(chan delivery, queueSize),
+		stopped: make(chan struct{}),
+	}
+	d.entries = append(d.entries, e)
+	go d.run(e)
+}
+
+// Emit queues event for every sink that wants it. It only waits when a sink's
+// queue is full, and for scan_finished events until they are delivered.
+func (d *Dispatcher) Emit(ctx context.Context, event *Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		log.Debugf("dropping %s event for %s: sinks are closed", event.Type, event.Domain)
+		return
+	}
+
+	wait := event.Type == EventScanFinished
+	for _, e := range d.entries {
+		wanted := e.wants(event.Type)
+		if !wanted && !wait {
+			continue
+		}
+
+		job := delivery{ctx: ctx}
+		if wanted {
+			job.event = event
+		}
+		if wait {
+			job.done = make(chan struct{})
+		}
+
+		e.queue <- job
+		if wait {
+			<-job.done
+		}
+	}
+}
+
+// run delivers the queued events of one sink until its queue is closed.
+func (d *Dispatcher) run(e *entry) {
+	defer close(e.stopped)
+
+	for job := range e.queue {
+		if job.event != nil {
+			if err := d.deliver(job.ctx, e, job.event); err != nil {
+				e.mu.Lock()
+				e.failures++
+				e.mu.Unlock()
+				if d.OnError != nil {
+					d.OnError(e.sink.Name(), job.event, err)
+				}
+			}
+		}
+		if job.done != nil {
+			close(job.done)
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, e *entry, event *Event) error {
+	var err error
+	for attempt := 0; attempt <= e.options.Retries; attempt++ {
+		if attempt > 0 {
+			log.Debugf("retrying %s event for sink %s (attempt %d): %v", event.Type, e.sink.Name(), attempt+1, err)
+			select {
+			case <-time.After(e.options.Backoff * time.Duration(attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err = e.sink.Handle(ctx, event); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Failures returns how many events each sink failed to handle.
+func (d *Dispatcher) Failures() map[string]int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	failures := make(map[string]int)
+	for _, e := range d.entries {
+		e.mu.Lock()
+		if e.failures > 0 {
+			failures[e.sink.Name()] = e.failures
+		}
+		e.mu.Unlock()
+	}
+	return failures
+}
+
+// Close waits until every sink handled its queued events, then closes the
+// sinks. Events emitted after Close are dropped.
+func (d *Dispatcher) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+
+	for _, e := range d.entries {
+		close(e.queue)
+	}
+	for _, e := range d.entries {
+		<-e.stopped
+	}
+
+	var errs []error
+	for _, e := range d.entries {
+		if err := e.sink.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
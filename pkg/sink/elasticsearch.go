@@ -0,0 +1,221 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/active"
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/elastic"
+	"github.com/samogod/samoscout/pkg/metrics"
+)
+
+func init() {
+	Register("elasticsearch", func(cfg config.Sink, env Env) (Sink, error) {
+		return NewElasticsearch(env.Config.Elasticsearch, env.DB), nil
+	})
+}
+
+// Elasticsearch indexes the httpx results of a scan and, with
+// index_subdomains, one document per subdomain. Probe results are buffered
+// and indexed together when the scan finishes, after tracking, so subdomain
+// documents carry the scan id and tracked status.
+type Elasticsearch struct {
+	cfg config.Elasticsearch
+	db  *database.DB
+
+	mu     sync.Mutex
+	client *elastic.Client
+	probes map[string][]json.RawMessage
+}
+
+func NewElasticsearch(cfg config.Elasticsearch, db *database.DB) *Elasticsearch {
+	return &Elasticsearch{
+		cfg:    cfg,
+		db:     db,
+		probes: make(map[string][]json.RawMessage),
+	}
+}
+
+func (e *Elasticsearch) Name() string {
+	return "elasticsearch"
+}
+
+// connect creates the client on first use; a failed attempt is retried on
+// the next call.
+func (e *Elasticsearch) connect() (*elastic.Client, error) {
+	if e.client != nil {
+		return e.client, nil
+	}
+
+	client, err := elastic.New(elastic.Config{
+		URL:            e.cfg.URL,
+		Username:       e.cfg.Username,
+		Password:       e.cfg.Password,
+		Index:          e.cfg.Index,
+		SubdomainIndex: e.cfg.SubdomainIndex,
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.client = client
+	return client, nil
+}
+
+func (e *Elasticsearch) Handle(ctx context.Context, event *Event) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch event.Type {
+	case EventProbe:
+		if len(event.Probe.Raw) > 0 {
+			e.probes[event.Domain] = append(e.probes[event.Domain], event.Probe.Raw)
+		}
+		return nil
+	case EventScanFinished:
+	default:
+		return nil
+	}
+
+	probes := e.probes[event.Domain]
+	indexSubdomains := e.cfg.IndexSubdomains && len(event.Scan.Subdomains) > 0
+	if len(probes) == 0 && !indexSubdomains {
+		return nil
+	}
+
+	client, err := e.connect()
+	if err != nil {
+		return err
+	}
+
+	// document IDs are deterministic, so a retry after a partial failure
+	// overwrites what the first attempt indexed
+	if len(probes) > 0 {
+		stats, err := client.IndexHTTPXDocuments(ctx, probes, event.Scan.StartedAt)
+		if err != nil {
+			metrics.ElasticBulkError("httpx")
+			return err
+		}
+		if err := reportIndexStats("httpx", stats); err != nil {
+			event.Scan.AddError(err)
+		}
+	}
+
+	if indexSubdomains {
+		stats, err := client.IndexSubdomains(ctx, e.subdomainDocuments(event))
+		if err != nil {
+			metrics.ElasticBulkError("subdomains")
+			return err
+		}
+		if err := reportIndexStats("subdomains", stats); err != nil {
+			event.Scan.AddError(err)
+		}
+	}
+
+	delete(e.probes, event.Domain)
+
+	return nil
+}
+
+func (e *Elasticsearch) subdomainDocuments(event *Event) []elastic.SubdomainDocument {
+	scan := event.Scan
+
+	log.Debugf("resolving %d subdomains for elasticsearch documents", len(scan.Subdomains))
+	records := active.LookupRecords(scan.Subdomains, 50, 5*time.Second)
+
+	tracked := make(map[string]database.SubdomainRecord)
+	if e.db != nil && e.db.IsEnabled() {
+		rows, err := e.db.FindSubdomains(database.SubdomainFilter{Domain: event.Domain})
+		if err != nil {
+			log.Debugf("failed to load tracked subdomains for elasticsearch: %v", err)
+		}
+		for _, r := range rows {
+			tracked[r.Subdomain] = r
+		}
+	}
+
+	var scanID int64
+	if scan.Track != nil {
+		scanID = scan.Track.ScanID
+	}
+
+	docs := make([]elastic.SubdomainDocument, 0, len(scan.Subdomains))
+	for _, subdomain := range scan.Subdomains {
+		sources := scan.AllSources[subdomain]
+		doc := elastic.SubdomainDocument{
+			Timestamp:     scan.EndedAt,
+			Domain:        event.Domain,
+			Subdomain:     subdomain,
+			Sources:       sources,
+			Phase:         Phase(sources),
+			ScanID:        scanID,
+			ScanStartedAt: scan.StartedAt,
+			ScanEndedAt:   scan.EndedAt,
+		}
+
+		if dns, ok := records[subdomain]; ok {
+			doc.Resolves = true
+			doc.DNS = elastic.DNSRecords{A: dns.A, AAAA: dns.AAAA, CNAME: dns.CNAME}
+		}
+
+		if r, ok := tracked[subdomain]; ok {
+			firstSeen, lastSeen := r.FirstSeen, r.LastSeen
+			doc.Status = r.Status
+			doc.FirstSeen = &firstSeen
+			doc.LastSeen = &lastSeen
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs
+}
+
+func (e *Elasticsearch) Close() error {
+	return nil
+}
+
+// Phase reports which stage of the scan found a host: passive when any
+// passive source reported it, otherwise active or llm.
+func Phase(sources []string) string {
+	phase := "passive"
+	for _, s := range sources {
+		switch s {
+		case "active":
+			phase = "active"
+		case "llm":
+			if phase != "active" {
+				phase = "llm"
+			}
+		default:
+			return "passive"
+		}
+	}
+	return phase
+}
+
+// reportIndexStats logs the outcome of a bulk call, including a sample of
+// the reasons for rejected documents, and returns the rejections as an error
+// for the scan result. Rejections are not retried: they are mapping problems
+// that a second attempt would hit again.
+func reportIndexStats(what string, stats *elastic.IndexStats) error {
+	metrics.ElasticBulk(what, stats.Indexed, stats.Failed)
+
+	es := log.With("index", stats.Index)
+	if stats.Failed == 0 {
+		es.Infof("Indexed %d %s documents into index '%s'", stats.Indexed, what, stats.Index)
+		return nil
+	}
+
+	es.Warnf("Indexed %d %s documents into index '%s', %d failed", stats.Indexed, what, stats.Index, stats.Failed)
+	for _, reason := range stats.Failures {
+		es.Warnf("  %s", reason)
+	}
+	return fmt.Errorf("elasticsearch: %d of %d %s documents failed to index into '%s': %s",
+		stats.Failed, stats.Indexed+stats.Failed, what, stats.Index, strings.Join(stats.Failures, "; "))
+}
@@ -0,0 +1,118 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/samogod/samoscout/pkg/config"
+)
+
+func init() {
+	Register("file", func(cfg config.Sink, env Env) (Sink, error) {
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("file sink requires a path")
+		}
+		return NewFile(cfg.Path, strings.EqualFold(cfg.Format, "jsonl") || strings.EqualFold(cfg.Format, "json")), nil
+	})
+}
+
+// File writes found subdomains to a file as plain lines or JSON lines, the
+// latter with artifacts, like the stdout sink. A "{domain}" placeholder in
+// the path gives every domain its own file. Files are truncated when first
+// opened and stay open until Close, so a multi domain run with a fixed path
+// collects every domain.
+type File struct {
+	path string
+	json bool
+
+	mu    sync.Mutex
+	files map[string]*os.File
+}
+
+func NewFile(path string, jsonFormat bool) *File {
+	return &File{path: path, json: jsonFormat, files: make(map[string]*os.File)}
+}
+
+func (f *File) Name() string {
+	return "file:" + f.path
+}
+
+func (f *File) open(domain string) (*os.File, error) {
+	path := strings.ReplaceAll(f.path, "{domain}", domain)
+	if file, ok := f.files[path]; ok {
+		return file, nil
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %w", err)
+	}
+	f.files[path] = file
+	return file, nil
+}
+
+func (f *File) Handle(ctx context.Context, event *Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch event.Type {
+	case EventSubdomain:
+		file, err := f.open(event.Domain)
+		if err != nil {
+			return err
+		}
+		if err := writeSubdomain(file, event, f.json); err != nil {
+			return fmt.Errorf("failed to write to file: %w", err)
+		}
+
+	case EventArtifact:
+		if !f.json {
+			return nil
+		}
+		file, err := f.open(event.Domain)
+		if err != nil {
+			return err
+		}
+		if err := writeArtifact(file, event); err != nil {
+			return fmt.Errorf("failed to write to file: %w", err)
+		}
+
+	case EventScanFinished:
+		file, err := f.open(event.Domain)
+		if err != nil {
+			return err
+		}
+		if f.json {
+			if _, err := fmt.Fprintf(file, "\n# Found %d subdomains for %s in %v\n",
+				len(event.Scan.Subdomains), event.Domain, event.Scan.EndedAt.Sub(event.Scan.StartedAt)); err != nil {
+				return fmt.Errorf("failed to write summary to file: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var firstErr error
+	for path, file := range f.files {
+		if err := file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(f.files, path)
+	}
+	return firstErr
+}
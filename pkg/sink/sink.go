@@ -0,0 +1,164 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/active"
+	"github.com/samogod/samoscout/pkg/config"
+	"github.com/samogod/samoscout/pkg/database"
+	"github.com/samogod/samoscout/pkg/logging"
+)
+
+var log = logging.Component("sink")
+
+type EventType string
+
+const (
+	EventSubdomain    EventType = "subdomain"
+	EventProbe        EventType = "probe"
+	EventArtifact     EventType = "artifact"
+	EventScanFinished EventType = "scan_finished"
+)
+
+// Event is one result streamed out of a scan. Exactly one of Subdomain,
+// Probe, Artifact and Scan is set, matching Type.
+type Event struct {
+	Type      EventType       `json:"type"`
+	Domain    string          `json:"domain"`
+	Time      time.Time       `json:"time"`
+	Subdomain *SubdomainEvent `json:"subdomain,omitempty"`
+	Probe     *ProbeEvent     `json:"probe,omitempty"`
+	Artifact  *Artifact       `json:"artifact,omitempty"`
+	Scan      *ScanEvent      `json:"scan,omitempty"`
+}
+
+// SubdomainEvent is sent once per host, when the scan first finds it.
+type SubdomainEvent struct {
+	Host   string `json:"host"`
+	Source string `json:"source"`
+	Phase  string `json:"phase"`
+}
+
+type ProbeEvent struct {
+	active.HttpxResult
+}
+
+// Artifact is a value a source reported besides a hostname: an "ip" Host
+// resolves to, a "url" on Host, or a "related_domain" of the scanned domain
+// Host. It is sent once, with the source that reported it first; the scan
+// result lists every source.
+type Artifact struct {
+	Type    string   `json:"type"`
+	Value   string   `json:"value"`
+	Host    string   `json:"host"`
+	Sources []string `json:"sources"`
+}
+
+// ScanEvent closes a scan. Track is filled in by the database sink, so sinks
+// registered after it can read the tracking summary. Imported marks hosts
+// found outside a samoscout scan, by Tool.
+type ScanEvent struct {
+	StartedAt        time.Time                            `json:"started_at"`
+	EndedAt          time.Time                            `json:"ended_at"`
+	Subdomains       []string                             `json:"subdomains"`
+	SubdomainSources map[string]string                    `json:"-"`
+	AllSources       map[string][]string                  `json:"sources"`
+	SourcesUsed      []string                             `json:"sources_used"`
+	WildcardZones    []string                             `json:"wildcard_zones,omitempty"`
+	Artifacts        []Artifact                           `json:"artifacts,omitempty"`
+	Options          string                               `json:"-"`
+	Partial          bool                                 `json:"partial"`
+	SourceErrors     int                                  `json:"source_errors"`
+	FailedSources    []string                             `json:"failed_sources,omitempty"`
+	Resolve          func(hosts []string) map[string]bool `json:"-"`
+	Imported         bool                                 `json:"imported,omitempty"`
+	Tool             string                               `json:"tool,omitempty"`
+	Track            *database.TrackSummary               `json:"track,omitempty"`
+
+	mu     sync.Mutex
+	errors []error
+}
+
+// AddError records a problem a sink hit with the scan that the scan result
+// should report, such as documents an index rejected.
+func (s *ScanEvent) AddError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, err)
+}
+
+// Errors returns what sinks recorded with AddError.
+func (s *ScanEvent) Errors() []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]error(nil), s.errors...)
+}
+
+// Sink is a destination for scan events. Handle may be called again with the
+// same event after it returned an error, so it must not duplicate work that
+// already succeeded.
+type Sink interface {
+	Name() string
+	Handle(ctx context.Context, event *Event) error
+	Close() error
+}
+
+// Env gives sink factories access to the shared resources of the orchestrator.
+type Env struct {
+	Config *config.Config
+	DB     *database.DB
+}
+
+type Factory func(cfg config.Sink, env Env) (Sink, error)
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a sink type available to the "sinks" config section.
+func Register(kind string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[kind] = factory
+}
+
+func Types() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	types := make([]string, 0, len(registry))
+	for kind := range registry {
+		types = append(types, kind)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func New(cfg config.Sink, env Env) (Sink, error) {
+	registryMu.Lock()
+	factory, ok := registry[strings.ToLower(cfg.Type)]
+	registryMu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown sink type %q (valid: %s)", cfg.Type, strings.Join(Types(), ", "))
+	}
+	return factory(cfg, env)
+}
+
+// OptionsFromConfig converts the delivery settings of a sink config entry.
+func OptionsFromConfig(cfg config.Sink) Options {
+	options := Options{
+		Retries: cfg.Retries,
+		Backoff: time.Duration(cfg.Backoff) * time.Second,
+	}
+	for _, e := range cfg.Events {
+		options.Events = append(options.Events, EventType(strings.ToLower(e)))
+	}
+	return options
+}
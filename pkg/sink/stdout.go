@@ -0,0 +1,104 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/samogod/samoscout/pkg/config"
+)
+
+func init() {
+	Register("stdout", func(cfg config.Sink, env Env) (Sink, error) {
+		return NewStdout(strings.EqualFold(cfg.Format, "jsonl") || strings.EqualFold(cfg.Format, "json")), nil
+	})
+}
+
+// subdomainLine is the JSONL record samoscout prints and writes with -json.
+type subdomainLine struct {
+	Host   string `json:"host"`
+	Input  string `json:"input"`
+	Source string `json:"source"`
+}
+
+// artifactLine is the JSONL record of an IP, URL or related domain. Only
+// these lines have a type.
+type artifactLine struct {
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	Host   string `json:"host"`
+	Input  string `json:"input"`
+	Source string `json:"source"`
+}
+
+// Stdout streams every found subdomain as it is discovered, one per line or
+// as JSON lines. JSON lines also carry the IPs, URLs and related domains
+// sources reported.
+type Stdout struct {
+	w    io.Writer
+	json bool
+}
+
+func NewStdout(jsonFormat bool) *Stdout {
+	return &Stdout{w: os.Stdout, json: jsonFormat}
+}
+
+func (s *Stdout) Name() string {
+	return "stdout"
+}
+
+func (s *Stdout) Handle(ctx context.Context, event *Event) error {
+	switch event.Type {
+	case EventSubdomain:
+		return writeSubdomain(s.w, event, s.json)
+	case EventArtifact:
+		if s.json {
+			return writeArtifact(s.w, event)
+		}
+	}
+	return nil
+}
+
+func (s *Stdout) Close() error {
+	return nil
+}
+
+func writeSubdomain(w io.Writer, event *Event, jsonFormat bool) error {
+	if !jsonFormat {
+		_, err := fmt.Fprintln(w, event.Subdomain.Host)
+		return err
+	}
+
+	jsonBytes, err := json.Marshal(subdomainLine{
+		Host:   event.Subdomain.Host,
+		Input:  event.Domain,
+		Source: event.Subdomain.Source,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(jsonBytes))
+	return err
+}
+
+func writeArtifact(w io.Writer, event *Event) error {
+	line := artifactLine{
+		Type:  event.Artifact.Type,
+		Value: event.Artifact.Value,
+		Host:  event.Artifact.Host,
+		Input: event.Domain,
+	}
+	if len(event.Artifact.Sources) > 0 {
+		line.Source = event.Artifact.Sources[0]
+	}
+
+	jsonBytes, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(jsonBytes))
+	return err
+}
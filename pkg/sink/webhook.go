@@ -0,0 +1,167 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/samogod/samoscout/pkg/config"
+)
+
+func init() {
+	Register("webhook", func(cfg config.Sink, env Env) (Sink, error) {
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("webhook sink requires a url")
+		}
+		return NewWebhook(cfg.URL, cfg.Headers, cfg.BatchSize, time.Duration(cfg.Timeout)*time.Second), nil
+	})
+}
+
+const (
+	// webhookMaxBatches caps the pending events at this many batches while
+	// the endpoint is down; the oldest events are dropped first.
+	webhookMaxBatches = 10
+	// webhookMaxFailures is how many flushes in a row may fail before the
+	// oldest batch is given up.
+	webhookMaxFailures = 5
+	webhookMaxBackoff  = 5 * time.Minute
+)
+
+// Webhook POSTs events as JSON ({"events": [...]}) in batches of batchSize.
+// Every scan_finished event flushes the pending batch, so each scan ends with
+// a delivery. After a failed flush the next attempt waits, doubling the wait
+// up to webhookMaxBackoff, and events keep queueing up to webhookMaxBatches
+// batches.
+type Webhook struct {
+	url       string
+	headers   map[string]string
+	batchSize int
+	client    *http.Client
+
+	mu       sync.Mutex
+	pending  []*Event
+	last     *Event
+	failures int
+	retryAt  time.Time
+}
+
+func NewWebhook(url string, headers map[string]string, batchSize int, timeout time.Duration) *Webhook {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Webhook{
+		url:       url,
+		headers:   headers,
+		batchSize: batchSize,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (w *Webhook) Name() string {
+	return "webhook:" + w.url
+}
+
+func (w *Webhook) Handle(ctx context.Context, event *Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// a retried event is already queued
+	if event != w.last {
+		w.pending = append(w.pending, event)
+		w.last = event
+		if len(w.pending) > w.batchSize*webhookMaxBatches {
+			w.drop(w.batchSize)
+		}
+	}
+
+	if len(w.pending) < w.batchSize && event.Type != EventScanFinished {
+		return nil
+	}
+	// the events stay pending until the backoff is over
+	if time.Now().Before(w.retryAt) {
+		return nil
+	}
+
+	if err := w.flush(ctx); err != nil {
+		w.failed()
+		return err
+	}
+	w.failures = 0
+	w.retryAt = time.Time{}
+	return nil
+}
+
+// failed backs off after a failed flush, and gives up the oldest batch each
+// time once too many flushes in a row failed.
+func (w *Webhook) failed() {
+	w.failures++
+	if w.failures >= webhookMaxFailures {
+		w.drop(min(w.batchSize, len(w.pending)))
+	}
+
+	backoff := time.Second << min(w.failures, 9)
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	w.retryAt = time.Now().Add(backoff)
+}
+
+// drop discards the n oldest pending events.
+func (w *Webhook) drop(n int) {
+	if n <= 0 {
+		return
+	}
+	log.Warnf("webhook %s: dropping %d undelivered event(s)", w.url, n)
+	w.pending = append([]*Event(nil), w.pending[n:]...)
+}
+
+func (w *Webhook) flush(ctx context.Context) error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"events": w.pending})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	w.pending = nil
+	return nil
+}
+
+func (w *Webhook) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.client.Timeout)
+	defer cancel()
+	return w.flush(ctx)
+}
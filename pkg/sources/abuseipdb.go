@@ -84,14 +84,9 @@ func (a *AbuseIPDB) Run(ctx context.Context, domain string, s *session.Session)
 			}
 
 			// Construct full domain
-			fullDomain := subdomain + "." + domain
-			hostname := strings.TrimSpace(strings.ToLower(fullDomain))
-
-			// Validate and send
-			if hostname != "" && hostname != domain &&
-				strings.HasSuffix(hostname, "."+domain) &&
-				a.isValidHostname(hostname) &&
-				!seen[hostname] {
+			hostname := subdomain + "." + domain
+
+			if !seen[hostname] {
 				seen[hostname] = true
 
 				select {
@@ -105,44 +100,3 @@ func (a *AbuseIPDB) Run(ctx context.Context, domain string, s *session.Session)
 
 	return results
 }
-
-// isValidHostname validates a hostname
-func (a *AbuseIPDB) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	// Check for leading or trailing dots
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	// Must contain at least one dot
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	// Check for valid characters
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') ||
-			(char >= '0' && char <= '9') ||
-			char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	// Check for leading or trailing hyphens
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
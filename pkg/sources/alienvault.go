@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type AlienVault struct{}
@@ -15,7 +14,9 @@ type AlienVaultResponse struct {
 	Detail     string `json:"detail"`
 	Error      string `json:"error"`
 	PassiveDNS []struct {
-		Hostname string `json:"hostname"`
+		Hostname   string `json:"hostname"`
+		Address    string `json:"address"`
+		RecordType string `json:"record_type"`
 	} `json:"passive_dns"`
 }
 
@@ -64,9 +65,9 @@ func (a *AlienVault) Run(ctx context.Context, domain string, s *session.Session)
 
 		seen := make(map[string]bool)
 		for _, record := range alienvaultResp.PassiveDNS {
-			hostname := strings.TrimSpace(strings.ToLower(record.Hostname))
+			hostname := record.Hostname
 			
-			if hostname != "" && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 				
 				select {
@@ -75,6 +76,14 @@ func (a *AlienVault) Run(ctx context.Context, domain string, s *session.Session)
 					return
 				}
 			}
+
+			if (record.RecordType == "A" || record.RecordType == "AAAA") && record.Address != "" {
+				select {
+				case results <- Result{Source: a.Name(), Value: record.Address, Host: hostname, Type: "ip"}:
+				case <-ctx.Done():
+					return
+				}
+			}
 		}
 	}()
 
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type Anubis struct{}
@@ -51,9 +50,9 @@ func (a *Anubis) Run(ctx context.Context, domain string, s *session.Session) <-c
 
 		seen := make(map[string]bool)
 		for _, subdomain := range subdomains {
-			hostname := strings.TrimSpace(strings.ToLower(subdomain))
+			hostname := subdomain
 
-			if hostname != "" && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 
 				select {
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type BeVigil struct{}
@@ -72,9 +71,9 @@ func (b *BeVigil) Run(ctx context.Context, domain string, s *session.Session) <-
 
 		seen := make(map[string]bool)
 		for _, subdomain := range bevigilResp.Subdomains {
-			hostname := strings.TrimSpace(strings.ToLower(subdomain))
+			hostname := subdomain
 			
-			if hostname != "" && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 				
 				select {
@@ -91,9 +91,9 @@ func (b *BufferOver) Run(ctx context.Context, domain string, s *session.Session)
 		for _, subdomain := range allSubdomains {
 			parts := strings.Fields(subdomain)
 			for _, part := range parts {
-				hostname := strings.TrimSpace(strings.ToLower(part))
+				hostname := part
 				
-				if hostname != "" && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true
 					
 					select {
@@ -85,11 +85,20 @@ func (b *BuiltWith) Run(ctx context.Context, domain string, s *session.Session)
 		seen := make(map[string]bool)
 		for _, result := range builtwithResp.Results {
 			for _, path := range result.Result.Paths {
+				if path.Domain != "" && !strings.EqualFold(path.Domain, domain) && !seen[path.Domain] {
+					seen[path.Domain] = true
+
+					select {
+					case results <- Result{Source: b.Name(), Value: path.Domain, Host: domain, Type: "related_domain"}:
+					case <-ctx.Done():
+						return
+					}
+				}
+
 				if path.SubDomain != "" && path.Domain != "" {
 					hostname := fmt.Sprintf("%s.%s", path.SubDomain, path.Domain)
-					hostname = strings.TrimSpace(strings.ToLower(hostname))
 					
-					if hostname != "" && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+					if !seen[hostname] {
 						seen[hostname] = true
 						
 						select {
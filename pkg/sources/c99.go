@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type C99 struct{}
@@ -86,9 +85,9 @@ func (c *C99) Run(ctx context.Context, domain string, s *session.Session) <-chan
 
 		seen := make(map[string]bool)
 		for _, data := range c99Resp.Subdomains {
-			hostname := strings.TrimSpace(strings.ToLower(data.Subdomain))
+			hostname := data.Subdomain
 			
-			if !strings.HasPrefix(hostname, ".") && hostname != "" && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 				
 				select {
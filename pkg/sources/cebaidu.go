@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type Cebaidu struct{}
@@ -68,7 +67,7 @@ func (c *Cebaidu) Run(ctx context.Context, domain string, s *session.Session) <-
 
 		seen := make(map[string]bool)
 		for _, domainResult := range response.Data {
-			hostname := strings.TrimSpace(strings.ToLower(domainResult.Domain))
+			hostname := domainResult.Domain
 			
 			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
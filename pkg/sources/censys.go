@@ -8,7 +8,6 @@ import (
 	"net/url"
 	"github.com/samogod/samoscout/pkg/session"
 	"strconv"
-	"strings"
 )
 
 type Censys struct{}
@@ -128,13 +127,13 @@ func (c *Censys) Run(ctx context.Context, domain string, s *session.Session) <-c
 			seen := make(map[string]bool)
 			for _, hit := range censysResp.Result.Hits {
 				for _, name := range hit.Names {
-					hostname := strings.TrimSpace(strings.ToLower(name))
+					hostname := name
 					
-					if hostname != "" && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+					if hostname != "" && !seen[hostname] {
 						seen[hostname] = true
 						
 						select {
-						case results <- Result{Source: c.Name(), Value: hostname, Type: "subdomain"}:
+						case results <- Result{Source: c.Name(), Value: hostname, Type: nameType(hostname)}:
 						case <-ctx.Done():
 							return
 						}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -117,13 +116,13 @@ func (c *CertSpotter) makeRequest(ctx context.Context, url string, s *session.Se
 func (c *CertSpotter) processResults(response []CertSpotterObject, domain string, seen map[string]bool, results chan<- Result) {
 	for _, cert := range response {
 		for _, dnsName := range cert.DNSNames {
-			hostname := strings.TrimSpace(strings.ToLower(dnsName))
+			hostname := dnsName
 			
-			if hostname != "" && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 				
 				select {
-				case results <- Result{Source: c.Name(), Value: hostname, Type: "subdomain"}:
+				case results <- Result{Source: c.Name(), Value: hostname, Type: nameType(hostname)}:
 				default:
 				}
 			}
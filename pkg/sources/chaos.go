@@ -73,16 +73,14 @@ func (c *Chaos) Run(ctx context.Context, domain string, s *session.Session) <-ch
 
 		seen := make(map[string]bool)
 		for _, subdomain := range chaosResp.Subdomains {
-			cleanSub := strings.TrimSpace(strings.ToLower(subdomain))
-			
 			var fullSubdomain string
-			if strings.Contains(cleanSub, ".") {
-				fullSubdomain = cleanSub
+			if strings.Contains(subdomain, ".") {
+				fullSubdomain = subdomain
 			} else {
-				fullSubdomain = fmt.Sprintf("%s.%s", cleanSub, domain)
+				fullSubdomain = fmt.Sprintf("%s.%s", subdomain, domain)
 			}
 			
-			if fullSubdomain != "" && fullSubdomain != domain && strings.HasSuffix(fullSubdomain, "."+domain) && !seen[fullSubdomain] {
+			if subdomain != "" && !seen[fullSubdomain] {
 				seen[fullSubdomain] = true
 
 				select {
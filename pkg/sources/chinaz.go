@@ -7,7 +7,6 @@ import (
 	"io"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type Chinaz struct{}
@@ -95,9 +94,9 @@ func (c *Chinaz) Run(ctx context.Context, domain string, s *session.Session) <-c
 
 		seen := make(map[string]bool)
 		for _, subdomain := range chinazResp.Result.ContributingSubdomainList {
-			hostname := strings.TrimSpace(strings.ToLower(subdomain.DataUrl))
+			hostname := subdomain.DataUrl
 			
-			if hostname != "" && hostname != domain && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 
 				select {
@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"regexp"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 	"time"
 )
 
@@ -303,9 +302,9 @@ func (cf *Cloudflare) processDNSResults(dnsResp *CloudflareDNSResponse, domain s
 	for _, record := range dnsResp.Result {
 		matches := subdomainPattern.FindAllString(record.Name, -1)
 		for _, match := range matches {
-			subdomain := strings.TrimSpace(strings.ToLower(match))
+			subdomain := match
 			
-			if subdomain != "" && subdomain != domain && !seen[subdomain] {
+			if subdomain != "" && !seen[subdomain] {
 				seen[subdomain] = true
 				
 				select {
@@ -319,9 +318,9 @@ func (cf *Cloudflare) processDNSResults(dnsResp *CloudflareDNSResponse, domain s
 		if record.Type == "CNAME" {
 			matches = subdomainPattern.FindAllString(record.Content, -1)
 			for _, match := range matches {
-				subdomain := strings.TrimSpace(strings.ToLower(match))
+				subdomain := match
 				
-				if subdomain != "" && subdomain != domain && !seen[subdomain] {
+				if subdomain != "" && !seen[subdomain] {
 					seen[subdomain] = true
 					
 					select {
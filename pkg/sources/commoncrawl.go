@@ -161,6 +161,15 @@ func (c *CommonCrawl) searchInIndex(ctx context.Context, apiURL, domain string,
 				}
 
 				
+				if rawURL := c.extractURL(line); rawURL != "" && !seen[rawURL] {
+					seen[rawURL] = true
+					select {
+					case results <- Result{Source: c.Name(), Value: rawURL, Type: "url"}:
+					case <-ctx.Done():
+						return
+					}
+				}
+
 				line, _ = url.QueryUnescape(line)
 				
 				
@@ -240,77 +249,38 @@ func (c *CommonCrawl) extractHostnamesFromPart(part, domain string) []string {
 
 
 func (c *CommonCrawl) cleanHostname(raw, domain string) string {
-	hostname := strings.ToLower(strings.TrimSpace(raw))
-	
-	
-	if strings.Contains(hostname, "://") {
-		parts := strings.Split(hostname, "://")
-		if len(parts) > 1 {
-			hostname = parts[1]
-		}
-	}
+	hostname := strings.TrimSpace(raw)
 	
 	
 	hostname = strings.TrimPrefix(hostname, "25")
-	hostname = strings.TrimPrefix(hostname, "2f")
-	
-	
-	if slashIndex := strings.Index(hostname, "/"); slashIndex > 0 {
-		hostname = hostname[:slashIndex]
-	}
-	if questionIndex := strings.Index(hostname, "?"); questionIndex > 0 {
-		hostname = hostname[:questionIndex]
-	}
-	
-	
-	if colonIndex := strings.LastIndex(hostname, ":"); colonIndex > 0 {
-		if portPart := hostname[colonIndex+1:]; isNumeric(portPart) {
-			hostname = hostname[:colonIndex]
-		}
+	if strings.HasPrefix(strings.ToLower(hostname), "2f") {
+		hostname = hostname[2:]
 	}
 	
 	
 	hostname = strings.Trim(hostname, ".,;:!?()[]{}\"'/\\")
 	
 	
-	if hostname != "" && hostname != domain && 
-	   strings.HasSuffix(hostname, "."+domain) && 
-	   isValidHostname(hostname) {
-		return hostname
+	if !strings.Contains(strings.ToLower(hostname), "."+domain) {
+		return ""
 	}
 	
-	return ""
+	return hostname
 }
 
-
-func isNumeric(s string) bool {
-	for _, r := range s {
-		if r < '0' || r > '9' {
-			return false
-		}
+// extractURL returns the captured URL of an index line, which reads
+// "<urlkey> <timestamp> {json}".
+func (c *CommonCrawl) extractURL(line string) string {
+	i := strings.Index(line, "{")
+	if i < 0 {
+		return ""
 	}
-	return len(s) > 0
-}
 
-
-func isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
+	var record struct {
+		URL string `json:"url"`
 	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
+	if err := json.Unmarshal([]byte(line[i:]), &record); err != nil {
+		return ""
 	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	return true
+	return record.URL
 }
@@ -113,12 +113,11 @@ func (c *Crtsh) getSubdomainsFromSQL(ctx context.Context, domain string, results
 		
 		subdomains := strings.Split(data, "\n")
 		for _, subdomain := range subdomains {
-			cleanSub := c.extractSubdomain(subdomain, domain)
-			if cleanSub != "" && !seen[cleanSub] {
-				seen[cleanSub] = true
+			if subdomain != "" && !seen[subdomain] {
+				seen[subdomain] = true
 				
 				select {
-				case results <- Result{Source: c.Name(), Value: cleanSub, Type: "subdomain"}:
+				case results <- Result{Source: c.Name(), Value: subdomain, Type: nameType(subdomain)}:
 				case <-ctx.Done():
 					return count
 				}
@@ -168,12 +167,11 @@ func (c *Crtsh) getSubdomainsFromHTTP(ctx context.Context, domain string, s *ses
 		
 		subdomains := strings.Split(crtResult.NameValue, "\n")
 		for _, subdomain := range subdomains {
-			cleanSub := c.extractSubdomain(subdomain, domain)
-			if cleanSub != "" && !seen[cleanSub] {
-				seen[cleanSub] = true
+			if subdomain != "" && !seen[subdomain] {
+				seen[subdomain] = true
 
 				select {
-				case results <- Result{Source: c.Name(), Value: cleanSub, Type: "subdomain"}:
+				case results <- Result{Source: c.Name(), Value: subdomain, Type: nameType(subdomain)}:
 				case <-ctx.Done():
 					return
 				}
@@ -181,48 +179,3 @@ func (c *Crtsh) getSubdomainsFromHTTP(ctx context.Context, domain string, s *ses
 		}
 	}
 }
-
-
-func (c *Crtsh) extractSubdomain(raw, domain string) string {
-	
-	subdomain := strings.TrimSpace(raw)
-	subdomain = strings.ToLower(subdomain)
-	
-	
-	subdomain = strings.TrimPrefix(subdomain, "*.")
-	
-	
-	if subdomain == "" || subdomain == domain || !c.isValidSubdomain(subdomain) {
-		return ""
-	}
-	
-	
-	if !strings.HasSuffix(subdomain, "."+domain) {
-		return ""
-	}
-	
-	return subdomain
-}
-
-
-func (c *Crtsh) isValidSubdomain(subdomain string) bool {
-	if len(subdomain) == 0 || len(subdomain) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(subdomain, ".") || strings.HasSuffix(subdomain, ".") {
-		return false
-	}
-
-	if strings.Contains(subdomain, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(subdomain, ".") {
-		return false
-	}
-
-	return true
-}
@@ -83,10 +83,10 @@ func (d *DigiCert) extractSubdomains(content, domain string, seen map[string]boo
 	
 	for _, match := range matches {
 		if len(match) > 1 {
-			hostname := strings.TrimSpace(strings.ToLower(match[1]))
+			hostname := match[1]
 			
 			
-			if d.isValidHostname(hostname) && !seen[hostname] {
+			if !seen[hostname] {
 				seen[hostname] = true
 				subdomains = append(subdomains, hostname)
 			}
@@ -100,10 +100,10 @@ func (d *DigiCert) extractSubdomains(content, domain string, seen map[string]boo
 		
 		for _, match := range generalMatches {
 			if len(match) > 1 {
-				hostname := strings.TrimSpace(strings.ToLower(match[1]))
+				hostname := match[1]
 				
 				
-				if strings.Contains(hostname, domain) && d.isValidHostname(hostname) && !seen[hostname] {
+				if strings.Contains(strings.ToLower(hostname), domain) && !seen[hostname] {
 					seen[hostname] = true
 					subdomains = append(subdomains, hostname)
 				}
@@ -113,42 +113,3 @@ func (d *DigiCert) extractSubdomains(content, domain string, seen map[string]boo
 	
 	return subdomains
 }
-
-
-func (d *DigiCert) isValidHostname(hostname string) bool {
-	if hostname == "" {
-		return false
-	}
-	
-	
-	if len(hostname) < 3 {
-		return false
-	}
-	
-	
-	if strings.Contains(hostname, " ") || strings.Contains(hostname, "\t") || strings.Contains(hostname, "\n") {
-		return false
-	}
-	
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-	
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-	
-	
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-	
-	
-	if strings.Contains(hostname, "*") {
-		return false
-	}
-	
-	return true
-}
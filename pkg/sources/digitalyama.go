@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -97,10 +96,10 @@ func (d *DigitalYama) Run(ctx context.Context, domain string, s *session.Session
 		
 		seen := make(map[string]bool)
 		for _, subdomain := range digitalYamaResp.Subdomains {
-			hostname := strings.TrimSpace(strings.ToLower(subdomain))
+			hostname := subdomain
 			
 			
-			if hostname != "" && hostname != domain && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 
 				select {
@@ -147,76 +147,12 @@ func (d *Digitorus) splitBySeparators(text string) []string {
 
 
 func (d *Digitorus) cleanHostname(raw, domain string) string {
-	hostname := strings.ToLower(strings.TrimSpace(raw))
+	hostname := strings.Trim(strings.TrimSpace(raw), ".,;:!?()[]{}\"'/\\")
 	
 	
-	hostname = strings.TrimPrefix(hostname, ".")
-	
-	
-	hostname = strings.Trim(hostname, ".,;:!?()[]{}\"'/\\*")
-	
-	
-	if strings.Contains(hostname, "://") {
-		parts := strings.Split(hostname, "://")
-		if len(parts) > 1 {
-			hostname = parts[1]
-		}
-	}
-	
-	
-	if slashIndex := strings.Index(hostname, "/"); slashIndex > 0 {
-		hostname = hostname[:slashIndex]
-	}
-	if questionIndex := strings.Index(hostname, "?"); questionIndex > 0 {
-		hostname = hostname[:questionIndex]
-	}
-	
-	
-	if colonIndex := strings.LastIndex(hostname, ":"); colonIndex > 0 {
-		if portPart := hostname[colonIndex+1:]; d.isNumeric(portPart) {
-			hostname = hostname[:colonIndex]
-		}
-	}
-	
-	
-	if hostname != "" && hostname != domain && 
-	   strings.HasSuffix(hostname, "."+domain) && 
-	   d.isValidHostname(hostname) {
-		return hostname
-	}
-	
-	return ""
-}
-
-
-func (d *Digitorus) isNumeric(s string) bool {
-	for _, r := range s {
-		if r < '0' || r > '9' {
-			return false
-		}
-	}
-	return len(s) > 0
-}
-
-
-func (d *Digitorus) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
+	if !strings.Contains(strings.ToLower(hostname), "."+domain) {
+		return ""
 	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
 	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	return true
+	return hostname
 }
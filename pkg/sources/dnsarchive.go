@@ -88,10 +88,9 @@ func (d *DNSArchive) Run(ctx context.Context, domain string, s *session.Session)
 		seen := make(map[string]bool)
 		for _, record := range DNSArchiveResp {
 
-			hostname := strings.TrimSuffix(record.Domain, ".")
-			hostname = strings.TrimSpace(strings.ToLower(hostname))
+			hostname := record.Domain
 
-			if hostname != "" && hostname != domain && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 
 				select {
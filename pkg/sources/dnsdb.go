@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"github.com/samogod/samoscout/pkg/session"
 	"strconv"
-	"strings"
 )
 
 
@@ -186,13 +185,10 @@ func (d *DNSDB) streamSubdomains(ctx context.Context, domain string, s *session.
 			if respCond == "" || respCond == "ongoing" {
 				
 				if response.Obj.Name != "" {
-					hostname := strings.TrimSuffix(response.Obj.Name, ".")
-					hostname = strings.ToLower(strings.TrimSpace(hostname))
+					hostname := response.Obj.Name
 					
 					
-					if hostname != "" && hostname != domain && 
-					   strings.HasSuffix(hostname, "."+domain) && 
-					   !seen[hostname] {
+					if !seen[hostname] {
 						seen[hostname] = true
 						totalResults++
 						
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -87,10 +86,10 @@ func (d *DNSDumpster) Run(ctx context.Context, domain string, s *session.Session
 		seen := make(map[string]bool)
 		
 		for _, record := range allRecords {
-			hostname := strings.TrimSpace(strings.ToLower(record.Host))
+			hostname := record.Host
 			
 			
-			if hostname != "" && hostname != domain && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 
 				select {
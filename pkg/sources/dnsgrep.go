@@ -84,9 +84,9 @@ func (d *Dnsgrep) extractSubdomains(responseText, domain string, results chan<-
 	matches := subdomainPattern.FindAllString(responseText, -1)
 	
 	for _, match := range matches {
-		subdomain := strings.TrimSpace(strings.ToLower(match))
+		subdomain := match
 		
-		if subdomain != "" && subdomain != domain && !seen[subdomain] {
+		if subdomain != "" && !seen[subdomain] {
 			seen[subdomain] = true
 			
 			select {
@@ -101,9 +101,9 @@ func (d *Dnsgrep) extractSubdomains(responseText, domain string, results chan<-
 	
 	for _, match := range jsonMatches {
 		if len(match) >= 2 {
-			subdomain := strings.TrimSpace(strings.ToLower(match[1]))
+			subdomain := match[1]
 			
-			if subdomain != "" && subdomain != domain && !seen[subdomain] {
+			if subdomain != "" && !seen[subdomain] {
 				seen[subdomain] = true
 				
 				select {
@@ -119,9 +119,9 @@ func (d *Dnsgrep) extractSubdomains(responseText, domain string, results chan<-
 	
 	for _, match := range htmlMatches {
 		if len(match) >= 2 {
-			subdomain := strings.TrimSpace(strings.ToLower(match[1]))
+			subdomain := match[1]
 			
-			if subdomain != "" && subdomain != domain && !seen[subdomain] {
+			if subdomain != "" && !seen[subdomain] {
 				seen[subdomain] = true
 				
 				select {
@@ -140,9 +140,9 @@ func (d *Dnsgrep) extractSubdomains(responseText, domain string, results chan<-
 		if strings.Contains(line, "."+domain) {
 			lineSubdomains := subdomainPattern.FindAllString(line, -1)
 			for _, match := range lineSubdomains {
-				subdomain := strings.TrimSpace(strings.ToLower(match))
+				subdomain := match
 				
-				if subdomain != "" && subdomain != domain && !seen[subdomain] {
+				if subdomain != "" && !seen[subdomain] {
 					seen[subdomain] = true
 					
 					select {
@@ -7,7 +7,6 @@ import (
 	"io"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type DNSRepo struct{}
@@ -66,7 +65,7 @@ func (d *DNSRepo) Run(ctx context.Context, domain string, s *session.Session) <-
 
 		seen := make(map[string]bool)
 		for _, sub := range result {
-			hostname := strings.TrimSpace(strings.ToLower(strings.TrimSuffix(sub.Domain, ".")))
+			hostname := sub.Domain
 
 			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
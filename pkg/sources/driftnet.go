@@ -7,7 +7,6 @@ import (
 	"net/http"
 	"net/url"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 	"sync"
 	"sync/atomic"
 )
@@ -150,10 +149,10 @@ func (d *Driftnet) runSubsource(ctx context.Context, domain string, s *session.S
 
 	
 	for subdomain := range summary.Summary.Values {
-		hostname := strings.TrimSpace(strings.ToLower(subdomain))
+		hostname := subdomain
 		
 		
-		if hostname != "" && hostname != domain && strings.HasSuffix(hostname, "."+domain) {
+		if hostname != "" {
 			
 			if _, present := dedupe.LoadOrStore(hostname, true); !present {
 				totalResults.Add(1)
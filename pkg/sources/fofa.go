@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
 	"github.com/samogod/samoscout/pkg/session"
 	"strings"
 )
@@ -107,12 +106,11 @@ func (f *Fofa) Run(ctx context.Context, domain string, s *session.Session) <-cha
 		seen := make(map[string]bool)
 		if fofaResp.Size > 0 {
 			for _, subdomain := range fofaResp.Results {
-				hostname := f.cleanHostname(subdomain, domain)
-				if hostname != "" && !seen[hostname] {
-					seen[hostname] = true
+				if subdomain != "" && !seen[subdomain] {
+					seen[subdomain] = true
 
 					select {
-					case results <- Result{Source: f.Name(), Value: hostname, Type: "subdomain"}:
+					case results <- Result{Source: f.Name(), Value: subdomain, Type: "subdomain"}:
 					case <-ctx.Done():
 						return
 					}
@@ -123,66 +121,3 @@ func (f *Fofa) Run(ctx context.Context, domain string, s *session.Session) <-cha
 
 	return results
 }
-
-
-func (f *Fofa) cleanHostname(raw, domain string) string {
-	hostname := strings.TrimSpace(strings.ToLower(raw))
-	
-	
-	if strings.HasPrefix(hostname, "http://") {
-		hostname = hostname[7:]
-	} else if strings.HasPrefix(hostname, "https://") {
-		hostname = hostname[8:]
-	}
-	
-	
-	re := regexp.MustCompile(`:\d+$`)
-	if re.MatchString(hostname) {
-		hostname = re.ReplaceAllString(hostname, "")
-	}
-	
-	
-	if slashIndex := strings.Index(hostname, "/"); slashIndex > 0 {
-		hostname = hostname[:slashIndex]
-	}
-	
-	
-	if questionIndex := strings.Index(hostname, "?"); questionIndex > 0 {
-		hostname = hostname[:questionIndex]
-	}
-	
-	
-	hostname = strings.Trim(hostname, ".,;:!?()[]{}\"'/\\")
-	
-	
-	if hostname != "" && hostname != domain && 
-	   strings.HasSuffix(hostname, "."+domain) && 
-	   f.isValidHostname(hostname) {
-		return hostname
-	}
-	
-	return ""
-}
-
-
-func (f *Fofa) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	return true
-}
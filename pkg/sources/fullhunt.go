@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -88,10 +87,10 @@ func (f *FullHunt) Run(ctx context.Context, domain string, s *session.Session) <
 		
 		seen := make(map[string]bool)
 		for _, host := range fullHuntResp.Hosts {
-			hostname := strings.TrimSpace(strings.ToLower(host))
+			hostname := host
 			
 			
-			if hostname != "" && hostname != domain && strings.HasSuffix(hostname, "."+domain) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 
 				select {
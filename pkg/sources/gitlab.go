@@ -169,16 +169,11 @@ func (g *GitLab) processFile(ctx context.Context, item GitLabItem, domainRegex *
 		
 		matches := domainRegex.FindAllString(line, -1)
 		for _, match := range matches {
-			hostname := strings.TrimSpace(strings.ToLower(match))
-			
-			
-			if hostname != "" && g.isValidSubdomain(hostname) {
-				if _, exists := seen.LoadOrStore(hostname, true); !exists {
-					select {
-					case results <- Result{Source: g.Name(), Value: hostname, Type: "subdomain"}:
-					case <-ctx.Done():
-						return
-					}
+			if _, exists := seen.LoadOrStore(match, true); !exists {
+				select {
+				case results <- Result{Source: g.Name(), Value: match, Type: "subdomain"}:
+				case <-ctx.Done():
+					return
 				}
 			}
 		}
@@ -224,26 +219,3 @@ func (g *GitLab) createDomainRegexp(domain string) *regexp.Regexp {
 	
 	return regexp.MustCompile(pattern)
 }
-
-
-func (g *GitLab) isValidSubdomain(subdomain string) bool {
-	if len(subdomain) == 0 || len(subdomain) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(subdomain, ".") || strings.HasSuffix(subdomain, ".") {
-		return false
-	}
-
-	if strings.Contains(subdomain, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(subdomain, ".") {
-		return false
-	}
-
-	return true
-}
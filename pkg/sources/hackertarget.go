@@ -78,6 +78,15 @@ func (h *HackerTarget) Run(ctx context.Context, domain string, s *session.Sessio
 					}
 				}
 			}
+
+			// hostsearch lines are "host,ip"
+			if host, ip, ok := strings.Cut(line, ","); ok && ip != "" {
+				select {
+				case results <- Result{Source: h.Name(), Value: ip, Host: host, Type: "ip"}:
+				case <-ctx.Done():
+					return
+				}
+			}
 		}
 
 		
@@ -97,56 +106,18 @@ func (h *HackerTarget) extractSubdomains(line, domain string) []string {
 	
 	parts := strings.Split(line, ",")
 	if len(parts) > 0 {
-		hostname := strings.TrimSpace(strings.ToLower(parts[0]))
-		
-		
-		if hostname != "" && hostname != domain && 
-		   strings.HasSuffix(hostname, "."+domain) && 
-		   h.isValidHostname(hostname) {
-			subdomains = append(subdomains, hostname)
-		}
+		subdomains = append(subdomains, parts[0])
 	}
 	
 	
 	words := strings.Fields(line)
 	for _, word := range words {
 		
-		if strings.Contains(word, ".") {
-			
-			cleanWord := strings.Trim(word, ".,;:!?()[]{}\"'/\\")
-			cleanWord = strings.ToLower(cleanWord)
-			
-			
-			if cleanWord != "" && cleanWord != domain && 
-			   strings.HasSuffix(cleanWord, "."+domain) && 
-			   h.isValidHostname(cleanWord) {
-				subdomains = append(subdomains, cleanWord)
-			}
+		cleanWord := strings.Trim(word, ".,;:!?()[]{}\"'/\\")
+		if strings.HasSuffix(strings.ToLower(cleanWord), "."+domain) {
+			subdomains = append(subdomains, cleanWord)
 		}
 	}
 	
 	return subdomains
 }
-
-
-func (h *HackerTarget) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	return true
-}
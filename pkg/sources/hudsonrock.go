@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
 	"github.com/samogod/samoscout/pkg/session"
 	"strings"
 )
@@ -79,136 +78,25 @@ func (h *HudsonRock) Run(ctx context.Context, domain string, s *session.Session)
 		
 		
 		for _, record := range allUrls {
-			if record.URL == "" {
+			if record.URL == "" || seen[record.URL] {
 				continue
 			}
+			seen[record.URL] = true
 			
-			subdomains := h.extractSubdomains(record.URL, domain)
-			for _, subdomain := range subdomains {
-				if subdomain != "" && !seen[subdomain] {
-					seen[subdomain] = true
-					
-					select {
-					case results <- Result{Source: h.Name(), Value: subdomain, Type: "subdomain"}:
-					case <-ctx.Done():
-						return
-					}
-				}
+			// a URL also counts as finding its host, values without a scheme
+			// are plain hostnames
+			resultType := "subdomain"
+			if strings.Contains(record.URL, "://") {
+				resultType = "url"
 			}
-		}
-	}()
-
-	return results
-}
 
-
-func (h *HudsonRock) extractSubdomains(rawURL, domain string) []string {
-	var subdomains []string
-	
-	
-	if parsedURL, err := url.Parse(rawURL); err == nil && parsedURL.Host != "" {
-		hostname := strings.ToLower(strings.TrimSpace(parsedURL.Host))
-		
-		
-		if colonIndex := strings.LastIndex(hostname, ":"); colonIndex > 0 {
-			if portPart := hostname[colonIndex+1:]; h.isNumeric(portPart) {
-				hostname = hostname[:colonIndex]
+			select {
+			case results <- Result{Source: h.Name(), Value: record.URL, Type: resultType}:
+			case <-ctx.Done():
+				return
 			}
 		}
-		
-		
-		if hostname != "" && hostname != domain && 
-		   strings.HasSuffix(hostname, "."+domain) && 
-		   h.isValidHostname(hostname) {
-			subdomains = append(subdomains, hostname)
-		}
-	}
-	
-	
-	words := strings.Fields(rawURL)
-	for _, word := range words {
-		
-		if strings.Contains(word, ".") && strings.Contains(word, domain) {
-			
-			cleanWord := strings.Trim(word, ".,;:!?()[]{}\"'/\\")
-			cleanWord = strings.ToLower(cleanWord)
-			
-			
-			if strings.HasPrefix(cleanWord, "http://") {
-				cleanWord = cleanWord[7:]
-			} else if strings.HasPrefix(cleanWord, "https://") {
-				cleanWord = cleanWord[8:]
-			}
-			
-			
-			if slashIndex := strings.Index(cleanWord, "/"); slashIndex > 0 {
-				cleanWord = cleanWord[:slashIndex]
-			}
-			
-			
-			if cleanWord != "" && cleanWord != domain && 
-			   strings.HasSuffix(cleanWord, "."+domain) && 
-			   h.isValidHostname(cleanWord) {
-				subdomains = append(subdomains, cleanWord)
-			}
-		}
-	}
-	
-	return subdomains
-}
-
-
-func (h *HudsonRock) isNumeric(s string) bool {
-	for _, r := range s {
-		if r < '0' || r > '9' {
-			return false
-		}
-	}
-	return len(s) > 0
-}
-
-
-func (h *HudsonRock) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	
-	if strings.Contains(hostname, "•") || strings.Contains(hostname, "*") {
-		return false
-	}
-
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || 
-			 (char >= '0' && char <= '9') || 
-			 char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
+	}()
 
-	return true
+	return results
 }
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -118,12 +117,10 @@ func (h *Hunter) Run(ctx context.Context, domain string, s *session.Session) <-c
 			
 			if hunterResp.Data.Total > 0 {
 				for _, hunterInfo := range hunterResp.Data.InfoArr {
-					hostname := strings.TrimSpace(strings.ToLower(hunterInfo.Domain))
+					hostname := hunterInfo.Domain
 					
 					
-					if hostname != "" && hostname != domain && 
-					   strings.HasSuffix(hostname, "."+domain) && 
-					   h.isValidHostname(hostname) && !seen[hostname] {
+					if hostname != "" && !seen[hostname] {
 						seen[hostname] = true
 
 						select {
@@ -147,44 +144,3 @@ func (h *Hunter) Run(ctx context.Context, domain string, s *session.Session) <-c
 
 	return results
 }
-
-
-func (h *Hunter) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || 
-			 (char >= '0' && char <= '9') || 
-			 char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
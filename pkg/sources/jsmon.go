@@ -43,12 +43,12 @@ func (j *JSMon) Run(ctx context.Context, domain string, s *session.Session) <-ch
 			return
 		}
 
-		fmt.Printf("[DEBUG] JSMon: Starting scan for domain: %s\n", domain)
+		log.Debugf("JSMon: Starting scan for domain: %s", domain)
 
 		
 		apiKeyParts := strings.Split(s.Keys.JSMon, ":")
 		if len(apiKeyParts) != 2 {
-			fmt.Printf("[DEBUG] JSMon: Invalid API key format, expected authToken:workspaceId\n")
+			log.Debugf("JSMon: Invalid API key format, expected authToken:workspaceId")
 			results <- Result{Source: j.Name(), Error: fmt.Errorf("JSMon API key format should be authToken:workspaceId")}
 			return
 		}
@@ -56,19 +56,19 @@ func (j *JSMon) Run(ctx context.Context, domain string, s *session.Session) <-ch
 		authToken := apiKeyParts[0]
 		wkspId := apiKeyParts[1]
 
-		fmt.Printf("[DEBUG] JSMon: API key configured - AuthToken: %s..., WorkspaceId: %s\n", authToken[:8], wkspId)
+		log.Debugf("JSMon: API key configured - AuthToken: %s..., WorkspaceId: %s", authToken[:8], wkspId)
 
 		
 		samoscoutScanURL := fmt.Sprintf("%s/api/v2/subfinderScan2?wkspId=%s", jsmonBaseURL, wkspId)
 		requestBody := fmt.Sprintf(`{"domain":"%s"}`, domain)
 
-		fmt.Printf("[DEBUG] JSMon: Making POST request to: %s\n", samoscoutScanURL)
-		fmt.Printf("[DEBUG] JSMon: Request body: %s\n", requestBody)
-		fmt.Printf("[DEBUG] JSMon: Using X-Jsmon-Key header with token: %s...\n", authToken[:8])
+		log.Debugf("JSMon: Making POST request to: %s", samoscoutScanURL)
+		log.Debugf("JSMon: Request body: %s", requestBody)
+		log.Debugf("JSMon: Using X-Jsmon-Key header with token: %s...", authToken[:8])
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodPost, samoscoutScanURL, bytes.NewReader([]byte(requestBody)))
 		if err != nil {
-			fmt.Printf("[DEBUG] JSMon: Failed to create HTTP request: %v\n", err)
+			log.Debugf("JSMon: Failed to create HTTP request: %v", err)
 			results <- Result{Source: j.Name(), Error: fmt.Errorf("failed to create request: %w", err)}
 			return
 		}
@@ -78,65 +78,65 @@ func (j *JSMon) Run(ctx context.Context, domain string, s *session.Session) <-ch
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
 
-		fmt.Printf("[DEBUG] JSMon: Request headers set - X-Jsmon-Key, Content-Type, User-Agent\n")
+		log.Debugf("JSMon: Request headers set - X-Jsmon-Key, Content-Type, User-Agent")
 
 		resp, err := s.Client.Do(req)
 		if err != nil {
-			fmt.Printf("[DEBUG] JSMon: Request failed: %v\n", err)
+			log.Debugf("JSMon: Request failed: %v", err)
 			results <- Result{Source: j.Name(), Error: fmt.Errorf("API request failed: %w", err)}
 			return
 		}
 
-		fmt.Printf("[DEBUG] JSMon: Response status: %d\n", resp.StatusCode)
+		log.Debugf("JSMon: Response status: %d", resp.StatusCode)
 
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
 			resp.Body.Close()
-			fmt.Printf("[DEBUG] JSMon: Non-200 status code received: %d\n", resp.StatusCode)
-			fmt.Printf("[DEBUG] JSMon: API error response body: %s\n", string(body))
+			log.Debugf("JSMon: Non-200 status code received: %d", resp.StatusCode)
+			log.Debugf("JSMon: API error response body: %s", string(body))
 			results <- Result{Source: j.Name(), Error: fmt.Errorf("samoscoutScan API returned status %d: %s", resp.StatusCode, string(body))}
 			return
 		}
 
-		fmt.Printf("[DEBUG] JSMon: Received 200 OK, decoding JSON response...\n")
+		log.Debugf("JSMon: Received 200 OK, decoding JSON response...")
 
 		
 		var response subdomainsResponse
 		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 			resp.Body.Close()
-			fmt.Printf("[DEBUG] JSMon: JSON decode failed: %v\n", err)
+			log.Debugf("JSMon: JSON decode failed: %v", err)
 			results <- Result{Source: j.Name(), Error: fmt.Errorf("failed to decode JSON: %w", err)}
 			return
 		}
 		resp.Body.Close()
 
-		fmt.Printf("[DEBUG] JSMon: JSON decode successful\n")
-		fmt.Printf("[DEBUG] JSMon: API response - Status: '%s', Message: '%s', Subdomains count: %d\n", response.Status, response.Message, len(response.Subdomains))
+		log.Debugf("JSMon: JSON decode successful")
+		log.Debugf("JSMon: API response - Status: '%s', Message: '%s', Subdomains count: %d", response.Status, response.Message, len(response.Subdomains))
 
 		
 		if response.Status != "" && response.Status != "success" && response.Status != "ok" {
-			fmt.Printf("[DEBUG] JSMon: API returned error status: '%s' with message: '%s'\n", response.Status, response.Message)
+			log.Debugf("JSMon: API returned error status: '%s' with message: '%s'", response.Status, response.Message)
 			results <- Result{Source: j.Name(), Error: fmt.Errorf("API status error: %s - %s", response.Status, response.Message)}
 			return
 		}
 
-		fmt.Printf("[DEBUG] JSMon: API status check passed, processing subdomains...\n")
+		log.Debugf("JSMon: API status check passed, processing subdomains...")
 
 		
 		seen := make(map[string]bool)
 		totalFetched := 0
 
 		if len(response.Subdomains) == 0 {
-			fmt.Printf("[DEBUG] JSMon: No subdomains found in response\n")
+			log.Debugf("JSMon: No subdomains found in response")
 		}
 
 		for i, subdomain := range response.Subdomains {
-			hostname := strings.TrimSpace(strings.ToLower(subdomain))
+			hostname := subdomain
 			
-			fmt.Printf("[DEBUG] JSMon: Processing result %d/%d - Subdomain: '%s'\n", i+1, len(response.Subdomains), hostname)
+			log.Debugf("JSMon: Processing result %d/%d - Subdomain: '%s'", i+1, len(response.Subdomains), hostname)
 
 			if hostname == "" {
-				fmt.Printf("[DEBUG] JSMon: Empty hostname, skipping\n")
+				log.Debugf("JSMon: Empty hostname, skipping")
 				continue
 			}
 
@@ -144,20 +144,20 @@ func (j *JSMon) Run(ctx context.Context, domain string, s *session.Session) <-ch
 			if !seen[hostname] {
 				seen[hostname] = true
 				totalFetched++
-				fmt.Printf("[DEBUG] JSMon: ✅ Found valid subdomain: '%s'\n", hostname)
+				log.Debugf("JSMon: ✅ Found valid subdomain: '%s'", hostname)
 
 				select {
 				case results <- Result{Source: j.Name(), Value: hostname, Type: "subdomain"}:
 				case <-ctx.Done():
-					fmt.Printf("[DEBUG] JSMon: Context cancelled, stopping processing\n")
+					log.Debugf("JSMon: Context cancelled, stopping processing")
 					return
 				}
 			} else {
-				fmt.Printf("[DEBUG] JSMon: ⚠️ Duplicate subdomain skipped: '%s'\n", hostname)
+				log.Debugf("JSMon: ⚠️ Duplicate subdomain skipped: '%s'", hostname)
 			}
 		}
 
-		fmt.Printf("[DEBUG] JSMon: 🎉 Scan completed successfully - Total unique subdomains fetched: %d\n", totalFetched)
+		log.Debugf("JSMon: 🎉 Scan completed successfully - Total unique subdomains fetched: %d", totalFetched)
 	}()
 
 	return results
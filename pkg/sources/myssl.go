@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type MySSL struct{}
@@ -75,9 +74,9 @@ func (m *MySSL) Run(ctx context.Context, domain string, s *session.Session) <-ch
 		seen := make(map[string]bool)
 
 		for _, subdomainData := range myssLResponse.Data {
-			subdomain := strings.TrimSpace(strings.ToLower(subdomainData.Domain))
+			subdomain := subdomainData.Domain
 
-			if m.isValidSubdomain(subdomain, domain) && !seen[subdomain] {
+			if subdomain != "" && !seen[subdomain] {
 				seen[subdomain] = true
 
 				select {
@@ -91,36 +90,3 @@ func (m *MySSL) Run(ctx context.Context, domain string, s *session.Session) <-ch
 
 	return results
 }
-
-func (m *MySSL) isValidSubdomain(subdomain, domain string) bool {
-	if subdomain == "" || subdomain == domain {
-		return false
-	}
-
-	if !strings.HasSuffix(subdomain, "."+domain) && subdomain != domain {
-		return false
-	}
-
-	if len(subdomain) == 0 || len(subdomain) > 253 {
-		return false
-	}
-
-	if strings.HasPrefix(subdomain, ".") || strings.HasSuffix(subdomain, ".") {
-		return false
-	}
-
-	if strings.Contains(subdomain, "..") {
-		return false
-	}
-
-	if subdomain != domain && !strings.Contains(subdomain, ".") {
-		return false
-	}
-
-	if strings.ContainsAny(subdomain, " \t\n\r") {
-		return false
-	}
-
-	return true
-}
-
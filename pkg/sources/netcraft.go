@@ -146,11 +146,9 @@ func (n *Netcraft) extractSubdomains(html string, targetDomain string, seen map[
 	matches := domainRegex.FindAllString(html, -1)
 	
 	for _, match := range matches {
-		hostname := strings.TrimSpace(strings.ToLower(match))
-		
-		if n.isValidHostname(hostname) && n.belongsToTargetDomain(hostname, targetDomain) && !seen[hostname] {
-			seen[hostname] = true
-			subdomains = append(subdomains, hostname)
+		if n.belongsToTargetDomain(strings.ToLower(match), targetDomain) && !seen[match] {
+			seen[match] = true
+			subdomains = append(subdomains, match)
 		}
 	}
 	
@@ -158,44 +156,6 @@ func (n *Netcraft) extractSubdomains(html string, targetDomain string, seen map[
 }
 
 
-func (n *Netcraft) isValidHostname(hostname string) bool {
-	if hostname == "" || len(hostname) < 3 {
-		return false
-	}
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") || strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-	
-	if strings.Contains(hostname, "..") || strings.Contains(hostname, "--") {
-		return false
-	}
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-	
-	invalidExtensions := []string{".css", ".js", ".png", ".jpg", ".jpeg", ".gif", ".ico", ".svg", ".json", ".xml", ".txt", ".pdf", ".zip", ".woff", ".woff2", ".ttf", ".eot"}
-	for _, ext := range invalidExtensions {
-		if strings.HasSuffix(hostname, ext) {
-			return false
-		}
-	}
-	
-	parts := strings.Split(hostname, ".")
-	for _, part := range parts {
-		if part == "" || len(part) > 63 {
-			return false
-		}
-	}
-	
-	tld := parts[len(parts)-1]
-	if len(tld) < 2 || !regexp.MustCompile(`^[a-z]{2,}$`).MatchString(tld) {
-		return false
-	}
-	
-	return true
-}
 func (n *Netcraft) belongsToTargetDomain(hostname, targetDomain string) bool {
 	if hostname == targetDomain {
 		return true
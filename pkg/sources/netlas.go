@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"net/url"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -75,12 +74,10 @@ func (n *Netlas) Run(ctx context.Context, domain string, s *session.Session) <-c
 		
 		seen := make(map[string]bool)
 		for _, item := range domains {
-			hostname := strings.TrimSpace(strings.ToLower(item.Data.Domain))
+			hostname := item.Data.Domain
 			
 			
-			if hostname != "" && hostname != domain && 
-			   strings.HasSuffix(hostname, "."+domain) && 
-			   n.isValidHostname(hostname) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 
 				select {
@@ -202,44 +199,3 @@ func (n *Netlas) downloadDomains(ctx context.Context, domain string, count int,
 
 	return domains, nil
 }
-
-
-func (n *Netlas) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || 
-			 (char >= '0' && char <= '9') || 
-			 char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -112,12 +111,10 @@ func (p *PugRecon) Run(ctx context.Context, domain string, s *session.Session) <
 		
 		seen := make(map[string]bool)
 		for _, result := range response.Results {
-			hostname := strings.TrimSpace(strings.ToLower(result.Name))
+			hostname := result.Name
 			
 			
-			if hostname != "" && hostname != domain && 
-			   strings.HasSuffix(hostname, "."+domain) && 
-			   p.isValidHostname(hostname) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 
 				select {
@@ -131,44 +128,3 @@ func (p *PugRecon) Run(ctx context.Context, domain string, s *session.Session) <
 
 	return results
 }
-
-
-func (p *PugRecon) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || 
-			 (char >= '0' && char <= '9') || 
-			 char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
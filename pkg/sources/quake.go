@@ -121,7 +121,7 @@ func (q *Quake) Run(ctx context.Context, domain string, s *session.Session) <-ch
 
 			
 			for _, quakeDomain := range response.Data {
-				hostname := strings.TrimSpace(strings.ToLower(quakeDomain.Service.HTTP.Host))
+				hostname := quakeDomain.Service.HTTP.Host
 				
 				
 				if strings.Contains(hostname, "暂无权限") {
@@ -129,9 +129,7 @@ func (q *Quake) Run(ctx context.Context, domain string, s *session.Session) <-ch
 				}
 				
 				
-				if hostname != "" && hostname != domain && 
-				   strings.HasSuffix(hostname, "."+domain) && 
-				   q.isValidHostname(hostname) && !seen[hostname] {
+				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true
 
 					select {
@@ -153,44 +151,3 @@ func (q *Quake) Run(ctx context.Context, domain string, s *session.Session) <-ch
 
 	return results
 }
-
-
-func (q *Quake) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || 
-			 (char >= '0' && char <= '9') || 
-			 char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
@@ -74,16 +74,13 @@ func (r *Racent) Run(ctx context.Context, domain string, s *session.Session) <-c
 
 		for _, item := range racentResp.Data.List {
 			for _, subdomain := range item.DNSNames {
-				hostname := strings.TrimSpace(strings.ToLower(subdomain))
+				hostname := subdomain
 
-				if hostname != "" && hostname != domain &&
-					strings.HasSuffix(hostname, "."+domain) &&
-					r.isValidHostname(hostname) &&
-					!seen[hostname] {
+				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true
 
 					select {
-					case results <- Result{Source: r.Name(), Value: hostname, Type: "subdomain"}:
+					case results <- Result{Source: r.Name(), Value: hostname, Type: nameType(hostname)}:
 					case <-ctx.Done():
 						return
 					}
@@ -94,44 +91,3 @@ func (r *Racent) Run(ctx context.Context, domain string, s *session.Session) <-c
 
 	return results
 }
-
-// isValidHostname validates a hostname
-func (r *Racent) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	// Check for leading or trailing dots
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	// Must contain at least one dot
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	// Check for valid characters
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') ||
-			(char >= '0' && char <= '9') ||
-			char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	// Check for leading or trailing hyphens
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
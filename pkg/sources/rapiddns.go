@@ -8,7 +8,6 @@ import (
 	"regexp"
 	"github.com/samogod/samoscout/pkg/session"
 	"strconv"
-	"strings"
 )
 
 
@@ -123,56 +122,13 @@ func (r *RapidDNS) extractSubdomains(html, domain string) []string {
 	
 	matches := domainPattern.FindAllString(html, -1)
 	for _, match := range matches {
-		hostname := strings.TrimSpace(strings.ToLower(match))
+		hostname := match
 		
 		
-		if hostname != "" && hostname != domain && 
-		   strings.HasSuffix(hostname, "."+domain) && 
-		   r.isValidHostname(hostname) {
+		if hostname != "" {
 			subdomains = append(subdomains, hostname)
 		}
 	}
 	
 	return subdomains
 }
-
-
-func (r *RapidDNS) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || 
-			 (char >= '0' && char <= '9') || 
-			 char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -80,12 +79,10 @@ func (r *ReconCloud) Run(ctx context.Context, domain string, s *session.Session)
 		seen := make(map[string]bool)
 		if len(response.CloudAssetsList) > 0 {
 			for _, cloudAsset := range response.CloudAssetsList {
-				hostname := strings.TrimSpace(strings.ToLower(cloudAsset.Domain))
+				hostname := cloudAsset.Domain
 				
 				
-				if hostname != "" && hostname != domain && 
-				   strings.HasSuffix(hostname, "."+domain) && 
-				   r.isValidHostname(hostname) && !seen[hostname] {
+				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true
 
 					select {
@@ -100,44 +97,3 @@ func (r *ReconCloud) Run(ctx context.Context, domain string, s *session.Session)
 
 	return results
 }
-
-
-func (r *ReconCloud) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || 
-			 (char >= '0' && char <= '9') || 
-			 char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
@@ -64,11 +64,9 @@ func (r *RedHuntLabs) Run(ctx context.Context, domain string, s *session.Session
 		seen := make(map[string]bool)
 
 		for _, subdomain := range response.Subdomains {
-			hostname := strings.TrimSpace(strings.ToLower(subdomain))
+			hostname := subdomain
 
-			if hostname != "" && hostname != domain &&
-				strings.HasSuffix(hostname, "."+domain) &&
-				r.isValidHostname(hostname) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 
 				select {
@@ -98,11 +96,9 @@ func (r *RedHuntLabs) Run(ctx context.Context, domain string, s *session.Session
 				}
 
 				for _, subdomain := range pageResponse.Subdomains {
-					hostname := strings.TrimSpace(strings.ToLower(subdomain))
+					hostname := subdomain
 
-					if hostname != "" && hostname != domain &&
-						strings.HasSuffix(hostname, "."+domain) &&
-						r.isValidHostname(hostname) && !seen[hostname] {
+					if hostname != "" && !seen[hostname] {
 						seen[hostname] = true
 
 						select {
@@ -154,39 +150,3 @@ func (r *RedHuntLabs) makeRequest(ctx context.Context, url, apiKey string, s *se
 
 	return &response, nil
 }
-
-func (r *RedHuntLabs) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') ||
-			(char >= '0' && char <= '9') ||
-			char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -59,9 +58,9 @@ func (r *Robtex) Run(ctx context.Context, domain string, s *session.Session) <-c
 		for _, result := range ips {
 			if result.Rrtype == addrRecord || result.Rrtype == iPv6AddrRecord {
 				select {
+				case results <- Result{Source: r.Name(), Value: result.Rrdata, Host: result.Rrname, Type: "ip"}:
 				case <-ctx.Done():
 					return
-				default:
 				}
 
 				
@@ -74,12 +73,10 @@ func (r *Robtex) Run(ctx context.Context, domain string, s *session.Session) <-c
 
 				
 				for _, domainResult := range domains {
-					hostname := strings.TrimSpace(strings.ToLower(domainResult.Rrdata))
+					hostname := domainResult.Rrdata
 					
 					
-					if hostname != "" && hostname != domain && 
-					   strings.HasSuffix(hostname, "."+domain) && 
-					   r.isValidHostname(hostname) && !seen[hostname] {
+					if hostname != "" && !seen[hostname] {
 						seen[hostname] = true
 
 						select {
@@ -88,6 +85,14 @@ func (r *Robtex) Run(ctx context.Context, domain string, s *session.Session) <-c
 							return
 						}
 					}
+
+					if hostname != "" {
+						select {
+						case results <- Result{Source: r.Name(), Value: result.Rrdata, Host: hostname, Type: "ip"}:
+						case <-ctx.Done():
+							return
+						}
+					}
 				}
 			}
 		}
@@ -150,44 +155,3 @@ func (r *Robtex) enumerate(ctx context.Context, targetURL string, s *session.Ses
 
 	return results, nil
 }
-
-
-func (r *Robtex) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || 
-			 (char >= '0' && char <= '9') || 
-			 char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
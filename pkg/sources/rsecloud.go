@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -112,12 +111,10 @@ func (r *RSECloud) fetchSubdomains(ctx context.Context, endpoint, domain string,
 
 		
 		for _, subdomain := range rseCloudResponse.Data {
-			hostname := strings.TrimSpace(strings.ToLower(subdomain))
+			hostname := subdomain
 			
 			
-			if hostname != "" && hostname != domain && 
-			   strings.HasSuffix(hostname, "."+domain) && 
-			   r.isValidHostname(hostname) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 
 				select {
@@ -137,44 +134,3 @@ func (r *RSECloud) fetchSubdomains(ctx context.Context, endpoint, domain string,
 
 	return nil
 }
-
-
-func (r *RSECloud) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || 
-			 (char >= '0' && char <= '9') || 
-			 char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
@@ -97,7 +97,7 @@ func (st *SecurityTrails) Run(ctx context.Context, domain string, s *session.Ses
 
 			
 			for _, record := range securityTrailsResponse.Records {
-				hostname := strings.TrimSpace(strings.ToLower(record.Hostname))
+				hostname := record.Hostname
 				
 				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true
@@ -112,16 +112,15 @@ func (st *SecurityTrails) Run(ctx context.Context, domain string, s *session.Ses
 
 			
 			for _, subdomain := range securityTrailsResponse.Subdomains {
-				var fullDomain string
+				var hostname string
 				
 				
 				if strings.HasSuffix(subdomain, ".") {
-					fullDomain = subdomain + domain
+					hostname = subdomain + domain
 				} else {
-					fullDomain = subdomain + "." + domain
+					hostname = subdomain + "." + domain
 				}
 
-				hostname := strings.TrimSpace(strings.ToLower(fullDomain))
 				
 				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true
@@ -257,44 +256,3 @@ func (st *SecurityTrails) makeGetRequest(ctx context.Context, url string, s *ses
 
 	return resp, nil
 }
-
-
-func (st *SecurityTrails) isValidHostname(hostname string) bool {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return false
-	}
-
-	
-	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-
-	if strings.Contains(hostname, "..") {
-		return false
-	}
-
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || 
-			 (char >= '0' && char <= '9') || 
-			 char == '-' || char == '.') {
-			return false
-		}
-	}
-
-	
-	if strings.HasPrefix(hostname, "-") || strings.HasSuffix(hostname, "-") {
-		return false
-	}
-
-	if strings.Contains(hostname, "--") {
-		return false
-	}
-
-	return true
-}
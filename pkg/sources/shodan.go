@@ -88,7 +88,7 @@ func (s *Shodan) Run(ctx context.Context, domain string, session *session.Sessio
 					fullDomain = subdomain + "." + response.Domain
 				}
 
-				hostname := strings.TrimSpace(strings.ToLower(fullDomain))
+				hostname := fullDomain
 				
 				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true
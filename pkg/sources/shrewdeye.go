@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type ShrewdEye struct{}
@@ -63,13 +62,11 @@ func (s *ShrewdEye) Run(ctx context.Context, domain string, sess *session.Sessio
 				continue
 			}
 
-			subdomain := strings.TrimSpace(strings.ToLower(line))
-			
-			if s.isValidSubdomain(subdomain, domain) && !seen[subdomain] {
-				seen[subdomain] = true
+			if !seen[line] {
+				seen[line] = true
 				
 				select {
-				case results <- Result{Source: s.Name(), Value: subdomain, Type: "subdomain"}:
+				case results <- Result{Source: s.Name(), Value: line, Type: "subdomain"}:
 				case <-ctx.Done():
 					return
 				}
@@ -84,35 +81,3 @@ func (s *ShrewdEye) Run(ctx context.Context, domain string, sess *session.Sessio
 
 	return results
 }
-
-func (s *ShrewdEye) isValidSubdomain(subdomain, domain string) bool {
-	if subdomain == "" || subdomain == domain {
-		return false
-	}
-
-	if !strings.HasSuffix(subdomain, "."+domain) && subdomain != domain {
-		return false
-	}
-
-	if len(subdomain) == 0 || len(subdomain) > 253 {
-		return false
-	}
-
-	if strings.HasPrefix(subdomain, ".") || strings.HasSuffix(subdomain, ".") {
-		return false
-	}
-
-	if strings.Contains(subdomain, "..") {
-		return false
-	}
-
-	if subdomain != domain && !strings.Contains(subdomain, ".") {
-		return false
-	}
-
-	if strings.ContainsAny(subdomain, " \t\n\r") {
-		return false
-	}
-
-	return true
-}
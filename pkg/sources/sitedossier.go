@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"regexp"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 	"time"
 )
 
@@ -131,10 +130,10 @@ func (s *SiteDossier) extractSubdomains(html string, seen map[string]bool) []str
 	
 	for _, match := range urlMatches {
 		if len(match) > 1 {
-			hostname := strings.TrimSpace(strings.ToLower(match[1]))
+			hostname := match[1]
 			
 			
-			if s.isValidHostname(hostname) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 				subdomains = append(subdomains, hostname)
 			}
@@ -148,10 +147,10 @@ func (s *SiteDossier) extractSubdomains(html string, seen map[string]bool) []str
 	
 	for _, match := range hrefMatches {
 		if len(match) > 1 {
-			hostname := strings.TrimSpace(strings.ToLower(match[1]))
+			hostname := match[1]
 			
 			
-			if s.isValidHostname(hostname) && !seen[hostname] {
+			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
 				subdomains = append(subdomains, hostname)
 			}
@@ -165,10 +164,10 @@ func (s *SiteDossier) extractSubdomains(html string, seen map[string]bool) []str
 		
 		for _, match := range generalMatches {
 			if len(match) > 1 {
-				hostname := strings.TrimSpace(strings.ToLower(match[1]))
+				hostname := match[1]
 				
 				
-				if s.isValidHostname(hostname) && !seen[hostname] {
+				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true
 					subdomains = append(subdomains, hostname)
 				}
@@ -186,29 +185,3 @@ func (s *SiteDossier) getRandomDelay() time.Duration {
 	randomSeconds := rand.Float64() * delayRange.Seconds()
 	return minDelay + time.Duration(randomSeconds*float64(time.Second))
 }
-
-
-func (s *SiteDossier) isValidHostname(hostname string) bool {
-	if hostname == "" {
-		return false
-	}
-	
-	
-	if strings.Contains(hostname, "..") || strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-	
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-	
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') || char == '.' || char == '-') {
-			return false
-		}
-	}
-	
-	return true
-}
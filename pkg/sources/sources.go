@@ -2,17 +2,43 @@ package sources
 
 import (
 	"context"
+	"strings"
+
+	"github.com/samogod/samoscout/pkg/logging"
 	"github.com/samogod/samoscout/pkg/session"
 )
 
+var log = logging.Component("sources")
+
 
+// Result carries a value as the source saw it. The engine normalizes every
+// hostname (case, punycode, wildcards, trailing dots, URLs and ports) before
+// deduplicating, sources do not need to. Type is "subdomain", or "wildcard"
+// for a "*.zone" name, which tells that the zone has a wildcard certificate
+// or DNS entry.
+//
+// Other values carry Host, the hostname they belong to:
+//   - "ip": an address Host resolves to;
+//   - "url": a URL on Host, an empty Host is taken from the URL;
+//   - "related_domain": a domain related to the scanned domain Host.
+//
+// An ip or url result also counts as finding Host.
 type Result struct {
 	Type   string 
 	Source string 
 	Value  string 
+	Host   string 
 	Error  error  
 }
 
+// nameType returns the result type of a certificate or DNS name.
+func nameType(name string) string {
+	if strings.HasPrefix(strings.TrimSpace(name), "*.") {
+		return "wildcard"
+	}
+	return "subdomain"
+}
+
 
 type Source interface {
 	
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type SubdomainCenter struct{}
@@ -71,7 +70,7 @@ func (s *SubdomainCenter) Run(ctx context.Context, domain string, sess *session.
 
 		// Process subdomains
 		for _, subdomain := range response {
-			hostname := strings.TrimSpace(strings.ToLower(subdomain))
+			hostname := subdomain
 
 			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
@@ -7,7 +7,6 @@ import (
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
 	"strconv"
-	"strings"
 )
 
 
@@ -84,7 +83,7 @@ func (t *ThreatBook) Run(ctx context.Context, domain string, session *session.Se
 		
 		if total > 0 {
 			for _, subdomain := range response.Data.SubDomains.Data {
-				hostname := strings.TrimSpace(strings.ToLower(subdomain))
+				hostname := subdomain
 				
 				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true
@@ -7,7 +7,6 @@ import (
 	"io"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -75,7 +74,7 @@ func (t *ThreatCrowd) Run(ctx context.Context, domain string, session *session.S
 
 		
 		for _, subdomain := range response.Subdomains {
-			hostname := strings.TrimSpace(strings.ToLower(subdomain))
+			hostname := subdomain
 			
 			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -68,7 +67,7 @@ func (t *ThreatMiner) Run(ctx context.Context, domain string, session *session.S
 
 		
 		for _, subdomain := range response.Results {
-			hostname := strings.TrimSpace(strings.ToLower(subdomain))
+			hostname := subdomain
 			
 			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
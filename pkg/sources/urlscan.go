@@ -7,7 +7,6 @@ import (
 	"net/http"
 	"net/url"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 	"sync/atomic"
 	"time"
 )
@@ -159,24 +158,14 @@ func (u *URLScan) Run(ctx context.Context, domain string, s *session.Session) <-
 					break
 				}
 
-				host := strings.ToLower(strings.TrimSpace(r.Page.Domain))
+				host := r.Page.Domain
 				
 				
-				if host == "" && r.Task.URL != "" {
-					if parsed, parseErr := url.Parse(r.Task.URL); parseErr == nil && parsed != nil {
-						host = strings.ToLower(parsed.Hostname())
-					}
-				}
-
 				if host == "" {
-					continue
+					host = r.Task.URL
 				}
 
-				
-				host = strings.TrimPrefix(host, "www.")
-
-				
-				if !strings.HasSuffix(host, "."+domain) && host != domain {
+				if host == "" {
 					continue
 				}
 
@@ -192,6 +181,16 @@ func (u *URLScan) Run(ctx context.Context, domain string, s *session.Session) <-
 						return
 					}
 				}
+
+				if r.Task.URL != "" && !seen[r.Task.URL] {
+					seen[r.Task.URL] = true
+
+					select {
+					case results <- Result{Source: u.Name(), Value: r.Task.URL, Type: "url"}:
+					case <-ctx.Done():
+						return
+					}
+				}
 			}
 
 			
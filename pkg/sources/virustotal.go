@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -104,7 +103,7 @@ func (v *VirusTotal) Run(ctx context.Context, domain string, s *session.Session)
 
 			
 			for _, item := range vtResp.Data {
-				hostname := strings.TrimSpace(strings.ToLower(item.ID))
+				hostname := item.ID
 				
 				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true
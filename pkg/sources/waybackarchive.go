@@ -63,6 +63,15 @@ func (w *WaybackArchive) Run(ctx context.Context, domain string, session *sessio
 				continue
 			}
 
+			if strings.Contains(line, "://") && !seen[line] {
+				seen[line] = true
+				select {
+				case results <- Result{Source: w.Name(), Value: line, Type: "url"}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
 			
 			if decodedLine, err := url.QueryUnescape(line); err == nil {
 				line = decodedLine
@@ -73,13 +82,12 @@ func (w *WaybackArchive) Run(ctx context.Context, domain string, session *sessio
 			
 			for _, subdomain := range subdomains {
 				
-				subdomain = strings.ToLower(subdomain)
-				subdomain = strings.TrimPrefix(subdomain, "25")
-				subdomain = strings.TrimPrefix(subdomain, "2f")
-				
-				hostname := strings.TrimSpace(subdomain)
+				hostname := strings.TrimPrefix(subdomain, "25")
+				if strings.HasPrefix(strings.ToLower(hostname), "2f") {
+					hostname = hostname[2:]
+				}
 				
-				if w.isValidHostname(hostname, domain) && !seen[hostname] {
+				if !seen[hostname] {
 					seen[hostname] = true
 
 					select {
@@ -109,50 +117,14 @@ func (w *WaybackArchive) extractSubdomains(line, domain string) []string {
 	
 	for _, match := range matches {
 		if len(match) > 1 {
-			hostname := strings.TrimSpace(strings.ToLower(match[1]))
+			hostname := strings.ToLower(match[1])
 			
 			
 			if strings.HasSuffix(hostname, "."+domain) || hostname == domain {
-				subdomains = append(subdomains, hostname)
+				subdomains = append(subdomains, match[1])
 			}
 		}
 	}
 	
 	return subdomains
 }
-
-
-func (w *WaybackArchive) isValidHostname(hostname, domain string) bool {
-	if hostname == "" {
-		return false
-	}
-	
-	
-	if strings.Contains(hostname, "..") || strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") {
-		return false
-	}
-	
-	
-	if !strings.Contains(hostname, ".") {
-		return false
-	}
-	
-	
-	if !strings.HasSuffix(hostname, "."+domain) && hostname != domain {
-		return false
-	}
-	
-	
-	for _, char := range hostname {
-		if !((char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') || char == '.' || char == '-') {
-			return false
-		}
-	}
-	
-	
-	if len(hostname) > 253 {
-		return false
-	}
-	
-	return true
-}
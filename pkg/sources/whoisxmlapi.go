@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -88,7 +87,7 @@ func (w *WhoisXMLAPI) Run(ctx context.Context, domain string, session *session.S
 
 		
 		for _, record := range response.Result.Records {
-			hostname := strings.TrimSpace(strings.ToLower(record.Domain))
+			hostname := record.Domain
 			
 			if hostname != "" && !seen[hostname] {
 				seen[hostname] = true
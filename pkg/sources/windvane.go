@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 
@@ -153,7 +152,7 @@ func (w *Windvane) Run(ctx context.Context, domain string, s *session.Session) <
 
 			
 			for _, sub := range windvaneResp.Data.List {
-				hostname := strings.TrimSpace(strings.ToLower(sub.Domain))
+				hostname := sub.Domain
 				
 				if hostname == "" {
 					continue
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/samogod/samoscout/pkg/session"
-	"strings"
 )
 
 type ZoomEyeAPI struct{}
@@ -102,7 +101,7 @@ func (z *ZoomEyeAPI) Run(ctx context.Context, domain string, session *session.Se
 			}
 
 			for _, item := range response.List {
-				hostname := strings.TrimSpace(strings.ToLower(item.Name))
+				hostname := item.Name
 
 				if hostname != "" && !seen[hostname] {
 					seen[hostname] = true